@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/datablast-analytics/blast/pkg/date"
+	"github.com/datablast-analytics/blast/pkg/history"
+	"github.com/datablast-analytics/blast/pkg/report"
+	"github.com/datablast-analytics/blast/pkg/scheduler"
+	"github.com/google/uuid"
+	"github.com/spf13/afero"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/sync/errgroup"
+)
+
+// runBackfill executes the pipeline once for every interval of its schedule within the given range, each interval is a
+// separate run with its own ID so that the failed ones can be resumed individually.
+func runBackfill(ctx context.Context, params *runParameters, startDate, endDate time.Time, parallelism int) error {
+	if params.previousRun != nil {
+		errorPrinter.Println("The '--backfill' flag cannot be used together with '--resume', please resume the runs of the failed intervals individually.")
+		return cli.Exit("", 1)
+	}
+
+	intervals, err := date.SplitIntoIntervals(string(params.pipeline.Schedule), startDate, endDate)
+	if err != nil {
+		errorPrinter.Printf("Failed to split the range into intervals: %v\n", err)
+		return cli.Exit("", 1)
+	}
+
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	params.trigger = history.TriggerBackfill
+
+	infoPrinter.Printf("\nBackfilling %d intervals of the schedule '%s', %d at a time.\n", len(intervals), params.pipeline.Schedule, parallelism)
+
+	summaries := make([]*runSummary, len(intervals))
+	runErrors := make([]error, len(intervals))
+
+	wg := new(errgroup.Group)
+	wg.SetLimit(parallelism)
+	for i, interval := range intervals {
+		i, interval := i, interval
+		wg.Go(func() error {
+			// the intervals that did not start before the cancellation are not started at all
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			summaries[i], runErrors[i] = executeRun(ctx, params, uuid.New().String(), interval)
+			return nil
+		})
+	}
+	_ = wg.Wait()
+
+	failed := printBackfillSummary(intervals, summaries, runErrors)
+
+	if backfill := backfillReport(params.pipeline.Name, intervals, summaries, runErrors); backfill != nil {
+		sendNotifications(params.pipeline, params.notifiers, backfill)
+	}
+
+	err = writeReports(afero.NewOsFs(), params.reports, summaries, params.pipeline.Name)
+	if err != nil {
+		errorPrinter.Printf("Failed to write the report: %v\n", err)
+		return cli.Exit("", 1)
+	}
+
+	if failed {
+		return cli.Exit("", 1)
+	}
+
+	return nil
+}
+
+// backfillReport aggregates the intervals into a single report so that the backfill is notified once, the intervals that
+// failed to run are reported as failed and the ones that did not start as cancelled. It returns nil if there was nothing
+// to run in any of the intervals.
+func backfillReport(pipelineName string, intervals []date.Interval, summaries []*runSummary, runErrors []error) *report.Run {
+	runs := make([]*report.Run, 0, len(intervals))
+	for i, interval := range intervals {
+		summary := summaries[i]
+
+		switch {
+		case runErrors[i] != nil:
+			runs = append(runs, intervalReport(pipelineName, interval, report.StatusFailed))
+		case summary == nil:
+			runs = append(runs, intervalReport(pipelineName, interval, report.StatusCancelled))
+		case summary.nothingToRun:
+			continue
+		default:
+			runs = append(runs, summary.report(pipelineName))
+		}
+	}
+
+	if len(runs) == 0 {
+		return nil
+	}
+
+	return report.NewBackfill(pipelineName, runs)
+}
+
+func intervalReport(pipelineName string, interval date.Interval, status string) *report.Run {
+	return &report.Run{
+		Pipeline:  pipelineName,
+		StartDate: interval.Start,
+		EndDate:   interval.End,
+		Status:    status,
+		Instances: make([]*report.Instance, 0),
+	}
+}
+
+// printBackfillSummary prints the outcome of every interval and reports whether any of them did not succeed.
+func printBackfillSummary(intervals []date.Interval, summaries []*runSummary, runErrors []error) bool {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "INTERVAL\tRUN ID\tSTATUS\tSUCCEEDED\tFAILED\tUPSTREAM FAILED\tCANCELLED\tSKIPPED\tDURATION")
+
+	failed := false
+	failedSummaries := make([]*runSummary, 0)
+	for i, interval := range intervals {
+		intervalString := fmt.Sprintf("%s - %s", interval.Start.Format(historyTimeFormat), interval.End.Format(historyTimeFormat))
+		summary := summaries[i]
+
+		switch {
+		case runErrors[i] != nil:
+			failed = true
+			fmt.Fprintf(w, "%s\t-\terror: %s\t-\t-\t-\t-\t-\t-\n", intervalString, runErrors[i])
+		case summary == nil:
+			failed = true
+			fmt.Fprintf(w, "%s\t-\tnot started\t-\t-\t-\t-\t-\t-\n", intervalString)
+		case summary.nothingToRun:
+			fmt.Fprintf(w, "%s\t%s\tnothing to run\t-\t-\t-\t-\t-\t-\n", intervalString, summary.runID)
+		default:
+			status := "succeeded"
+			if !summary.succeeded() {
+				status = "failed"
+				failed = true
+				failedSummaries = append(failedSummaries, summary)
+			}
+
+			s := summary.scheduler
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%d\t%d\t%d\t%s\n",
+				intervalString,
+				summary.runID,
+				status,
+				s.InstanceCountByStatus(scheduler.Succeeded),
+				s.InstanceCountByStatus(scheduler.Failed),
+				s.InstanceCountByStatus(scheduler.UpstreamFailed),
+				s.InstanceCountByStatus(scheduler.Cancelled),
+				s.InstanceCountByStatus(scheduler.Skipped),
+				summary.duration.Truncate(time.Millisecond),
+			)
+		}
+	}
+	_ = w.Flush()
+
+	infoPrinter.Printf("\n\nBackfill summary:\n")
+	infoPrinter.Print(buf.String())
+
+	for _, summary := range failedSummaries {
+		errorPrinter.Printf("\nInterval %s - %s:\n", summary.interval.Start.Format(historyTimeFormat), summary.interval.End.Format(historyTimeFormat))
+		for _, t := range summary.failedResults() {
+			errorPrinter.Printf("  - %s\n", t.Instance.GetHumanID())
+			errorPrinter.Printf("    └── %s\n", t.Error.Error())
+		}
+		infoPrinter.Printf("  You can resume this interval with: blast-cli run --resume %s\n", summary.runID)
+	}
+
+	return failed
+}
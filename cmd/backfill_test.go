@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/datablast-analytics/blast/pkg/date"
+	"github.com/datablast-analytics/blast/pkg/pipeline"
+	"github.com/datablast-analytics/blast/pkg/report"
+	"github.com/datablast-analytics/blast/pkg/scheduler"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func Test_backfillReport(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	intervals := make([]date.Interval, 0, 4)
+	for i := 0; i < 4; i++ {
+		intervals = append(intervals, date.Interval{Start: start.AddDate(0, 0, i), End: start.AddDate(0, 0, i+1).Add(-time.Second)})
+	}
+
+	p := &pipeline.Pipeline{Name: "pipeline", Tasks: []*pipeline.Asset{{Name: "task1"}}}
+	s := scheduler.NewScheduler(zap.NewNop().Sugar(), p)
+	s.MarkAll(scheduler.Succeeded)
+
+	summaries := []*runSummary{
+		{runID: "run-1", interval: intervals[0], scheduler: s, startedAt: start, duration: time.Minute},
+		nil,
+		{runID: "run-3", interval: intervals[2], scheduler: s, nothingToRun: true},
+		nil,
+	}
+	runErrors := []error{nil, errors.New("failed to save the state"), nil, nil}
+
+	got := backfillReport("pipeline", intervals, summaries, runErrors)
+	require.NotNil(t, got)
+	assert.Equal(t, report.StatusFailed, got.Status)
+	assert.Equal(t, intervals[0].Start, got.StartDate)
+	assert.Equal(t, intervals[3].End, got.EndDate)
+
+	require.Len(t, got.Intervals, 3)
+	assert.Equal(t, "run-1", got.Intervals[0].ID)
+	assert.Equal(t, report.StatusSucceeded, got.Intervals[0].Status)
+	assert.Equal(t, report.StatusFailed, got.Intervals[1].Status)
+	assert.Equal(t, intervals[1].Start, got.Intervals[1].StartDate)
+	assert.Equal(t, report.StatusCancelled, got.Intervals[2].Status)
+	assert.Equal(t, intervals[3].Start, got.Intervals[2].StartDate)
+
+	assert.Nil(t, backfillReport("pipeline", intervals[2:3], summaries[2:3], runErrors[2:3]))
+}
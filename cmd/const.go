@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"os"
+
 	"github.com/datablast-analytics/blast/pkg/pipeline"
 	"github.com/fatih/color"
 	"github.com/spf13/afero"
@@ -17,6 +19,11 @@ var (
 	infoPrinter    = color.New(color.Bold)
 	errorPrinter   = color.New(color.FgRed, color.Bold)
 	successPrinter = color.New(color.FgGreen, color.Bold)
+	warningPrinter = color.New(color.FgYellow, color.Bold)
+
+	// humanOutput is where the output that is not printed through the printers goes, e.g. the prompts. It is moved to
+	// stderr together with the printers when stdout is used for the events.
+	humanOutput = os.Stdout
 
 	builderConfig = pipeline.BuilderConfig{
 		PipelineFileName:    pipelineDefinitionFile,
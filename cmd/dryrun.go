@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/datablast-analytics/blast/pkg/date"
+	"github.com/datablast-analytics/blast/pkg/dryrun"
+	"github.com/datablast-analytics/blast/pkg/executor"
+	"github.com/datablast-analytics/blast/pkg/python"
+	"github.com/datablast-analytics/blast/pkg/scheduler"
+	"github.com/datablast-analytics/blast/pkg/sensor"
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+)
+
+// printExecutionPlan walks the instances in their dependency order and prints what each of them would execute. The
+// operators are the same ones used in the actual runs, but their connections only print the queries.
+func printExecutionPlan(params *runParameters, interval date.Interval) error {
+	s := newRunScheduler(params, interval)
+	plannedInstances := s.InstanceCountByStatus(scheduler.Pending)
+	if plannedInstances == 0 {
+		successPrinter.Println("There are no tasks left to run.")
+		return nil
+	}
+
+	executors, err := setupExecutors(s, dryrun.Connections{}, python.NewDryRunOperator(), interval.Start, interval.End)
+	if err != nil {
+		errorPrinter.Println(err.Error())
+		return cli.Exit("", 1)
+	}
+
+	// the dry-run connections cannot know the results of the custom checks and the sensors, therefore only their queries
+	// are printed, the sensors are poked once instead of waiting for their conditions
+	for _, config := range executors {
+		if operator, ok := config[scheduler.TaskInstanceTypeCustomTest]; ok {
+			config[scheduler.TaskInstanceTypeCustomTest] = planOnlyOperator{operator: operator}
+		}
+
+		if operator, ok := config[scheduler.TaskInstanceTypeMain].(*sensor.Operator); ok {
+			config[scheduler.TaskInstanceTypeMain] = planOnlyOperator{operator: operator.WithMode(sensor.ModeOnce)}
+		}
+	}
+
+	infoPrinter.Printf("\nExecution plan for the interval %s - %s:\n", interval.Start.Format(historyTimeFormat), interval.End.Format(historyTimeFormat))
+
+	ex := executor.Sequential{TaskTypeMap: executors}
+	failedInstances := 0
+	go func() {
+		step := 0
+		for instance := range s.WorkQueue {
+			step++
+			infoPrinter.Printf("\n[%d] %s\n", step, instance.GetHumanID())
+
+			output := &indentWriter{w: color.Output, prefix: "    "}
+			_, _ = fmt.Fprintf(output, "type: %s\n", instance.GetAsset().Type)
+
+			err := ex.RunSingleTask(context.WithValue(context.Background(), executor.KeyPrinter, output), instance)
+			if err != nil {
+				failedInstances++
+				errorPrinter.Printf("    failed to build the plan: %v\n", err)
+			}
+
+			// the plan continues with the downstream instances even if this one could not be planned
+			s.Results <- &scheduler.TaskExecutionResult{Instance: instance}
+		}
+	}()
+
+	s.Run(context.Background())
+
+	if failedInstances > 0 {
+		errorPrinter.Printf("\nFailed to build the plan for %d instances.\n", failedInstances)
+		return cli.Exit("", 1)
+	}
+
+	successPrinter.Printf("\nThe plan has %d instances, nothing is executed.\n", plannedInstances)
+	return nil
+}
+
+// planOnlyOperator runs the given operator for its output and ignores its result.
+type planOnlyOperator struct {
+	operator executor.Operator
+}
+
+func (p planOnlyOperator) Run(ctx context.Context, ti scheduler.TaskInstance) error {
+	_ = p.operator.Run(ctx, ti)
+	return nil
+}
+
+// indentWriter prefixes every line written to it, the writes are expected to end with complete lines.
+type indentWriter struct {
+	w      io.Writer
+	prefix string
+}
+
+func (i *indentWriter) Write(p []byte) (int, error) {
+	lines := strings.SplitAfter(string(p), "\n")
+	var sb strings.Builder
+	for _, line := range lines {
+		if line != "\n" && line != "" {
+			sb.WriteString(i.prefix)
+		}
+		sb.WriteString(line)
+	}
+
+	_, err := i.w.Write([]byte(sb.String()))
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndentWriter_Write(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w := &indentWriter{w: &buf, prefix: "  "}
+
+	n, err := w.Write([]byte("first\n\nsecond\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 14, n)
+
+	_, err = w.Write([]byte("third\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "  first\n\n  second\n  third\n", buf.String())
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/datablast-analytics/blast/pkg/events"
+	"github.com/fatih/color"
+	"github.com/pkg/errors"
+)
+
+const eventsToStdout = "-"
+
+// openEventsWriter opens the JSON lines output of the events, when the events are streamed to stdout the rest of the
+// output is moved to stderr so that stdout stays machine-readable.
+func openEventsWriter(path string) (*events.JSONLinesWriter, func(), error) {
+	file := os.Stdout
+	if path == eventsToStdout {
+		humanOutput = os.Stderr
+		color.Output = color.Error
+	} else {
+		var err error
+		file, err = os.Create(path)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to create the events file")
+		}
+	}
+
+	writer := events.NewJSONLinesWriter(file)
+	return writer, func() {
+		if writer.Err() != nil {
+			errorPrinter.Printf("Failed to write the events: %v\n", writer.Err())
+		}
+
+		if file != os.Stdout {
+			_ = file.Close()
+		}
+	}, nil
+}
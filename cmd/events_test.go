@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+// the test swaps stdout and the global outputs, therefore it cannot run in parallel with the others.
+func TestRun_EventsToStdoutOnlyPrintsEvents(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	pipelineDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(pipelineDir, "assets"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(pipelineDir, pipelineDefinitionFile), []byte("name: events-pipeline\nschedule: daily\nstart_date: \"2023-03-01\"\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(pipelineDir, "assets", "first.asset.yml"), []byte("name: first\ntype: empty\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(pipelineDir, "assets", "second.asset.yml"), []byte("name: second\ntype: empty\ndepends:\n  - first\n"), 0o644))
+
+	reader, writer, err := os.Pipe()
+	require.NoError(t, err)
+
+	originalStdout, originalOutput, originalHumanOutput := os.Stdout, color.Output, humanOutput
+	os.Stdout, color.Output, humanOutput = writer, writer, writer
+	defer func() {
+		os.Stdout, color.Output, humanOutput = originalStdout, originalOutput, originalHumanOutput
+	}()
+
+	var stdout bytes.Buffer
+	copied := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(&stdout, reader)
+		close(copied)
+	}()
+
+	isDebug := false
+	app := &cli.App{
+		Commands:       []*cli.Command{Run(&isDebug)},
+		ExitErrHandler: func(c *cli.Context, err error) {},
+	}
+	runErr := app.Run([]string{"blast", "run", "--events", "-", "--start-date", "2023-03-01", "--end-date", "2023-03-02", pipelineDir})
+
+	require.NoError(t, writer.Close())
+	<-copied
+	require.NoError(t, runErr)
+
+	types := make([]string, 0)
+	scanner := bufio.NewScanner(&stdout)
+	for scanner.Scan() {
+		var event map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event), "stdout contains a line that is not an event: %q", scanner.Text())
+		types = append(types, event["type"].(string))
+	}
+	require.NoError(t, scanner.Err())
+
+	require.NotEmpty(t, types)
+	assert.Equal(t, "run_started", types[0])
+	assert.Equal(t, "run_finished", types[len(types)-1])
+}
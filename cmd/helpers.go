@@ -28,11 +28,12 @@ func switchEnvironment(c *cli.Context, cm *config.Config, stdin io.ReadCloser) e
 			Label:     "You are using a production environment. Are you sure you want to continue?",
 			IsConfirm: true,
 			Stdin:     stdin,
+			Stdout:    humanOutput,
 		}
 
 		_, err := prompt.Run()
 		if err != nil {
-			fmt.Printf("The operation is cancelled.\n")
+			fmt.Fprintf(humanOutput, "The operation is cancelled.\n")
 			return cli.Exit("", 1)
 		}
 	}
@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/datablast-analytics/blast/pkg/history"
+	"github.com/datablast-analytics/blast/pkg/scheduler"
+	"github.com/datablast-analytics/blast/pkg/user"
+	"github.com/spf13/afero"
+	"github.com/urfave/cli/v2"
+)
+
+const historyTimeFormat = "2006-01-02 15:04:05"
+
+func History() *cli.Command {
+	limitFlag := &cli.IntFlag{
+		Name:  "limit",
+		Usage: "the maximum number of records to display",
+		Value: 20,
+	}
+
+	return &cli.Command{
+		Name:  "history",
+		Usage: "inspect the past runs recorded on this machine",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "runs",
+				Usage: "list the most recent runs",
+				Flags: []cli.Flag{limitFlag},
+				Action: func(c *cli.Context) error {
+					return runHistoryCommand(func(r *HistoryCommand) error {
+						return r.ListRuns(c.Int("limit"))
+					})
+				},
+			},
+			{
+				Name:      "show",
+				Usage:     "show the task instances of a run",
+				ArgsUsage: "[run ID]",
+				Action: func(c *cli.Context) error {
+					return runHistoryCommand(func(r *HistoryCommand) error {
+						return r.ShowRun(c.Args().Get(0))
+					})
+				},
+			},
+			{
+				Name:      "asset",
+				Usage:     "show the recent durations and the failure rate of an asset",
+				ArgsUsage: "[asset name]",
+				Flags: []cli.Flag{
+					limitFlag,
+					&cli.StringFlag{
+						Name:     "pipeline",
+						Aliases:  []string{"p"},
+						Usage:    "the name of the pipeline the asset belongs to",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:    "environment",
+						Aliases: []string{"e", "env"},
+						Usage:   "only show the runs in the given environment, all the environments are shown by default",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					return runHistoryCommand(func(r *HistoryCommand) error {
+						return r.ShowAsset(c.String("pipeline"), c.String("environment"), c.Args().Get(0), c.Int("limit"))
+					})
+				},
+			},
+		},
+	}
+}
+
+func openHistoryStore() (*history.Store, error) {
+	cm := user.NewConfigManager(afero.NewOsFs())
+	err := cm.EnsureHomeDirExists()
+	if err != nil {
+		return nil, err
+	}
+
+	return history.Open(cm.HistoryDBPath())
+}
+
+func runHistoryCommand(action func(r *HistoryCommand) error) error {
+	store, err := openHistoryStore()
+	if err != nil {
+		errorPrinter.Printf("Failed to open the run history: %v\n", err)
+		return cli.Exit("", 1)
+	}
+	defer store.Close()
+
+	return action(&HistoryCommand{
+		store:        store,
+		infoPrinter:  infoPrinter,
+		errorPrinter: errorPrinter,
+	})
+}
+
+type historyReader interface {
+	ListRuns(limit int) ([]*history.Run, error)
+	GetRun(id string) (*history.Run, error)
+	GetAssetStats(pipeline, environment, asset string, limit int) (*history.AssetStats, error)
+}
+
+type HistoryCommand struct {
+	store        historyReader
+	infoPrinter  printer
+	errorPrinter printer
+}
+
+func (r *HistoryCommand) ListRuns(limit int) error {
+	runs, err := r.store.ListRuns(limit)
+	if err != nil {
+		r.errorPrinter.Printf("Failed to list the runs: %v\n", err)
+		return cli.Exit("", 1)
+	}
+
+	if len(runs) == 0 {
+		r.infoPrinter.Println("There are no runs recorded yet.")
+		return nil
+	}
+
+	r.printTable(func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "RUN ID\tPIPELINE\tENVIRONMENT\tINTERVAL\tSTARTED AT\tDURATION\tSUCCEEDED\tFAILED")
+		for _, run := range runs {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s - %s\t%s\t%s\t%d\t%d\n",
+				run.ID,
+				run.Pipeline,
+				run.Environment,
+				run.StartDate.Format(historyTimeFormat),
+				run.EndDate.Format(historyTimeFormat),
+				run.StartedAt.Format(historyTimeFormat),
+				run.Duration().Truncate(time.Millisecond),
+				run.InstanceCountByStatus(scheduler.Succeeded),
+				run.InstanceCountByStatus(scheduler.Failed),
+			)
+		}
+	})
+
+	return nil
+}
+
+func (r *HistoryCommand) ShowRun(runID string) error {
+	if runID == "" {
+		r.errorPrinter.Printf("Please give a run ID to show: blast-cli history show <run ID>\n")
+		return cli.Exit("", 1)
+	}
+
+	run, err := r.store.GetRun(runID)
+	if err != nil {
+		r.errorPrinter.Printf("Failed to find the run: %v\n", err)
+		return cli.Exit("", 1)
+	}
+
+	r.infoPrinter.Printf("Run: %s\n", run.ID)
+	r.infoPrinter.Printf("Pipeline: %s\n", run.Pipeline)
+	r.infoPrinter.Printf("Environment: %s\n", run.Environment)
+	r.infoPrinter.Printf("Interval: %s - %s\n\n", run.StartDate.Format(historyTimeFormat), run.EndDate.Format(historyTimeFormat))
+
+	r.printTable(func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "INSTANCE\tTYPE\tSTATUS\tSTARTED AT\tFINISHED AT\tDURATION\tATTEMPTS\tERROR")
+		for _, instance := range run.Instances {
+			startedAt, finishedAt, duration := "-", "-", "-"
+			if instance.Executed() {
+				startedAt = instance.StartedAt.Format(historyTimeFormat)
+				finishedAt = instance.FinishedAt.Format(historyTimeFormat)
+				duration = instance.Duration().Truncate(time.Millisecond).String()
+			}
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%d\t%s\n",
+				instance.ID,
+				instance.Type,
+				instance.Status,
+				startedAt,
+				finishedAt,
+				duration,
+				instance.Attempts,
+				instance.Error,
+			)
+		}
+	})
+
+	return nil
+}
+
+func (r *HistoryCommand) ShowAsset(pipeline, environment, asset string, limit int) error {
+	if asset == "" || pipeline == "" {
+		r.errorPrinter.Printf("Please give a pipeline and an asset name: blast-cli history asset --pipeline <pipeline name> <asset name>\n")
+		return cli.Exit("", 1)
+	}
+
+	stats, err := r.store.GetAssetStats(pipeline, environment, asset, limit)
+	if err != nil {
+		r.errorPrinter.Printf("Failed to read the history of the asset: %v\n", err)
+		return cli.Exit("", 1)
+	}
+
+	if len(stats.Executions) == 0 {
+		r.infoPrinter.Printf("There are no executions recorded for the asset '%s' in the pipeline '%s'.\n", asset, pipeline)
+		return nil
+	}
+
+	r.infoPrinter.Printf("Asset: %s\n", asset)
+	r.infoPrinter.Printf("Pipeline: %s\n", pipeline)
+	if environment != "" {
+		r.infoPrinter.Printf("Environment: %s\n", environment)
+	}
+	r.infoPrinter.Printf("Executions: %d\n", len(stats.Executions))
+	r.infoPrinter.Printf("Average duration: %s\n", stats.AverageDuration.Truncate(time.Millisecond))
+	r.infoPrinter.Printf("Failure rate: %.1f%%\n\n", stats.FailureRate()*100)
+
+	r.printTable(func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "RUN ID\tSTATUS\tSTARTED AT\tDURATION\tATTEMPTS")
+		for _, execution := range stats.Executions {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\n",
+				execution.RunID,
+				execution.Instance.Status,
+				execution.Instance.StartedAt.Format(historyTimeFormat),
+				execution.Instance.Duration().Truncate(time.Millisecond),
+				execution.Instance.Attempts,
+			)
+		}
+	})
+
+	return nil
+}
+
+func (r *HistoryCommand) printTable(write func(w *tabwriter.Writer)) {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	write(w)
+	_ = w.Flush()
+
+	r.infoPrinter.Print(buf.String())
+}
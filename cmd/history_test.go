@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/datablast-analytics/blast/pkg/history"
+	"github.com/datablast-analytics/blast/pkg/scheduler"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockHistoryReader struct {
+	mock.Mock
+}
+
+func (m *mockHistoryReader) ListRuns(limit int) ([]*history.Run, error) {
+	args := m.Called(limit)
+	return args.Get(0).([]*history.Run), args.Error(1)
+}
+
+func (m *mockHistoryReader) GetRun(id string) (*history.Run, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*history.Run), args.Error(1)
+}
+
+func (m *mockHistoryReader) GetAssetStats(pipeline, environment, asset string, limit int) (*history.AssetStats, error) {
+	args := m.Called(pipeline, environment, asset, limit)
+	return args.Get(0).(*history.AssetStats), args.Error(1)
+}
+
+func TestHistoryCommand_ListRuns(t *testing.T) {
+	t.Parallel()
+
+	startedAt := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	store := new(mockHistoryReader)
+	store.On("ListRuns", 10).Return([]*history.Run{
+		{
+			ID:          "run-1",
+			Pipeline:    "my-pipeline",
+			Environment: "dev",
+			StartDate:   time.Date(2023, 2, 28, 0, 0, 0, 0, time.UTC),
+			EndDate:     time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+			StartedAt:   startedAt,
+			FinishedAt:  startedAt.Add(90 * time.Second),
+			Instances: []*history.Instance{
+				{ID: "task1", Status: scheduler.Succeeded},
+				{ID: "task2", Status: scheduler.Failed},
+			},
+		},
+	}, nil)
+
+	output := &mockPrinter{buf: &bytes.Buffer{}}
+	r := &HistoryCommand{store: store, infoPrinter: output, errorPrinter: output}
+
+	err := r.ListRuns(10)
+	assert.NoError(t, err)
+
+	expected := "RUN ID  PIPELINE     ENVIRONMENT  INTERVAL                                   STARTED AT           DURATION  SUCCEEDED  FAILED\n" +
+		"run-1   my-pipeline  dev          2023-02-28 00:00:00 - 2023-03-01 00:00:00  2023-03-01 10:00:00  1m30s     1          1\n"
+	assert.Equal(t, expected, output.buf.String())
+}
+
+func TestHistoryCommand_ShowRun(t *testing.T) {
+	t.Parallel()
+
+	store := new(mockHistoryReader)
+	store.On("GetRun", "missing-run").Return(nil, errors.New("there is no run with the ID 'missing-run'"))
+
+	output := &mockPrinter{buf: &bytes.Buffer{}}
+	r := &HistoryCommand{store: store, infoPrinter: output, errorPrinter: output}
+
+	assert.Error(t, r.ShowRun(""))
+	assert.Error(t, r.ShowRun("missing-run"))
+	assert.Contains(t, output.buf.String(), "there is no run with the ID 'missing-run'")
+}
+
+func TestHistoryCommand_ShowAsset(t *testing.T) {
+	t.Parallel()
+
+	startedAt := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	store := new(mockHistoryReader)
+	store.On("GetAssetStats", "my-pipeline", "prod", "task1", 5).Return(&history.AssetStats{
+		Executions: []*history.AssetExecution{
+			{
+				RunID: "run-2",
+				Instance: &history.Instance{
+					ID:         "task1",
+					Status:     scheduler.Failed,
+					StartedAt:  startedAt,
+					FinishedAt: startedAt.Add(3 * time.Second),
+					Attempts:   2,
+				},
+			},
+			{
+				RunID: "run-1",
+				Instance: &history.Instance{
+					ID:         "task1",
+					Status:     scheduler.Succeeded,
+					StartedAt:  startedAt,
+					FinishedAt: startedAt.Add(time.Second),
+					Attempts:   1,
+				},
+			},
+		},
+		Failures:        1,
+		AverageDuration: 2 * time.Second,
+	}, nil)
+	store.On("GetAssetStats", "my-pipeline", "", "task2", 5).Return(&history.AssetStats{}, nil)
+
+	output := &mockPrinter{buf: &bytes.Buffer{}}
+	r := &HistoryCommand{store: store, infoPrinter: output, errorPrinter: output}
+
+	assert.Error(t, r.ShowAsset("", "", "task1", 5))
+
+	output.buf.Reset()
+	err := r.ShowAsset("my-pipeline", "prod", "task1", 5)
+	assert.NoError(t, err)
+
+	expected := "Asset: task1\n" +
+		"Pipeline: my-pipeline\n" +
+		"Environment: prod\n" +
+		"Executions: 2\n" +
+		"Average duration: 2s\n" +
+		"Failure rate: 50.0%\n\n" +
+		"RUN ID  STATUS     STARTED AT           DURATION  ATTEMPTS\n" +
+		"run-2   failed     2023-03-01 10:00:00  3s        2\n" +
+		"run-1   succeeded  2023-03-01 10:00:00  1s        1\n"
+	assert.Equal(t, expected, output.buf.String())
+
+	output.buf.Reset()
+	err = r.ShowAsset("my-pipeline", "", "task2", 5)
+	assert.NoError(t, err)
+	assert.Equal(t, "There are no executions recorded for the asset 'task2' in the pipeline 'my-pipeline'.\n", output.buf.String())
+}
@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/datablast-analytics/blast/pkg/path"
 	"github.com/datablast-analytics/blast/pkg/pipeline"
@@ -71,6 +73,7 @@ func (r *LineageCommand) Run(assetPath string, fullLineage bool) error {
 		return cli.Exit("", 1)
 	}
 	r.infoPrinter.Printf("\nLineage: '%s'", asset.Name)
+	r.printAssetMetadata(asset)
 
 	upstream := asset.GetUpstream()
 	downstream := asset.GetDownstream()
@@ -93,8 +96,65 @@ func (r *LineageCommand) printLineageSummary(p *pipeline.Pipeline, assets []*pip
 		r.infoPrinter.Println(absenceMessage)
 	} else {
 		for _, u := range assets {
-			r.infoPrinter.Printf("- %s %s\n", u.Name, faint(fmt.Sprintf("(%s)", p.RelativeAssetPath(u))))
+			details := fmt.Sprintf("(%s)", p.RelativeAssetPath(u))
+			if summary := assetMetadataSummary(u); summary != "" {
+				details = fmt.Sprintf("(%s, %s)", p.RelativeAssetPath(u), summary)
+			}
+
+			r.infoPrinter.Printf("- %s %s\n", u.Name, faint(details))
 		}
 		r.infoPrinter.Printf("\nTotal: %d\n", len(assets))
 	}
 }
+
+func (r *LineageCommand) printAssetMetadata(asset *pipeline.Asset) {
+	if asset.Owner != "" {
+		r.infoPrinter.Printf("\nOwner: %s", asset.Owner)
+	}
+
+	if asset.Domain != "" {
+		r.infoPrinter.Printf("\nDomain: %s", asset.Domain)
+	}
+
+	if len(asset.Tags) > 0 {
+		r.infoPrinter.Printf("\nTags: %s", strings.Join(asset.Tags, ", "))
+	}
+
+	if len(asset.Meta) > 0 {
+		r.infoPrinter.Printf("\nMeta: %s", formatMeta(asset.Meta))
+	}
+}
+
+// assetMetadataSummary returns a short description of the ownership and the classification of an asset, the meta
+// values are left out to keep the dependency lists readable.
+func assetMetadataSummary(asset *pipeline.Asset) string {
+	parts := make([]string, 0)
+	if asset.Owner != "" {
+		parts = append(parts, "owner: "+asset.Owner)
+	}
+
+	if asset.Domain != "" {
+		parts = append(parts, "domain: "+asset.Domain)
+	}
+
+	if len(asset.Tags) > 0 {
+		parts = append(parts, "tags: "+strings.Join(asset.Tags, " "))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func formatMeta(meta map[string]string) string {
+	keys := make([]string, 0, len(meta))
+	for key := range meta {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = fmt.Sprintf("%s=%s", key, meta[key])
+	}
+
+	return strings.Join(pairs, ", ")
+}
@@ -74,6 +74,10 @@ func TestLineageCommand_Run(t *testing.T) {
 			},
 			want: `
 Lineage: 'dashboard.hello_bq'
+Owner: analytics@example.com
+Domain: dashboards
+Tags: daily, reporting
+Meta: sla=6h
 
 Upstream Dependencies
 ========================
@@ -84,7 +88,7 @@ Total: 1
 
 Downstream Dependencies
 ========================
-- nested1 (assets/nested1.sql)
+- nested1 (assets/nested1.sql, owner: finance@example.com, tags: finance)
 
 Total: 1
 `,
@@ -98,6 +102,10 @@ Total: 1
 			},
 			want: `
 Lineage: 'dashboard.hello_bq'
+Owner: analytics@example.com
+Domain: dashboards
+Tags: daily, reporting
+Meta: sla=6h
 
 Upstream Dependencies
 ========================
@@ -108,7 +116,7 @@ Total: 1
 
 Downstream Dependencies
 ========================
-- nested1 (assets/nested1.sql)
+- nested1 (assets/nested1.sql, owner: finance@example.com, tags: finance)
 - nested2 (assets/nested2.sql)
 
 Total: 2
@@ -81,6 +81,20 @@ func Lint(isDebug *bool) *cli.Command {
 				})
 			}
 
+			if len(cm.SelectedEnvironment.Connections.Snowflake) > 0 {
+				rules = append(rules, &lint.QueryValidatorRule{
+					Identifier:  "snowflake-validator",
+					TaskType:    executor.TaskTypeSnowflakeQuery,
+					Connections: connectionManager,
+					Extractor: &query.FileQuerySplitterExtractor{
+						Fs:       fs,
+						Renderer: query.DefaultJinjaRenderer,
+					},
+					WorkerCount: 32,
+					Logger:      logger,
+				})
+			}
+
 			linter := lint.NewLinter(path.GetPipelinePaths, builder, rules, logger)
 
 			infoPrinter.Printf("Validating pipelines in '%s' for '%s' environment...\n", rootPath, cm.SelectedEnvironmentName)
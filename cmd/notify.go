@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/datablast-analytics/blast/pkg/config"
+	"github.com/datablast-analytics/blast/pkg/notification"
+	"github.com/datablast-analytics/blast/pkg/pipeline"
+	"github.com/datablast-analytics/blast/pkg/report"
+	"github.com/datablast-analytics/blast/pkg/scheduler"
+	"github.com/pkg/errors"
+)
+
+type pipelineNotifier struct {
+	name     string
+	notifier notification.Notifier
+}
+
+// setupNotifiers creates the notifiers declared in the pipeline, the connections they refer to must exist in the
+// selected environment.
+func setupNotifiers(p *pipeline.Pipeline, connections config.Connections) ([]pipelineNotifier, error) {
+	slackConnections := make(map[string]config.SlackConnection, len(connections.Slack))
+	for _, conn := range connections.Slack {
+		slackConnections[conn.Name] = conn
+	}
+
+	webhookConnections := make(map[string]config.WebhookConnection, len(connections.Webhook))
+	for _, conn := range connections.Webhook {
+		webhookConnections[conn.Name] = conn
+	}
+
+	smtpConnections := make(map[string]config.SMTPConnection, len(connections.SMTP))
+	for _, conn := range connections.SMTP {
+		smtpConnections[conn.Name] = conn
+	}
+
+	notifiers := make([]pipelineNotifier, 0, len(p.Notifications.Names()))
+	for _, slack := range p.Notifications.Slack {
+		conn, ok := slackConnections[slack.Connection]
+		if !ok {
+			return nil, errors.Errorf("the Slack connection '%s' of the notification '%s' is not found in the selected environment", slack.Connection, slack.Name)
+		}
+
+		notifiers = append(notifiers, pipelineNotifier{name: slack.Name, notifier: notification.NewSlack(conn.WebhookURL, slack)})
+	}
+
+	for _, webhook := range p.Notifications.Webhook {
+		conn, ok := webhookConnections[webhook.Connection]
+		if !ok {
+			return nil, errors.Errorf("the webhook connection '%s' of the notification '%s' is not found in the selected environment", webhook.Connection, webhook.Name)
+		}
+
+		notifiers = append(notifiers, pipelineNotifier{name: webhook.Name, notifier: notification.NewWebhook(conn.URL, conn.Headers, webhook)})
+	}
+
+	for _, email := range p.Notifications.Email {
+		conn, ok := smtpConnections[email.Connection]
+		if !ok {
+			return nil, errors.Errorf("the SMTP connection '%s' of the notification '%s' is not found in the selected environment", email.Connection, email.Name)
+		}
+
+		notifiers = append(notifiers, pipelineNotifier{name: email.Name, notifier: notification.NewEmail(conn, email)})
+	}
+
+	return notifiers, nil
+}
+
+// sendNotifications sends the outcome of the run to every notifier, the failures are printed without failing the run.
+func sendNotifications(p *pipeline.Pipeline, notifiers []pipelineNotifier, run *report.Run) {
+	for _, n := range notifiers {
+		if !shouldNotify(p, n.name, run) {
+			continue
+		}
+
+		err := n.notifier.Notify(context.Background(), run)
+		if err != nil {
+			errorPrinter.Printf("Failed to send the notification '%s': %v\n", n.name, err)
+		}
+	}
+}
+
+// shouldNotify reports whether the run should be sent to the given notification. A failed run is only sent if at least
+// one of the failed assets does not exclude the notification with its `notify_on_failure` list, the failed assets of a
+// backfill are collected from all of its intervals.
+func shouldNotify(p *pipeline.Pipeline, name string, run *report.Run) bool {
+	if run.Status != report.StatusFailed {
+		return true
+	}
+
+	failed := false
+	for _, r := range append([]*report.Run{run}, run.Intervals...) {
+		for _, instance := range r.Instances {
+			if instance.Status != scheduler.Failed {
+				continue
+			}
+
+			failed = true
+			asset := p.GetAssetByName(instance.Asset)
+			if asset == nil || asset.NotifyOnFailure == nil || isStringInSlice(asset.NotifyOnFailure, name) {
+				return true
+			}
+		}
+	}
+
+	return !failed
+}
+
+func isStringInSlice(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
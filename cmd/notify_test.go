@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/datablast-analytics/blast/pkg/config"
+	"github.com/datablast-analytics/blast/pkg/pipeline"
+	"github.com/datablast-analytics/blast/pkg/report"
+	"github.com/datablast-analytics/blast/pkg/scheduler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_setupNotifiers(t *testing.T) {
+	t.Parallel()
+
+	connections := config.Connections{
+		Slack: []config.SlackConnection{
+			{Name: "slack-alerts", WebhookURL: "https://hooks.slack.com/services/T000/B000/XXXX"},
+		},
+		Webhook: []config.WebhookConnection{
+			{Name: "pagerduty", URL: "https://events.example.com/v2/enqueue"},
+		},
+		SMTP: []config.SMTPConnection{
+			{Name: "mail", Host: "smtp.example.com", Port: 587},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		notifications pipeline.Notifications
+		wantNames     []string
+		wantErr       bool
+	}{
+		{
+			name:      "no notifications",
+			wantNames: []string{},
+		},
+		{
+			name: "notifications with known connections",
+			notifications: pipeline.Notifications{
+				Slack:   []pipeline.SlackNotification{{Name: "alerts", Connection: "slack-alerts"}},
+				Webhook: []pipeline.WebhookNotification{{Name: "oncall", Connection: "pagerduty"}},
+				Email:   []pipeline.EmailNotification{{Name: "team", Connection: "mail", To: []string{"team@example.com"}}},
+			},
+			wantNames: []string{"alerts", "oncall", "team"},
+		},
+		{
+			name: "unknown Slack connection is rejected",
+			notifications: pipeline.Notifications{
+				Slack: []pipeline.SlackNotification{{Name: "alerts", Connection: "missing"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown webhook connection is rejected",
+			notifications: pipeline.Notifications{
+				Webhook: []pipeline.WebhookNotification{{Name: "oncall", Connection: "slack-alerts"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown SMTP connection is rejected",
+			notifications: pipeline.Notifications{
+				Email: []pipeline.EmailNotification{{Name: "team", Connection: "missing"}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := setupNotifiers(&pipeline.Pipeline{Notifications: tt.notifications}, connections)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			names := make([]string, 0, len(got))
+			for _, n := range got {
+				names = append(names, n.name)
+			}
+			assert.Equal(t, tt.wantNames, names)
+		})
+	}
+}
+
+func Test_shouldNotify(t *testing.T) {
+	t.Parallel()
+
+	p := &pipeline.Pipeline{
+		Tasks: []*pipeline.Asset{
+			{Name: "default"},
+			{Name: "oncall-only", NotifyOnFailure: []string{"oncall"}},
+			{Name: "silent", NotifyOnFailure: []string{}},
+		},
+	}
+
+	failedRun := func(assets ...string) *report.Run {
+		run := &report.Run{Status: report.StatusFailed}
+		for _, asset := range assets {
+			run.Instances = append(run.Instances, &report.Instance{ID: asset, Asset: asset, Status: scheduler.Failed})
+		}
+		return run
+	}
+
+	tests := []struct {
+		name         string
+		notification string
+		run          *report.Run
+		want         bool
+	}{
+		{
+			name:         "successful runs are always sent",
+			notification: "team",
+			run:          &report.Run{Status: report.StatusSucceeded},
+			want:         true,
+		},
+		{
+			name:         "failures of assets without overrides are sent everywhere",
+			notification: "team",
+			run:          failedRun("default"),
+			want:         true,
+		},
+		{
+			name:         "failures are sent to the notifications of the override",
+			notification: "oncall",
+			run:          failedRun("oncall-only"),
+			want:         true,
+		},
+		{
+			name:         "failures are not sent to the notifications outside the override",
+			notification: "team",
+			run:          failedRun("oncall-only", "silent"),
+			want:         false,
+		},
+		{
+			name:         "any failed asset that allows the notification is enough",
+			notification: "team",
+			run:          failedRun("silent", "default"),
+			want:         true,
+		},
+		{
+			name:         "failures of the backfill intervals are checked",
+			notification: "team",
+			run:          report.NewBackfill("pipeline", []*report.Run{failedRun("silent"), failedRun("default")}),
+			want:         true,
+		},
+		{
+			name:         "backfill is not sent if none of the failed assets allows it",
+			notification: "team",
+			run:          report.NewBackfill("pipeline", []*report.Run{failedRun("silent"), failedRun("oncall-only")}),
+			want:         false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, shouldNotify(p, tt.notification, tt.run))
+		})
+	}
+}
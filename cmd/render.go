@@ -11,6 +11,7 @@ import (
 	"github.com/datablast-analytics/blast/pkg/executor"
 	"github.com/datablast-analytics/blast/pkg/pipeline"
 	"github.com/datablast-analytics/blast/pkg/query"
+	"github.com/datablast-analytics/blast/pkg/snowflake"
 	"github.com/urfave/cli/v2"
 )
 
@@ -26,6 +27,7 @@ func Render() *cli.Command {
 					Renderer: query.DefaultJinjaRenderer,
 				},
 				bqMaterializer: &bigquery.Materializer{},
+				sfMaterializer: &snowflake.Materializer{},
 				builder:        builder,
 				writer:         os.Stdout,
 			}
@@ -50,6 +52,7 @@ type taskCreator interface {
 type RenderCommand struct {
 	extractor      queryExtractor
 	bqMaterializer queryMaterializer
+	sfMaterializer queryMaterializer
 	builder        taskCreator
 
 	writer io.Writer
@@ -72,6 +75,24 @@ func (r *RenderCommand) Run(taskPath string) error {
 		return cli.Exit("", 1)
 	}
 
+	// the Snowflake assets are rendered by the same builder the run command executes them with
+	if task.Type == executor.TaskTypeSnowflakeQuery {
+		qq, err := snowflake.NewQueryBuilder(r.extractor, r.sfMaterializer).Build(task)
+		if err != nil {
+			errorPrinter.Printf("Failed to render the query: %v\n", err.Error())
+			return cli.Exit("", 1)
+		}
+
+		if qq == nil {
+			qq = &query.Query{}
+		}
+
+		qq.Query = highlightCode(qq.Query, "sql")
+		_, err = r.writer.Write([]byte(fmt.Sprintf("%s\n", qq)))
+
+		return err
+	}
+
 	queries, err := r.extractor.ExtractQueriesFromFile(task.ExecutableFile.Path)
 	if err != nil {
 		errorPrinter.Printf("Failed to extract queries from file: %v\n", err.Error())
@@ -80,8 +101,12 @@ func (r *RenderCommand) Run(taskPath string) error {
 
 	qq := queries[0]
 
-	if task.Type == executor.TaskTypeBigqueryQuery {
-		materialized, err := r.bqMaterializer.Render(task, qq.Query)
+	materializers := map[pipeline.AssetType]queryMaterializer{
+		executor.TaskTypeBigqueryQuery: r.bqMaterializer,
+	}
+
+	if materializer, ok := materializers[task.Type]; ok {
+		materialized, err := materializer.Render(task, qq.Query)
 		if err != nil {
 			errorPrinter.Printf("Failed to materialize the query: %v\n", err.Error())
 			return cli.Exit("", 1)
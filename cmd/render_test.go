@@ -65,6 +65,25 @@ func TestRenderCommand_Run(t *testing.T) {
 		},
 	}
 
+	sfAsset := &pipeline.Asset{
+		Name: "asset2",
+		Type: executor.TaskTypeSnowflakeQuery,
+		ExecutableFile: pipeline.ExecutableFile{
+			Path: "/path/to/executable3",
+		},
+	}
+
+	sfTableAsset := &pipeline.Asset{
+		Name: "asset3",
+		Type: executor.TaskTypeSnowflakeQuery,
+		ExecutableFile: pipeline.ExecutableFile{
+			Path: "/path/to/executable4",
+		},
+		Materialization: pipeline.Materialization{
+			Type: pipeline.MaterializationTypeTable,
+		},
+	}
+
 	nonBqAsset := &pipeline.Asset{
 		Name: "non-bq",
 		Type: executor.TaskTypeEmpty,
@@ -76,6 +95,7 @@ func TestRenderCommand_Run(t *testing.T) {
 	type fields struct {
 		extractor      *mockExtractor
 		bqMaterializer *mockMaterializer
+		sfMaterializer *mockMaterializer
 		builder        *mockBuilder
 		writer         *mockWriter
 	}
@@ -168,6 +188,57 @@ func TestRenderCommand_Run(t *testing.T) {
 			},
 			wantErr: assert.NoError,
 		},
+		{
+			name: "should materialize if asset is a snowflake query",
+			args: args{
+				taskPath: "/path/to/asset",
+			},
+			setup: func(f *fields) {
+				f.builder.On("CreateTaskFromFile", "/path/to/asset").
+					Return(sfTableAsset, nil)
+
+				f.extractor.On("ExtractQueriesFromFile", sfTableAsset.ExecutableFile.Path).
+					Return([]*query.Query{{Query: "SELECT * FROM table1"}}, nil)
+
+				f.sfMaterializer.On("Render", sfTableAsset, "SELECT * FROM table1").
+					Return("some-materialized-snowflake-query", nil)
+
+				f.writer.On("Write", []byte("some-materialized-snowflake-query\n")).
+					Return(0, nil)
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "should render multiple snowflake queries as they are if there is no materialization",
+			args: args{
+				taskPath: "/path/to/asset",
+			},
+			setup: func(f *fields) {
+				f.builder.On("CreateTaskFromFile", "/path/to/asset").
+					Return(sfAsset, nil)
+
+				f.extractor.On("ExtractQueriesFromFile", sfAsset.ExecutableFile.Path).
+					Return([]*query.Query{{Query: "USE DATABASE db1; SELECT * FROM table1;"}}, nil)
+
+				f.writer.On("Write", []byte("USE DATABASE db1; SELECT * FROM table1;\n")).
+					Return(0, nil)
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "should fail like the run command for multiple materialized snowflake queries",
+			args: args{
+				taskPath: "/path/to/asset",
+			},
+			setup: func(f *fields) {
+				f.builder.On("CreateTaskFromFile", "/path/to/asset").
+					Return(sfTableAsset, nil)
+
+				f.extractor.On("ExtractQueriesFromFile", sfTableAsset.ExecutableFile.Path).
+					Return([]*query.Query{{Query: "SELECT * FROM table1; SELECT * FROM table2;"}}, nil)
+			},
+			wantErr: assert.Error,
+		},
 		{
 			name: "should skip materialization if asset is a not bigquery query",
 			args: args{
@@ -194,6 +265,7 @@ func TestRenderCommand_Run(t *testing.T) {
 			f := &fields{
 				extractor:      new(mockExtractor),
 				bqMaterializer: new(mockMaterializer),
+				sfMaterializer: new(mockMaterializer),
 				builder:        new(mockBuilder),
 				writer:         new(mockWriter),
 			}
@@ -205,6 +277,7 @@ func TestRenderCommand_Run(t *testing.T) {
 			render := &RenderCommand{
 				extractor:      f.extractor,
 				bqMaterializer: f.bqMaterializer,
+				sfMaterializer: f.sfMaterializer,
 				builder:        f.builder,
 				writer:         f.writer,
 			}
@@ -212,6 +285,7 @@ func TestRenderCommand_Run(t *testing.T) {
 			tt.wantErr(t, render.Run(tt.args.taskPath))
 			f.extractor.AssertExpectations(t)
 			f.bqMaterializer.AssertExpectations(t)
+			f.sfMaterializer.AssertExpectations(t)
 			f.builder.AssertExpectations(t)
 			f.writer.AssertExpectations(t)
 		})
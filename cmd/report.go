@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"io"
+	"strings"
+
+	"github.com/datablast-analytics/blast/pkg/report"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+const (
+	reportFormatJSON  = "json"
+	reportFormatJUnit = "junit"
+)
+
+var reportWriters = map[string]func(w io.Writer, r *report.Report) error{
+	reportFormatJSON:  report.WriteJSON,
+	reportFormatJUnit: report.WriteJUnit,
+}
+
+type reportOutput struct {
+	format string
+	path   string
+}
+
+// parseReportOutputs parses the values of the '--report' flag, each value is in the '<format>=<path>' format.
+func parseReportOutputs(values []string) ([]reportOutput, error) {
+	outputs := make([]reportOutput, 0, len(values))
+	for _, value := range values {
+		format, path, found := strings.Cut(value, "=")
+		format = strings.ToLower(strings.TrimSpace(format))
+		path = strings.TrimSpace(path)
+		if !found || path == "" {
+			return nil, errors.Errorf("invalid report '%s', the report must be given in the '<format>=<path>' format, e.g. 'json=report.json'", value)
+		}
+
+		if _, ok := reportWriters[format]; !ok {
+			return nil, errors.Errorf("unsupported report format '%s', the supported formats are '%s' and '%s'", format, reportFormatJSON, reportFormatJUnit)
+		}
+
+		outputs = append(outputs, reportOutput{format: format, path: path})
+	}
+
+	return outputs, nil
+}
+
+func writeReports(fs afero.Fs, outputs []reportOutput, summaries []*runSummary, pipelineName string) error {
+	if len(outputs) == 0 {
+		return nil
+	}
+
+	r := &report.Report{Runs: make([]*report.Run, 0, len(summaries))}
+	for _, summary := range summaries {
+		if summary == nil {
+			continue
+		}
+
+		r.Runs = append(r.Runs, summary.report(pipelineName))
+	}
+
+	for _, output := range outputs {
+		file, err := fs.Create(output.path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create the %s report", output.format)
+		}
+
+		err = reportWriters[output.format](file, r)
+		closeErr := file.Close()
+		if err != nil {
+			return err
+		}
+		if closeErr != nil {
+			return errors.Wrapf(closeErr, "failed to write the %s report", output.format)
+		}
+
+		infoPrinter.Printf("The %s report is written to '%s'.\n", output.format, output.path)
+	}
+
+	return nil
+}
+
+func (r *runSummary) report(pipelineName string) *report.Run {
+	return report.NewRun(
+		r.runID,
+		pipelineName,
+		r.interval.Start,
+		r.interval.End,
+		r.startedAt,
+		r.duration,
+		r.scheduler.GetTaskInstances(),
+		r.results,
+	)
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseReportOutputs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		values  []string
+		want    []reportOutput
+		wantErr bool
+	}{
+		{
+			name:   "no reports",
+			values: nil,
+			want:   []reportOutput{},
+		},
+		{
+			name:   "multiple reports are parsed",
+			values: []string{"json=out/report.json", "JUnit = report.xml"},
+			want: []reportOutput{
+				{format: "json", path: "out/report.json"},
+				{format: "junit", path: "report.xml"},
+			},
+		},
+		{
+			name:    "missing path is rejected",
+			values:  []string{"json"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown format is rejected",
+			values:  []string{"html=report.html"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseReportOutputs(tt.values)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
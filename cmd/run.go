@@ -4,26 +4,43 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	path2 "path"
+	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/datablast-analytics/blast/pkg/bigquery"
 	"github.com/datablast-analytics/blast/pkg/config"
 	"github.com/datablast-analytics/blast/pkg/connection"
 	"github.com/datablast-analytics/blast/pkg/date"
+	"github.com/datablast-analytics/blast/pkg/events"
 	"github.com/datablast-analytics/blast/pkg/executor"
+	"github.com/datablast-analytics/blast/pkg/history"
 	"github.com/datablast-analytics/blast/pkg/jinja"
 	"github.com/datablast-analytics/blast/pkg/lint"
 	"github.com/datablast-analytics/blast/pkg/path"
 	"github.com/datablast-analytics/blast/pkg/pipeline"
 	"github.com/datablast-analytics/blast/pkg/python"
 	"github.com/datablast-analytics/blast/pkg/query"
+	"github.com/datablast-analytics/blast/pkg/report"
 	"github.com/datablast-analytics/blast/pkg/scheduler"
+	"github.com/datablast-analytics/blast/pkg/selector"
+	"github.com/datablast-analytics/blast/pkg/sensor"
+	"github.com/datablast-analytics/blast/pkg/snowflake"
+	"github.com/datablast-analytics/blast/pkg/state"
+	"github.com/datablast-analytics/blast/pkg/user"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
 	"github.com/spf13/afero"
 	"github.com/urfave/cli/v2"
+	"go.uber.org/zap"
 )
 
+// estimatedDurationSampleSize is the number of recent executions used to estimate the duration of an instance.
+const estimatedDurationSampleSize = 10
+
 func Run(isDebug *bool) *cli.Command {
 	return &cli.Command{
 		Name:      "run",
@@ -61,11 +78,77 @@ func Run(isDebug *bool) *cli.Command {
 				Aliases: []string{"f"},
 				Usage:   "force the validation even if the environment is a production environment",
 			},
+			&cli.StringFlag{
+				Name:  "resume",
+				Usage: "resume the run with the given ID, only the tasks that did not succeed will be executed",
+			},
+			&cli.BoolFlag{
+				Name:  "resume-last",
+				Usage: "resume the last run, only the tasks that did not succeed will be executed",
+			},
+			&cli.StringSliceFlag{
+				Name:  "select",
+				Usage: "run only the selected assets, e.g. 'asset_a', '+asset_a' with its upstreams, 'asset_a+' with its downstreams, 'tag:finance', 'path:assets/marts/*', 'owner:<email>', 'domain:<name>' or 'meta.<key>:<value>'",
+			},
+			&cli.StringSliceFlag{
+				Name:  "exclude",
+				Usage: "skip the selected assets, supports the same syntax as '--select'",
+			},
+			&cli.BoolFlag{
+				Name:  "backfill",
+				Usage: "split the date range into the intervals of the pipeline schedule and run the pipeline once for each interval",
+			},
+			&cli.IntFlag{
+				Name:  "backfill-parallelism",
+				Usage: "number of intervals to run in parallel during a backfill, each interval uses its own set of workers",
+				Value: 1,
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "print the execution plan with the queries and the connections that would be used, without running anything",
+			},
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "the maximum duration the whole run can take, e.g. 30m or 2h, the unfinished tasks will be cancelled afterwards",
+			},
+			&cli.StringSliceFlag{
+				Name:  "report",
+				Usage: "write a report of the task instances after the run, e.g. 'json=report.json' or 'junit=report.xml', can be given multiple times",
+			},
+			&cli.StringFlag{
+				Name:  "events",
+				Usage: "stream the events of the run as JSON lines to the given file, '-' streams them to stdout and moves the rest of the output to stderr",
+			},
 		},
 		Action: func(c *cli.Context) error {
 			logger := makeLogger(*isDebug)
 
+			// the events are opened before anything is printed, stdout must only contain the events when they are streamed there
+			var eventBus *events.Bus
+			if c.IsSet("events") {
+				eventsWriter, closeEvents, err := openEventsWriter(c.String("events"))
+				if err != nil {
+					errorPrinter.Printf("Failed to open the events output: %v\n", err)
+					return cli.Exit("", 1)
+				}
+				defer closeEvents()
+
+				eventBus = events.NewBus()
+				eventBus.Subscribe(eventsWriter)
+			}
+
+			stateStore := state.NewStore(afero.NewOsFs(), user.NewConfigManager(afero.NewOsFs()))
+			previousRun, err := loadPreviousRun(c, stateStore)
+			if err != nil {
+				errorPrinter.Printf("Failed to load the previous run: %v\n", err)
+				return cli.Exit("", 1)
+			}
+
 			inputPath := c.Args().Get(0)
+			if inputPath == "" && previousRun != nil {
+				inputPath = previousRun.Path
+			}
+
 			if inputPath == "" {
 				errorPrinter.Printf("Please give a task or pipeline path: blast-cli run <path to the task definition>)\n")
 				return cli.Exit("", 1)
@@ -91,6 +174,27 @@ func Run(isDebug *bool) *cli.Command {
 				return cli.Exit("", 1)
 			}
 
+			runID := uuid.New().String()
+			if previousRun != nil {
+				runID = previousRun.ID
+				startDate = previousRun.StartDate
+				endDate = previousRun.EndDate
+
+				if c.String("environment") == "" && previousRun.Environment != "" {
+					err = c.Set("environment", previousRun.Environment)
+					if err != nil {
+						errorPrinter.Printf("Failed to use the environment of the previous run: %v\n", err)
+						return cli.Exit("", 1)
+					}
+				}
+			}
+
+			absoluteInputPath, err := filepath.Abs(inputPath)
+			if err != nil {
+				errorPrinter.Printf("Failed to resolve the path '%s': %v\n", inputPath, err)
+				return cli.Exit("", 1)
+			}
+
 			pipelinePath := inputPath
 
 			runningForATask := isPathReferencingTask(inputPath)
@@ -136,12 +240,6 @@ func Run(isDebug *bool) *cli.Command {
 				return err
 			}
 
-			connectionManager, err := connection.NewManagerFromConfig(cm)
-			if err != nil {
-				errorPrinter.Printf("Failed to register connections: %v\n", err)
-				return cli.Exit("", 1)
-			}
-
 			foundPipeline, err := builder.CreatePipelineFromPath(pipelinePath)
 			if err != nil {
 				errorPrinter.Println("failed to build pipeline, are you sure you have referred the right path?")
@@ -150,6 +248,13 @@ func Run(isDebug *bool) *cli.Command {
 				return cli.Exit("", 1)
 			}
 
+			// the assets run on their own are not linted, the pools must still be valid for them to run at all
+			err = scheduler.ValidateConcurrencyLimits(foundPipeline)
+			if err != nil {
+				errorPrinter.Printf("Invalid pipeline: %v\n", err)
+				return cli.Exit("", 1)
+			}
+
 			if !runningForATask {
 				rules, err := lint.GetRules(logger, fs)
 				if err != nil {
@@ -165,51 +270,104 @@ func Run(isDebug *bool) *cli.Command {
 				}
 			}
 
-			s := scheduler.NewScheduler(logger, foundPipeline)
+			var selectedAssets []*pipeline.Asset
+			if c.IsSet("select") || c.IsSet("exclude") {
+				if runningForATask {
+					errorPrinter.Println("The '--select' and '--exclude' flags can only be used when running the whole pipeline.")
+					return cli.Exit("", 1)
+				}
 
-			infoPrinter.Printf("\nStarting the pipeline execution...\n\n")
+				if previousRun != nil {
+					errorPrinter.Println("The '--select' and '--exclude' flags cannot be used when resuming a run, the resumed run keeps its original selection.")
+					return cli.Exit("", 1)
+				}
 
-			if task != nil {
-				logger.Debug("marking single task to run: ", task.Name)
-				s.MarkAll(scheduler.Succeeded)
-				s.MarkTask(task, scheduler.Pending, runDownstreamTasks)
+				selectedAssets, err = selector.Select(foundPipeline, c.StringSlice("select"), c.StringSlice("exclude"))
+				if err != nil {
+					errorPrinter.Printf("Failed to resolve the selected assets: %v\n", err)
+					return cli.Exit("", 1)
+				}
+
+				infoPrinter.Printf("\nSelected %d of the %d assets in the pipeline.\n", len(selectedAssets), len(foundPipeline.Tasks))
 			}
 
-			mainExecutors, err := setupExecutors(s, connectionManager, startDate, endDate)
+			reports, err := parseReportOutputs(c.StringSlice("report"))
 			if err != nil {
-				errorPrinter.Printf(err.Error())
+				errorPrinter.Printf("Please give a valid report: %v\n", err)
 				return cli.Exit("", 1)
 			}
 
-			ex := executor.NewConcurrent(logger, mainExecutors, c.Int("workers"))
-			ex.Start(s.WorkQueue, s.Results)
+			params := &runParameters{
+				logger:             logger,
+				pipeline:           foundPipeline,
+				task:               task,
+				runDownstreamTasks: runDownstreamTasks,
+				selectedAssets:     selectedAssets,
+				estimatedDurations: loadEstimatedDurations(logger, foundPipeline.Name),
+				previousRun:        previousRun,
+				environment:        cm.SelectedEnvironmentName,
+				trigger:            history.TriggerManual,
+				path:               absoluteInputPath,
+				workers:            c.Int("workers"),
+				stateStore:         stateStore,
+				reports:            reports,
+				events:             eventBus,
+			}
 
-			start := time.Now()
-			results := s.Run(context.Background())
-			duration := time.Since(start)
+			// a resumed run keeps its trigger, e.g. a scheduled interval that succeeds after a resume is not run again
+			if previousRun != nil && previousRun.Trigger != "" {
+				params.trigger = previousRun.Trigger
+			}
 
-			successPrinter.Printf("\n\nExecuted %d tasks in %s\n", len(results), duration.Truncate(time.Millisecond).String())
-			errors := make([]*scheduler.TaskExecutionResult, 0)
-			for _, res := range results {
-				if res.Error != nil {
-					errors = append(errors, res)
+			if c.Bool("dry-run") {
+				if c.Bool("backfill") {
+					errorPrinter.Println("The '--dry-run' flag cannot be used together with '--backfill'.")
+					return cli.Exit("", 1)
 				}
+
+				return printExecutionPlan(params, date.Interval{Start: startDate, End: endDate})
 			}
 
-			if len(errors) > 0 {
-				errorPrinter.Printf("\nFailed tasks: %d\n", len(errors))
-				for _, t := range errors {
-					errorPrinter.Printf("  - %s\n", t.Instance.GetAsset().Name)
-					errorPrinter.Printf("    └── %s\n\n", t.Error.Error())
-				}
+			params.connectionManager, err = connection.NewManagerFromConfig(cm)
+			if err != nil {
+				errorPrinter.Printf("Failed to register connections: %v\n", err)
+				return cli.Exit("", 1)
+			}
 
-				upstreamFailedTasks := s.GetTaskInstancesByStatus(scheduler.UpstreamFailed)
-				if len(upstreamFailedTasks) > 0 {
-					errorPrinter.Printf("The following tasks are skipped due to their upstream failing:\n")
-					for _, t := range upstreamFailedTasks {
-						errorPrinter.Printf("  - %s\n", t.GetAsset().Name)
-					}
-				}
+			params.notifiers, err = setupNotifiers(foundPipeline, cm.SelectedEnvironment.Connections)
+			if err != nil {
+				errorPrinter.Printf("Failed to set up the notifications: %v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			ctx, cancel := cancellableRunContext(c.Duration("timeout"))
+			defer cancel()
+
+			if c.Bool("backfill") {
+				return runBackfill(ctx, params, startDate, endDate, c.Int("backfill-parallelism"))
+			}
+
+			summary, err := executeRun(ctx, params, runID, date.Interval{Start: startDate, End: endDate})
+			if err != nil {
+				errorPrinter.Println(err.Error())
+				return cli.Exit("", 1)
+			}
+
+			if summary.nothingToRun {
+				successPrinter.Println("There are no tasks left to run.")
+			} else {
+				printRunSummary(summary)
+				sendNotifications(foundPipeline, params.notifiers, summary.report(foundPipeline.Name))
+			}
+
+			err = writeReports(afero.NewOsFs(), params.reports, []*runSummary{summary}, foundPipeline.Name)
+			if err != nil {
+				errorPrinter.Printf("Failed to write the report: %v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			if !summary.succeeded() {
+				return cli.Exit("", 1)
 			}
 
 			return nil
@@ -217,16 +375,360 @@ func Run(isDebug *bool) *cli.Command {
 	}
 }
 
-func setupExecutors(s *scheduler.Scheduler, conn *connection.Manager, startDate, endDate time.Time) (map[pipeline.AssetType]executor.Config, error) {
-	mainExecutors := executor.DefaultExecutorsV2
+type runParameters struct {
+	logger             *zap.SugaredLogger
+	pipeline           *pipeline.Pipeline
+	task               *pipeline.Asset
+	runDownstreamTasks bool
+	selectedAssets     []*pipeline.Asset
+	estimatedDurations map[string]time.Duration
+	previousRun        *state.RunState
+	connectionManager  *connection.Manager
+	environment        string
+	trigger            string
+	path               string
+	workers            int
+	stateStore         *state.Store
+	reports            []reportOutput
+	events             *events.Bus
+	notifiers          []pipelineNotifier
+}
+
+type runSummary struct {
+	runID        string
+	interval     date.Interval
+	scheduler    *scheduler.Scheduler
+	results      []*scheduler.TaskExecutionResult
+	startedAt    time.Time
+	duration     time.Duration
+	nothingToRun bool
+}
+
+func (r *runSummary) failedResults() []*scheduler.TaskExecutionResult {
+	failed := make([]*scheduler.TaskExecutionResult, 0)
+	for _, res := range r.results {
+		if res.Error != nil && res.Instance.GetStatus() == scheduler.Failed {
+			failed = append(failed, res)
+		}
+	}
+
+	return failed
+}
+
+func (r *runSummary) warningResults() []*scheduler.TaskExecutionResult {
+	warnings := make([]*scheduler.TaskExecutionResult, 0)
+	for _, res := range r.results {
+		if res.IsWarning() && res.Instance.GetStatus() == scheduler.Succeeded {
+			warnings = append(warnings, res)
+		}
+	}
+
+	return warnings
+}
+
+func (r *runSummary) status() string {
+	switch {
+	case r.scheduler.InstanceCountByStatus(scheduler.Failed) > 0 || r.scheduler.InstanceCountByStatus(scheduler.UpstreamFailed) > 0:
+		return report.StatusFailed
+	case r.scheduler.InstanceCountByStatus(scheduler.Cancelled) > 0:
+		return report.StatusCancelled
+	default:
+		return report.StatusSucceeded
+	}
+}
+
+func (r *runSummary) succeeded() bool {
+	return r.scheduler.InstanceCountByStatus(scheduler.Failed) == 0 &&
+		r.scheduler.InstanceCountByStatus(scheduler.UpstreamFailed) == 0 &&
+		r.scheduler.InstanceCountByStatus(scheduler.Cancelled) == 0
+}
+
+// newRunScheduler creates a scheduler where only the instances that should run are pending, e.g. the ones that did not
+// succeed in the resumed run, the selected task or the selected assets. The assets that are not scheduled for the
+// weekday of the interval are skipped.
+func newRunScheduler(params *runParameters, interval date.Interval) *scheduler.Scheduler {
+	s := scheduler.NewScheduler(params.logger, params.pipeline)
+	s.SetEstimatedDurations(params.estimatedDurations)
+
+	if params.previousRun != nil {
+		s.RestoreState(params.previousRun.Instances)
+	} else if params.task != nil {
+		params.logger.Debug("marking single task to run: ", params.task.Name)
+		s.MarkAll(scheduler.Succeeded)
+		s.MarkTask(params.task, scheduler.Pending, params.runDownstreamTasks)
+	} else if params.selectedAssets != nil {
+		s.MarkAll(scheduler.Succeeded)
+		for _, asset := range params.selectedAssets {
+			s.MarkTask(asset, scheduler.Pending, false)
+		}
+	}
+
+	s.SkipAssetsNotScheduledOn(interval.Start.Weekday())
+
+	return s
+}
+
+// executeRun runs the pipeline once for the given interval, the state and the history of the run are persisted
+// regardless of its outcome.
+func executeRun(ctx context.Context, params *runParameters, runID string, interval date.Interval) (*runSummary, error) {
+	if params.previousRun != nil {
+		infoPrinter.Printf("\nResuming the run '%s', the tasks that succeeded previously will be skipped.\n", runID)
+	}
+
+	s := newRunScheduler(params, interval)
+	summary := &runSummary{
+		runID:     runID,
+		interval:  interval,
+		scheduler: s,
+	}
+
+	if s.InstanceCountByStatus(scheduler.Pending) == 0 {
+		summary.nothingToRun = true
+		return summary, nil
+	}
+
+	runState := &state.RunState{
+		ID:          runID,
+		Pipeline:    params.pipeline.Name,
+		Path:        params.path,
+		Environment: params.environment,
+		Trigger:     params.trigger,
+		StartDate:   interval.Start,
+		EndDate:     interval.End,
+		Instances:   s.GetInstanceStatuses(),
+	}
+
+	err := params.stateStore.Save(runState)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to save the run state")
+	}
+
+	// the state is saved after every result, so that a run that is killed can be resumed from where it stopped
+	s.SetStateListener(func(statuses map[string]scheduler.TaskInstanceStatus) {
+		runState.Instances = statuses
+		err := params.stateStore.Save(runState)
+		if err != nil {
+			errorPrinter.Printf("Failed to save the run state: %v\n", err)
+		}
+	})
+
+	mainExecutors, err := setupExecutors(s, params.connectionManager, python.NewLocalOperator(map[string]string{}), interval.Start, interval.End)
+	if err != nil {
+		return nil, err
+	}
+
+	infoPrinter.Printf("\nStarting the pipeline execution with the run ID '%s'...\n\n", runID)
+
+	ex := executor.NewConcurrent(params.logger, mainExecutors, params.workers)
+
+	var publisher events.Publisher
+	if params.events != nil {
+		publisher = params.events.ForRun(runID)
+		s.SetEventPublisher(publisher)
+		ex.SetEventPublisher(publisher)
+		publisher.Publish(&events.Event{
+			Type:      events.RunStarted,
+			Pipeline:  params.pipeline.Name,
+			StartDate: &interval.Start,
+			EndDate:   &interval.End,
+		})
+	}
+
+	ex.Start(ctx, s.WorkQueue, s.Results)
+
+	start := time.Now()
+	summary.startedAt = start
+	summary.results = s.Run(ctx)
+	summary.duration = time.Since(start)
+
+	if publisher != nil {
+		publisher.Publish(&events.Event{
+			Type:            events.RunFinished,
+			Pipeline:        params.pipeline.Name,
+			Status:          summary.status(),
+			DurationSeconds: summary.duration.Seconds(),
+		})
+	}
+
+	runState.Instances = s.GetInstanceStatuses()
+	err = params.stateStore.Save(runState)
+	if err != nil {
+		errorPrinter.Printf("Failed to save the run state: %v\n", err)
+	}
+
+	err = recordRunHistory(&history.Run{
+		ID:          runID,
+		Pipeline:    params.pipeline.Name,
+		Environment: params.environment,
+		Trigger:     params.trigger,
+		StartDate:   interval.Start,
+		EndDate:     interval.End,
+		StartedAt:   start,
+		FinishedAt:  start.Add(summary.duration),
+		Instances:   history.NewInstances(s.GetTaskInstances(), summary.results),
+	})
+	if err != nil {
+		errorPrinter.Printf("Failed to record the run history: %v\n", err)
+	}
+
+	return summary, nil
+}
+
+func printRunSummary(summary *runSummary) {
+	s := summary.scheduler
+	successPrinter.Printf("\n\nExecuted %d tasks in %s\n", len(summary.results), summary.duration.Truncate(time.Millisecond).String())
+
+	retriedTasks := 0
+	for _, res := range summary.results {
+		if res.Attempts > 1 {
+			retriedTasks++
+		}
+	}
+
+	if retriedTasks > 0 {
+		infoPrinter.Printf("Retried tasks: %d\n", retriedTasks)
+	}
+
+	warnings := summary.warningResults()
+	if len(warnings) > 0 {
+		warningPrinter.Printf("\nChecks with warnings: %d\n", len(warnings))
+		for _, t := range warnings {
+			warningPrinter.Printf("  - %s\n", t.Instance.GetHumanID())
+			warningPrinter.Printf("    └── %s\n", t.Error.Error())
+		}
+	}
+
+	failedTasks := summary.failedResults()
+	if len(failedTasks) > 0 {
+		errorPrinter.Printf("\nFailed tasks: %d\n", len(failedTasks))
+		for _, t := range failedTasks {
+			if t.Attempts > 1 {
+				errorPrinter.Printf("  - %s %s\n", t.Instance.GetAsset().Name, faint(fmt.Sprintf("(%d attempts)", t.Attempts)))
+			} else {
+				errorPrinter.Printf("  - %s\n", t.Instance.GetAsset().Name)
+			}
+			errorPrinter.Printf("    └── %s\n\n", t.Error.Error())
+		}
+
+		upstreamFailedTasks := s.GetTaskInstancesByStatus(scheduler.UpstreamFailed)
+		if len(upstreamFailedTasks) > 0 {
+			errorPrinter.Printf("The following tasks are skipped due to their upstream failing:\n")
+			for _, t := range upstreamFailedTasks {
+				errorPrinter.Printf("  - %s\n", t.GetAsset().Name)
+			}
+		}
+	}
+
+	skippedTasks := s.GetTaskInstancesByStatus(scheduler.Skipped)
+	if len(skippedTasks) > 0 {
+		infoPrinter.Printf("\nThe following tasks are skipped since they are not scheduled for %s:\n", summary.interval.Start.Weekday())
+		for _, t := range skippedTasks {
+			infoPrinter.Printf("  - %s\n", t.GetHumanID())
+		}
+	}
+
+	cancelledTasks := s.GetTaskInstancesByStatus(scheduler.Cancelled)
+	if len(cancelledTasks) > 0 {
+		errorPrinter.Printf("\nThe following tasks are cancelled before they could finish:\n")
+		for _, t := range cancelledTasks {
+			errorPrinter.Printf("  - %s\n", t.GetHumanID())
+		}
+	}
+
+	if !summary.succeeded() {
+		infoPrinter.Printf("\nYou can resume this run with: blast-cli run --resume %s\n", summary.runID)
+	}
+}
+
+// cancellableRunContext returns a context that is cancelled either when the process receives an interrupt or when the
+// given timeout is reached, a zero timeout means the run has no time limit.
+func cancellableRunContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), timeout)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		// the default signal behavior is restored after the first one so that a second interrupt kills the process
+		defer signal.Stop(signals)
+
+		select {
+		case <-signals:
+			errorPrinter.Printf("\nReceived an interrupt, cancelling the running tasks...\n")
+			cancel()
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				errorPrinter.Printf("\nThe run has timed out after %s, cancelling the running tasks...\n", timeout)
+			}
+		}
+	}()
+
+	return ctx, cancel
+}
+
+// loadEstimatedDurations reads the average durations of the recent executions from the run history to prioritise the
+// longest paths, the scheduler works without them as well so the failures are only logged.
+func loadEstimatedDurations(logger *zap.SugaredLogger, pipelineName string) map[string]time.Duration {
+	store, err := openHistoryStore()
+	if err != nil {
+		logger.Debugf("failed to open the run history for the estimated durations: %v", err)
+		return nil
+	}
+	defer store.Close()
+
+	durations, err := store.GetAverageDurations(pipelineName, estimatedDurationSampleSize)
+	if err != nil {
+		logger.Debugf("failed to read the estimated durations: %v", err)
+		return nil
+	}
+
+	return durations
+}
+
+func recordRunHistory(run *history.Run) error {
+	store, err := openHistoryStore()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	return store.SaveRun(run)
+}
+
+func loadPreviousRun(c *cli.Context, store *state.Store) (*state.RunState, error) {
+	if c.String("resume") != "" {
+		return store.Load(c.String("resume"))
+	}
+
+	if c.Bool("resume-last") {
+		return store.Last()
+	}
+
+	return nil, nil
+}
+
+type connectionFetcher interface {
+	GetBqConnection(name string) (bigquery.DB, error)
+	GetSfConnection(name string) (snowflake.SfClient, error)
+}
+
+func setupExecutors(s *scheduler.Scheduler, conn connectionFetcher, pythonOperator executor.Operator, startDate, endDate time.Time) (map[pipeline.AssetType]executor.Config, error) {
+	mainExecutors := executor.CopyDefaultExecutors()
 	if s.WillRunTaskOfType(executor.TaskTypePython) {
-		mainExecutors[executor.TaskTypePython][scheduler.TaskInstanceTypeMain] = python.NewLocalOperator(map[string]string{})
+		mainExecutors[executor.TaskTypePython][scheduler.TaskInstanceTypeMain] = pythonOperator
 	}
 
+	renderer := jinja.NewRendererWithStartEndDates(&startDate, &endDate)
 	if s.WillRunTaskOfType(executor.TaskTypeBigqueryQuery) {
 		wholeFileExtractor := &query.WholeFileExtractor{
 			Fs:       fs,
-			Renderer: jinja.NewRendererWithStartEndDates(&startDate, &endDate),
+			Renderer: renderer,
 		}
 
 		bqOperator := bigquery.NewBasicOperator(conn, wholeFileExtractor, bigquery.Materializer{})
@@ -238,6 +740,37 @@ func setupExecutors(s *scheduler.Scheduler, conn *connection.Manager, startDate,
 
 		mainExecutors[executor.TaskTypeBigqueryQuery][scheduler.TaskInstanceTypeMain] = bqOperator
 		mainExecutors[executor.TaskTypeBigqueryQuery][scheduler.TaskInstanceTypeColumnCheck] = bqTestRunner
+		mainExecutors[executor.TaskTypeBigqueryQuery][scheduler.TaskInstanceTypeCustomTest] = bigquery.NewCustomCheckOperator(conn, renderer)
+	}
+
+	if s.WillRunTaskOfType(executor.TaskTypeBigqueryTableSensor) {
+		mainExecutors[executor.TaskTypeBigqueryTableSensor][scheduler.TaskInstanceTypeMain] = sensor.NewOperator(bigquery.NewTableSensor(conn))
+	}
+
+	if s.WillRunTaskOfType(executor.TaskTypeBigqueryQuerySensor) {
+		mainExecutors[executor.TaskTypeBigqueryQuerySensor][scheduler.TaskInstanceTypeMain] = sensor.NewOperator(bigquery.NewQuerySensor(conn, renderer))
+	}
+
+	if s.WillRunTaskOfType(executor.TaskTypeBigqueryPartitionSensor) {
+		mainExecutors[executor.TaskTypeBigqueryPartitionSensor][scheduler.TaskInstanceTypeMain] = sensor.NewOperator(bigquery.NewPartitionSensor(conn, renderer))
+	}
+
+	if s.WillRunTaskOfType(executor.TaskTypeSnowflakeQuery) {
+		sfQueryExtractor := &query.WholeFileExtractor{
+			Fs:       fs,
+			Renderer: renderer,
+		}
+
+		sfOperator := snowflake.NewBasicOperator(conn, sfQueryExtractor, snowflake.Materializer{})
+
+		sfTestRunner, err := snowflake.NewColumnCheckOperator(conn)
+		if err != nil {
+			return nil, err
+		}
+
+		mainExecutors[executor.TaskTypeSnowflakeQuery][scheduler.TaskInstanceTypeMain] = sfOperator
+		mainExecutors[executor.TaskTypeSnowflakeQuery][scheduler.TaskInstanceTypeColumnCheck] = sfTestRunner
+		mainExecutors[executor.TaskTypeSnowflakeQuery][scheduler.TaskInstanceTypeCustomTest] = snowflake.NewCustomCheckOperator(conn, renderer)
 	}
 
 	return mainExecutors, nil
@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"context"
+	path2 "path"
+	"time"
+
+	"github.com/datablast-analytics/blast/pkg/config"
+	"github.com/datablast-analytics/blast/pkg/connection"
+	"github.com/datablast-analytics/blast/pkg/daemon"
+	"github.com/datablast-analytics/blast/pkg/date"
+	"github.com/datablast-analytics/blast/pkg/history"
+	"github.com/datablast-analytics/blast/pkg/lint"
+	"github.com/datablast-analytics/blast/pkg/path"
+	"github.com/datablast-analytics/blast/pkg/pipeline"
+	"github.com/datablast-analytics/blast/pkg/scheduler"
+	"github.com/datablast-analytics/blast/pkg/state"
+	"github.com/datablast-analytics/blast/pkg/user"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+)
+
+func Schedule(isDebug *bool) *cli.Command {
+	return &cli.Command{
+		Name:      "schedule",
+		Usage:     "run the pipelines under the given path on their schedules until the process is stopped",
+		ArgsUsage: "[path to the pipelines]",
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:  "workers",
+				Usage: "number of workers every run uses to run the tasks in parallel",
+				Value: 8,
+			},
+			&cli.StringFlag{
+				Name:    "environment",
+				Aliases: []string{"e", "env"},
+				Usage:   "the environment to use",
+			},
+			&cli.IntFlag{
+				Name:  "max-active-runs",
+				Usage: "number of intervals of a single pipeline that can run at the same time",
+				Value: 1,
+			},
+			&cli.BoolFlag{
+				Name:  "no-catchup",
+				Usage: "only run the latest due interval of every pipeline instead of all the intervals since their start dates",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			logger := makeLogger(*isDebug)
+
+			rootPath := c.Args().Get(0)
+			if rootPath == "" {
+				errorPrinter.Printf("Please give the path to the pipelines: blast schedule <path to the pipelines>\n")
+				return cli.Exit("", 1)
+			}
+
+			pipelinePaths, err := path.GetPipelinePaths(rootPath, pipelineDefinitionFile)
+			if err != nil {
+				errorPrinter.Printf("Failed to find the pipelines under '%s': %v\n", rootPath, err)
+				return cli.Exit("", 1)
+			}
+
+			if len(pipelinePaths) == 0 {
+				errorPrinter.Printf("No pipelines are found under '%s'.\n", rootPath)
+				return cli.Exit("", 1)
+			}
+
+			pipelines := make([]*pipeline.Pipeline, 0, len(pipelinePaths))
+			for _, pipelinePath := range pipelinePaths {
+				p, err := builder.CreatePipelineFromPath(pipelinePath)
+				if err != nil {
+					errorPrinter.Printf("Failed to build the pipeline at '%s': %v\n", pipelinePath, err)
+					return cli.Exit("", 1)
+				}
+
+				pipelines = append(pipelines, p)
+			}
+
+			rules, err := lint.GetRules(logger, fs)
+			if err != nil {
+				errorPrinter.Printf("An error occurred while linting the pipelines: %v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			linter := lint.NewLinter(path.GetPipelinePaths, builder, rules, logger)
+			res, err := linter.LintPipelines(pipelines)
+			err = reportLintErrors(res, err, lint.Printer{RootCheckPath: rootPath})
+			if err != nil {
+				return cli.Exit("", 1)
+			}
+
+			stateStore := state.NewStore(afero.NewOsFs(), user.NewConfigManager(afero.NewOsFs()))
+			paramsByPipeline := make(map[string]*runParameters, len(pipelines))
+			for i, p := range pipelines {
+				params, err := newScheduledRunParameters(logger, c, p, pipelinePaths[i], stateStore)
+				if err != nil {
+					errorPrinter.Printf("Failed to prepare the pipeline '%s': %v\n", p.Name, err)
+					return cli.Exit("", 1)
+				}
+
+				paramsByPipeline[p.Name] = params
+			}
+
+			d := daemon.New(logger, func(ctx context.Context, p *pipeline.Pipeline, interval date.Interval) error {
+				return runScheduledInterval(ctx, paramsByPipeline[p.Name], interval)
+			}, daemon.Config{
+				MaxActiveRuns: c.Int("max-active-runs"),
+				Catchup:       !c.Bool("no-catchup"),
+			})
+
+			scheduled := 0
+			for _, p := range pipelines {
+				if p.Schedule == "" {
+					infoPrinter.Printf("Skipping the pipeline '%s' since it does not have a schedule.\n", p.Name)
+					continue
+				}
+
+				lastRunEndDate, failedRuns, err := loadLastRunEndDate(p.Name, paramsByPipeline[p.Name].environment)
+				if err != nil {
+					errorPrinter.Printf("Failed to read the last run of the pipeline '%s': %v\n", p.Name, err)
+					return cli.Exit("", 1)
+				}
+
+				// the daemon continues after the last successful run, the failed intervals before it are only resumed by hand
+				for _, run := range failedRuns {
+					warningPrinter.Printf(
+						"The interval %s - %s of the pipeline '%s' has failed and it will not be run again, you can resume the run with: blast-cli run --resume %s\n",
+						run.StartDate.Format(historyTimeFormat), run.EndDate.Format(historyTimeFormat), p.Name, run.ID,
+					)
+				}
+
+				first, err := d.Add(p, lastRunEndDate)
+				if err != nil {
+					errorPrinter.Printf("Failed to schedule the pipeline '%s': %v\n", p.Name, err)
+					return cli.Exit("", 1)
+				}
+
+				scheduled++
+				infoPrinter.Printf("Scheduled the pipeline '%s' with the schedule '%s', the first interval is %s - %s.\n", p.Name, p.Schedule, first.Start.Format(historyTimeFormat), first.End.Format(historyTimeFormat))
+			}
+
+			if scheduled == 0 {
+				errorPrinter.Println("None of the pipelines have a schedule.")
+				return cli.Exit("", 1)
+			}
+
+			ctx, cancel := cancellableRunContext(0)
+			defer cancel()
+
+			d.Start(ctx)
+
+			return nil
+		},
+	}
+}
+
+// newScheduledRunParameters prepares the parameters shared by all the runs of a scheduled pipeline, the connections are
+// registered once for the lifetime of the daemon.
+func newScheduledRunParameters(logger *zap.SugaredLogger, c *cli.Context, p *pipeline.Pipeline, pipelinePath string, stateStore *state.Store) (*runParameters, error) {
+	err := scheduler.ValidateConcurrencyLimits(p)
+	if err != nil {
+		return nil, err
+	}
+
+	cm, err := config.LoadOrCreate(afero.NewOsFs(), path2.Join(pipelinePath, ".blast.yml"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load the config file")
+	}
+
+	// the daemon is not interactive, therefore the production environments are not confirmed as in the run command
+	if env := c.String("environment"); env != "" {
+		err = cm.SelectEnvironment(env)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	connectionManager, err := connection.NewManagerFromConfig(cm)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to register the connections")
+	}
+
+	notifiers, err := setupNotifiers(p, cm.SelectedEnvironment.Connections)
+	if err != nil {
+		return nil, err
+	}
+
+	return &runParameters{
+		logger:             logger,
+		pipeline:           p,
+		estimatedDurations: loadEstimatedDurations(logger, p.Name),
+		connectionManager:  connectionManager,
+		environment:        cm.SelectedEnvironmentName,
+		trigger:            history.TriggerSchedule,
+		path:               pipelinePath,
+		workers:            c.Int("workers"),
+		stateStore:         stateStore,
+		notifiers:          notifiers,
+	}, nil
+}
+
+func runScheduledInterval(ctx context.Context, params *runParameters, interval date.Interval) error {
+	summary, err := executeRun(ctx, params, uuid.New().String(), interval)
+	if err != nil {
+		return err
+	}
+
+	if summary.nothingToRun {
+		return nil
+	}
+
+	sendNotifications(params.pipeline, params.notifiers, summary.report(params.pipeline.Name))
+
+	if !summary.succeeded() {
+		return errors.Errorf(
+			"%d instances have failed, you can resume the run with: blast-cli run --resume %s",
+			summary.scheduler.InstanceCountByStatus(scheduler.Failed),
+			summary.runID,
+		)
+	}
+
+	successPrinter.Printf(
+		"The pipeline '%s' has succeeded for the interval %s - %s in %s.\n",
+		params.pipeline.Name,
+		interval.Start.Format(historyTimeFormat),
+		interval.End.Format(historyTimeFormat),
+		summary.duration.Truncate(time.Millisecond),
+	)
+
+	return nil
+}
+
+// loadLastRunEndDate returns the end of the last successful scheduled run, together with the failed scheduled runs
+// before it.
+func loadLastRunEndDate(pipelineName, environment string) (time.Time, []*history.Run, error) {
+	store, err := openHistoryStore()
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	defer store.Close()
+
+	last, err := store.GetLastRunEndDate(pipelineName, environment)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+
+	failed, err := store.GetFailedScheduledRuns(pipelineName, environment, last)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+
+	return last, failed, nil
+}
@@ -2,6 +2,13 @@
 
 name: dashboard.hello_bq
 type: bq.sql
+owner: analytics@example.com
+domain: dashboards
+tags:
+   - daily
+   - reporting
+meta:
+   sla: 6h
 
 depends:
    - hello_python
@@ -2,6 +2,9 @@
 
 name: nested1
 type: bq.sql
+owner: finance@example.com
+tags:
+   - finance
 
 depends:
    - dashboard.hello_bq
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/datablast-analytics/blast/pkg/python"
+	"github.com/spf13/afero"
+	"github.com/urfave/cli/v2"
+)
+
+func Venv() *cli.Command {
+	return &cli.Command{
+		Name:  "venv",
+		Usage: "manage the virtualenvs of the Python assets under ~/.blast/virtualenvs",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "list",
+				Usage: "list the virtualenvs starting from the most recently used one",
+				Action: func(c *cli.Context) error {
+					return newVenvCommand().List()
+				},
+			},
+			{
+				Name:      "rebuild",
+				Usage:     "install the requirements of the given virtualenvs from scratch",
+				ArgsUsage: "[virtualenv names]",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "all",
+						Usage: "rebuild all the virtualenvs",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					ctx, cancel := cancellableRunContext(0)
+					defer cancel()
+
+					return newVenvCommand().Rebuild(ctx, c.Args().Slice(), c.Bool("all"))
+				},
+			},
+			{
+				Name:  "prune",
+				Usage: "remove the virtualenvs that are not used recently",
+				Flags: []cli.Flag{
+					&cli.DurationFlag{
+						Name:  "unused-for",
+						Usage: "remove the virtualenvs that are not used for the given duration, e.g. 720h",
+						Value: 30 * 24 * time.Hour,
+					},
+				},
+				Action: func(c *cli.Context) error {
+					return newVenvCommand().Prune(time.Now().Add(-c.Duration("unused-for")))
+				},
+			},
+		},
+	}
+}
+
+type virtualEnvManager interface {
+	List() ([]*python.VirtualEnv, error)
+	Rebuild(ctx context.Context, name string) error
+	Prune(unusedSince time.Time) ([]*python.VirtualEnv, error)
+}
+
+type VenvCommand struct {
+	manager        virtualEnvManager
+	infoPrinter    printer
+	errorPrinter   printer
+	successPrinter printer
+}
+
+func newVenvCommand() *VenvCommand {
+	return &VenvCommand{
+		manager:        python.NewVirtualEnvManager(afero.NewOsFs()),
+		infoPrinter:    infoPrinter,
+		errorPrinter:   errorPrinter,
+		successPrinter: successPrinter,
+	}
+}
+
+func (r *VenvCommand) List() error {
+	venvs, err := r.manager.List()
+	if err != nil {
+		r.errorPrinter.Printf("Failed to list the virtualenvs: %v\n", err)
+		return cli.Exit("", 1)
+	}
+
+	if len(venvs) == 0 {
+		r.infoPrinter.Println("There are no virtualenvs yet.")
+		return nil
+	}
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSTATUS\tLAST USED")
+	for _, venv := range venvs {
+		status := "installed"
+		if !venv.Installed {
+			status = "incomplete"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\n", venv.Name, status, venv.LastUsed.Format(historyTimeFormat))
+	}
+	_ = w.Flush()
+
+	r.infoPrinter.Print(buf.String())
+	return nil
+}
+
+func (r *VenvCommand) Rebuild(ctx context.Context, names []string, all bool) error {
+	if all == (len(names) > 0) {
+		r.errorPrinter.Println("Please give either the names of the virtualenvs or the --all flag: blast venv rebuild <names>")
+		return cli.Exit("", 1)
+	}
+
+	if all {
+		venvs, err := r.manager.List()
+		if err != nil {
+			r.errorPrinter.Printf("Failed to list the virtualenvs: %v\n", err)
+			return cli.Exit("", 1)
+		}
+
+		for _, venv := range venvs {
+			names = append(names, venv.Name)
+		}
+	}
+
+	failed := false
+	for _, name := range names {
+		r.infoPrinter.Printf("Rebuilding the virtualenv '%s'...\n", name)
+		err := r.manager.Rebuild(ctx, name)
+		if err != nil {
+			failed = true
+			r.errorPrinter.Printf("Failed to rebuild the virtualenv '%s': %v\n", name, err)
+			continue
+		}
+
+		r.successPrinter.Printf("Rebuilt the virtualenv '%s'.\n", name)
+	}
+
+	if failed {
+		return cli.Exit("", 1)
+	}
+
+	return nil
+}
+
+func (r *VenvCommand) Prune(unusedSince time.Time) error {
+	pruned, err := r.manager.Prune(unusedSince)
+	for _, venv := range pruned {
+		r.infoPrinter.Printf("Removed the virtualenv '%s', last used at %s.\n", venv.Name, venv.LastUsed.Format(historyTimeFormat))
+	}
+
+	if err != nil {
+		r.errorPrinter.Printf("Failed to prune the virtualenvs: %v\n", err)
+		return cli.Exit("", 1)
+	}
+
+	r.successPrinter.Printf("Removed %d virtualenvs.\n", len(pruned))
+	return nil
+}
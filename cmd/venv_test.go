@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/datablast-analytics/blast/pkg/python"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockVirtualEnvManager struct {
+	mock.Mock
+}
+
+func (m *mockVirtualEnvManager) List() ([]*python.VirtualEnv, error) {
+	args := m.Called()
+	return args.Get(0).([]*python.VirtualEnv), args.Error(1)
+}
+
+func (m *mockVirtualEnvManager) Rebuild(ctx context.Context, name string) error {
+	return m.Called(ctx, name).Error(0)
+}
+
+func (m *mockVirtualEnvManager) Prune(unusedSince time.Time) ([]*python.VirtualEnv, error) {
+	args := m.Called(unusedSince)
+	return args.Get(0).([]*python.VirtualEnv), args.Error(1)
+}
+
+var testVirtualEnvs = []*python.VirtualEnv{
+	{Name: "abc123", Installed: true, LastUsed: time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)},
+	{Name: "def456", LastUsed: time.Date(2023, 2, 1, 10, 0, 0, 0, time.UTC)},
+}
+
+func TestVenvCommand_List(t *testing.T) {
+	t.Parallel()
+
+	manager := new(mockVirtualEnvManager)
+	manager.On("List").Return(testVirtualEnvs, nil)
+
+	output := &mockPrinter{buf: &bytes.Buffer{}}
+	r := &VenvCommand{manager: manager, infoPrinter: output, errorPrinter: output, successPrinter: output}
+
+	assert.NoError(t, r.List())
+
+	expected := "NAME    STATUS      LAST USED\n" +
+		"abc123  installed   2023-03-01 10:00:00\n" +
+		"def456  incomplete  2023-02-01 10:00:00\n"
+	assert.Equal(t, expected, output.buf.String())
+}
+
+func TestVenvCommand_Rebuild(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	manager := new(mockVirtualEnvManager)
+	manager.On("List").Return(testVirtualEnvs, nil)
+	manager.On("Rebuild", ctx, "abc123").Return(nil)
+	manager.On("Rebuild", ctx, "def456").Return(errors.New("the requirements are not known"))
+
+	output := &mockPrinter{buf: &bytes.Buffer{}}
+	r := &VenvCommand{manager: manager, infoPrinter: output, errorPrinter: output, successPrinter: output}
+
+	assert.Error(t, r.Rebuild(ctx, nil, false))
+	assert.Error(t, r.Rebuild(ctx, []string{"abc123"}, true))
+
+	output.buf.Reset()
+	assert.NoError(t, r.Rebuild(ctx, []string{"abc123"}, false))
+	assert.Equal(t, "Rebuilding the virtualenv 'abc123'...\nRebuilt the virtualenv 'abc123'.\n", output.buf.String())
+
+	output.buf.Reset()
+	assert.Error(t, r.Rebuild(ctx, nil, true))
+	assert.Contains(t, output.buf.String(), "Rebuilt the virtualenv 'abc123'.\n")
+	assert.Contains(t, output.buf.String(), "Failed to rebuild the virtualenv 'def456': the requirements are not known\n")
+}
+
+func TestVenvCommand_Prune(t *testing.T) {
+	t.Parallel()
+
+	unusedSince := time.Date(2023, 2, 15, 0, 0, 0, 0, time.UTC)
+	manager := new(mockVirtualEnvManager)
+	manager.On("Prune", unusedSince).Return(testVirtualEnvs[1:], nil)
+
+	output := &mockPrinter{buf: &bytes.Buffer{}}
+	r := &VenvCommand{manager: manager, infoPrinter: output, errorPrinter: output, successPrinter: output}
+
+	assert.NoError(t, r.Prune(unusedSince))
+	assert.Equal(t, "Removed the virtualenv 'def456', last used at 2023-02-01 10:00:00.\nRemoved 1 virtualenvs.\n", output.buf.String())
+}
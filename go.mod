@@ -20,9 +20,11 @@ require (
 	github.com/stretchr/testify v1.8.3-0.20230314120135-c5fc9d6b6b21
 	github.com/urfave/cli/v2 v2.25.1
 	github.com/yourbasic/graph v0.0.0-20210606180040-8ecfec1c2869
+	go.etcd.io/bbolt v1.3.7
 	go.uber.org/zap v1.24.0
 	golang.org/x/oauth2 v0.7.0
 	golang.org/x/sync v0.1.0
+	golang.org/x/sys v0.7.0
 	google.golang.org/api v0.116.0
 	gopkg.in/yaml.v3 v3.0.1
 )
@@ -99,7 +101,6 @@ require (
 	golang.org/x/crypto v0.8.0 // indirect
 	golang.org/x/mod v0.10.0 // indirect
 	golang.org/x/net v0.9.0 // indirect
-	golang.org/x/sys v0.7.0 // indirect
 	golang.org/x/term v0.7.0 // indirect
 	golang.org/x/text v0.9.0 // indirect
 	golang.org/x/tools v0.8.0 // indirect
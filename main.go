@@ -28,8 +28,11 @@ func main() {
 		Commands: []*cli.Command{
 			cmd.Lint(&isDebug),
 			cmd.Run(&isDebug),
+			cmd.Schedule(&isDebug),
 			cmd.Render(),
 			cmd.Lineage(),
+			cmd.History(),
+			cmd.Venv(),
 		},
 	}
 
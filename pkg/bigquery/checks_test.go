@@ -50,10 +50,10 @@ func TestNotNullCheck_Check(t *testing.T) {
 
 	runTestsFoCountZeroCheck(
 		t,
-		func(q *mockQuerierWithResult) testRunner {
+		func(q *mockQuerierWithResult) *ColumnCheckOperator {
 			conn := new(mockConnectionFetcher)
 			conn.On("GetBqConnection", "test").Return(q, nil)
-			return &NotNullCheck{conn: conn}
+			return &ColumnCheckOperator{conn: conn}
 		},
 		"SELECT count(*) FROM `dataset.test_asset` WHERE `test_column` IS NULL",
 		"column `test_column` has 5 null values",
@@ -68,10 +68,10 @@ func TestPositiveCheck_Check(t *testing.T) {
 
 	runTestsFoCountZeroCheck(
 		t,
-		func(q *mockQuerierWithResult) testRunner {
+		func(q *mockQuerierWithResult) *ColumnCheckOperator {
 			conn := new(mockConnectionFetcher)
 			conn.On("GetBqConnection", "test").Return(q, nil)
-			return &PositiveCheck{conn: conn}
+			return &ColumnCheckOperator{conn: conn}
 		},
 		"SELECT count(*) FROM `dataset.test_asset` WHERE `test_column` <= 0",
 		"column `test_column` has 5 non-positive values",
@@ -86,10 +86,10 @@ func TestUniqueCheck_Check(t *testing.T) {
 
 	runTestsFoCountZeroCheck(
 		t,
-		func(q *mockQuerierWithResult) testRunner {
+		func(q *mockQuerierWithResult) *ColumnCheckOperator {
 			conn := new(mockConnectionFetcher)
 			conn.On("GetBqConnection", "test").Return(q, nil)
-			return &UniqueCheck{conn: conn}
+			return &ColumnCheckOperator{conn: conn}
 		},
 		"SELECT COUNT(`test_column`) - COUNT(DISTINCT `test_column`) FROM `dataset.test_asset`",
 		"column `test_column` has 5 non-unique values",
@@ -104,10 +104,10 @@ func TestAcceptedValuesCheck_Check(t *testing.T) {
 
 	runTestsFoCountZeroCheck(
 		t,
-		func(q *mockQuerierWithResult) testRunner {
+		func(q *mockQuerierWithResult) *ColumnCheckOperator {
 			conn := new(mockConnectionFetcher)
 			conn.On("GetBqConnection", "test").Return(q, nil)
-			return &AcceptedValuesCheck{conn: conn}
+			return &ColumnCheckOperator{conn: conn}
 		},
 		"SELECT COUNT(*) FROM `dataset.test_asset` WHERE CAST(`test_column` as STRING) NOT IN (\"test\",\"test2\")",
 		"column `test_column` has 5 rows that are not in the accepted values",
@@ -121,10 +121,10 @@ func TestAcceptedValuesCheck_Check(t *testing.T) {
 
 	runTestsFoCountZeroCheck(
 		t,
-		func(q *mockQuerierWithResult) testRunner {
+		func(q *mockQuerierWithResult) *ColumnCheckOperator {
 			conn := new(mockConnectionFetcher)
 			conn.On("GetBqConnection", "test").Return(q, nil)
-			return &AcceptedValuesCheck{conn: conn}
+			return &ColumnCheckOperator{conn: conn}
 		},
 		"SELECT COUNT(*) FROM `dataset.test_asset` WHERE CAST(`test_column` as STRING) NOT IN (\"1\",\"2\")",
 		"column `test_column` has 5 rows that are not in the accepted values",
@@ -137,7 +137,173 @@ func TestAcceptedValuesCheck_Check(t *testing.T) {
 	)
 }
 
-func runTestsFoCountZeroCheck(t *testing.T, instanceBuilder func(q *mockQuerierWithResult) testRunner, expectedQueryString string, expectedErrorMessage string, checkInstance *pipeline.ColumnCheck) {
+func TestMinMaxChecks_Check(t *testing.T) {
+	t.Parallel()
+
+	minValue := 3
+	runTestsFoCountZeroCheck(
+		t,
+		func(q *mockQuerierWithResult) *ColumnCheckOperator {
+			conn := new(mockConnectionFetcher)
+			conn.On("GetBqConnection", "test").Return(q, nil)
+			return &ColumnCheckOperator{conn: conn}
+		},
+		"SELECT count(*) FROM `dataset.test_asset` WHERE `test_column` < 3",
+		"column `test_column` has 5 values below 3",
+		&pipeline.ColumnCheck{
+			Name:  "min",
+			Value: pipeline.ColumnCheckValue{Int: &minValue},
+		},
+	)
+
+	maxValue := 9.75
+	runTestsFoCountZeroCheck(
+		t,
+		func(q *mockQuerierWithResult) *ColumnCheckOperator {
+			conn := new(mockConnectionFetcher)
+			conn.On("GetBqConnection", "test").Return(q, nil)
+			return &ColumnCheckOperator{conn: conn}
+		},
+		"SELECT count(*) FROM `dataset.test_asset` WHERE `test_column` > 9.75",
+		"column `test_column` has 5 values above 9.75",
+		&pipeline.ColumnCheck{
+			Name:  "max",
+			Value: pipeline.ColumnCheckValue{Float: &maxValue},
+		},
+	)
+}
+
+func TestBetweenCheck_Check(t *testing.T) {
+	t.Parallel()
+
+	runTestsFoCountZeroCheck(
+		t,
+		func(q *mockQuerierWithResult) *ColumnCheckOperator {
+			conn := new(mockConnectionFetcher)
+			conn.On("GetBqConnection", "test").Return(q, nil)
+			return &ColumnCheckOperator{conn: conn}
+		},
+		"SELECT count(*) FROM `dataset.test_asset` WHERE `test_column` NOT BETWEEN 1 AND 10",
+		"column `test_column` has 5 values outside of the range [1, 10]",
+		&pipeline.ColumnCheck{
+			Name:  "between",
+			Value: pipeline.ColumnCheckValue{IntArray: &[]int{1, 10}},
+		},
+	)
+
+	runTestsFoCountZeroCheck(
+		t,
+		func(q *mockQuerierWithResult) *ColumnCheckOperator {
+			conn := new(mockConnectionFetcher)
+			conn.On("GetBqConnection", "test").Return(q, nil)
+			return &ColumnCheckOperator{conn: conn}
+		},
+		"SELECT count(*) FROM `dataset.test_asset` WHERE `test_column` NOT BETWEEN 0.5 AND 1.5",
+		"column `test_column` has 5 values outside of the range [0.5, 1.5]",
+		&pipeline.ColumnCheck{
+			Name:  "between",
+			Value: pipeline.ColumnCheckValue{StringArray: &[]string{"0.5", "1.5"}},
+		},
+	)
+}
+
+func TestNegativeChecks_Check(t *testing.T) {
+	t.Parallel()
+
+	runTestsFoCountZeroCheck(
+		t,
+		func(q *mockQuerierWithResult) *ColumnCheckOperator {
+			conn := new(mockConnectionFetcher)
+			conn.On("GetBqConnection", "test").Return(q, nil)
+			return &ColumnCheckOperator{conn: conn}
+		},
+		"SELECT count(*) FROM `dataset.test_asset` WHERE `test_column` >= 0",
+		"column `test_column` has 5 non-negative values",
+		&pipeline.ColumnCheck{Name: "negative"},
+	)
+
+	runTestsFoCountZeroCheck(
+		t,
+		func(q *mockQuerierWithResult) *ColumnCheckOperator {
+			conn := new(mockConnectionFetcher)
+			conn.On("GetBqConnection", "test").Return(q, nil)
+			return &ColumnCheckOperator{conn: conn}
+		},
+		"SELECT count(*) FROM `dataset.test_asset` WHERE `test_column` < 0",
+		"column `test_column` has 5 negative values",
+		&pipeline.ColumnCheck{Name: "non_negative"},
+	)
+}
+
+func TestStringChecks_Check(t *testing.T) {
+	t.Parallel()
+
+	pattern := `[a-z]+\d`
+	runTestsFoCountZeroCheck(
+		t,
+		func(q *mockQuerierWithResult) *ColumnCheckOperator {
+			conn := new(mockConnectionFetcher)
+			conn.On("GetBqConnection", "test").Return(q, nil)
+			return &ColumnCheckOperator{conn: conn}
+		},
+		"SELECT count(*) FROM `dataset.test_asset` WHERE NOT REGEXP_CONTAINS(CAST(`test_column` as STRING), \"^(?:[a-z]+\\\\d)$\")",
+		"column `test_column` has 5 values that do not match the pattern '[a-z]+\\d'",
+		&pipeline.ColumnCheck{
+			Name:  "pattern",
+			Value: pipeline.ColumnCheckValue{String: &pattern},
+		},
+	)
+
+	runTestsFoCountZeroCheck(
+		t,
+		func(q *mockQuerierWithResult) *ColumnCheckOperator {
+			conn := new(mockConnectionFetcher)
+			conn.On("GetBqConnection", "test").Return(q, nil)
+			return &ColumnCheckOperator{conn: conn}
+		},
+		"SELECT count(*) FROM `dataset.test_asset` WHERE `test_column` = ''",
+		"column `test_column` has 5 empty strings",
+		&pipeline.ColumnCheck{Name: "not_empty_string"},
+	)
+
+	maxLength := 12
+	runTestsFoCountZeroCheck(
+		t,
+		func(q *mockQuerierWithResult) *ColumnCheckOperator {
+			conn := new(mockConnectionFetcher)
+			conn.On("GetBqConnection", "test").Return(q, nil)
+			return &ColumnCheckOperator{conn: conn}
+		},
+		"SELECT count(*) FROM `dataset.test_asset` WHERE LENGTH(`test_column`) > 12",
+		"column `test_column` has 5 values longer than 12 characters",
+		&pipeline.ColumnCheck{
+			Name:  "max_length",
+			Value: pipeline.ColumnCheckValue{Int: &maxLength},
+		},
+	)
+}
+
+func TestRelationshipCheck_Check(t *testing.T) {
+	t.Parallel()
+
+	reference := "dataset.users.id"
+	runTestsFoCountZeroCheck(
+		t,
+		func(q *mockQuerierWithResult) *ColumnCheckOperator {
+			conn := new(mockConnectionFetcher)
+			conn.On("GetBqConnection", "test").Return(q, nil)
+			return &ColumnCheckOperator{conn: conn}
+		},
+		"SELECT count(*) FROM `dataset.test_asset` AS child WHERE child.`test_column` IS NOT NULL AND NOT EXISTS (SELECT 1 FROM `dataset.users` AS parent WHERE parent.`id` = child.`test_column`)",
+		"column `test_column` has 5 values that do not exist in `dataset.users`.`id`",
+		&pipeline.ColumnCheck{
+			Name:  "relationship",
+			Value: pipeline.ColumnCheckValue{String: &reference},
+		},
+	)
+}
+
+func runTestsFoCountZeroCheck(t *testing.T, instanceBuilder func(q *mockQuerierWithResult) *ColumnCheckOperator, expectedQueryString string, expectedErrorMessage string, checkInstance *pipeline.ColumnCheck) {
 	expectedQuery := &query.Query{Query: expectedQueryString}
 	setupFunc := func(val [][]interface{}, err error) func(n *mockQuerierWithResult) {
 		return func(q *mockQuerierWithResult) {
@@ -218,7 +384,7 @@ func runTestsFoCountZeroCheck(t *testing.T, instanceBuilder func(q *mockQuerierW
 				Check: checkInstance,
 			}
 
-			tt.wantErr(t, n.Check(context.Background(), testInstance))
+			tt.wantErr(t, n.Run(context.Background(), testInstance))
 			defer q.AssertExpectations(t)
 		})
 	}
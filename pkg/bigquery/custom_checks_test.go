@@ -0,0 +1,101 @@
+package bigquery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/datablast-analytics/blast/pkg/executor"
+	"github.com/datablast-analytics/blast/pkg/jinja"
+	"github.com/datablast-analytics/blast/pkg/pipeline"
+	"github.com/datablast-analytics/blast/pkg/query"
+	"github.com/datablast-analytics/blast/pkg/scheduler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCustomCheckOperator_Run(t *testing.T) {
+	t.Parallel()
+
+	expectedQuery := &query.Query{Query: "SELECT count(*) FROM `dataset.test_asset` WHERE dt = '2023-01-01'"}
+
+	tests := []struct {
+		name     string
+		expected int64
+		result   [][]interface{}
+		err      error
+		wantErr  string
+	}{
+		{
+			name:    "failed to run query",
+			err:     assert.AnError,
+			wantErr: "failed custom check 'recent_rows': " + assert.AnError.Error(),
+		},
+		{
+			name:    "multiple results are returned",
+			result:  [][]interface{}{{1}, {2}},
+			wantErr: "unexpected result from query during custom check 'recent_rows', the query must return a single value",
+		},
+		{
+			name:     "count matches the expected value",
+			expected: 5,
+			result:   [][]interface{}{{int64(5)}},
+		},
+		{
+			name:    "count does not match the expected value",
+			result:  [][]interface{}{{int64(3)}},
+			wantErr: "custom check 'recent_rows' has returned 3 instead of 0",
+		},
+		{
+			name:     "boolean result is compared as an integer",
+			expected: 1,
+			result:   [][]interface{}{{true}},
+		},
+		{
+			name:     "false is compared as zero",
+			expected: 1,
+			result:   [][]interface{}{{false}},
+			wantErr:  "custom check 'recent_rows' has returned 0 instead of 1",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			q := new(mockQuerierWithResult)
+			q.On("Select", mock.Anything, expectedQuery).Return(tt.result, tt.err).Once()
+			defer q.AssertExpectations(t)
+
+			conn := new(mockConnectionFetcher)
+			conn.On("GetBqConnection", "test").Return(q, nil)
+
+			instance := &scheduler.CustomCheckInstance{
+				AssetInstance: &scheduler.AssetInstance{
+					Asset: &pipeline.Asset{
+						Name: "dataset.test_asset",
+						Type: executor.TaskTypeBigqueryQuery,
+					},
+					Pipeline: &pipeline.Pipeline{
+						Name: "test",
+						DefaultConnections: map[string]string{
+							"google_cloud_platform": "test",
+						},
+					},
+				},
+				Check: &pipeline.CustomCheck{
+					Name:  "recent_rows",
+					Query: "SELECT count(*) FROM `dataset.test_asset` WHERE dt = '{{ dt }}'",
+					Value: tt.expected,
+				},
+			}
+
+			operator := NewCustomCheckOperator(conn, jinja.NewRenderer(jinja.Context{"dt": "2023-01-01"}))
+			err := operator.Run(context.Background(), instance)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
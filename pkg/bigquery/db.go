@@ -87,8 +87,7 @@ func (d *Client) IsValid(ctx context.Context, query *query.Query) (bool, error)
 }
 
 func (d *Client) RunQueryWithoutResult(ctx context.Context, query *query.Query) error {
-	q := d.client.Query(query.String())
-	_, err := q.Read(ctx)
+	_, err := d.read(ctx, query)
 	if err != nil {
 		return formatError(err)
 	}
@@ -97,8 +96,7 @@ func (d *Client) RunQueryWithoutResult(ctx context.Context, query *query.Query)
 }
 
 func (d *Client) Select(ctx context.Context, query *query.Query) ([][]interface{}, error) {
-	q := d.client.Query(query.String())
-	rows, err := q.Read(ctx)
+	rows, err := d.read(ctx, query)
 	if err != nil {
 		return nil, formatError(err)
 	}
@@ -125,6 +123,25 @@ func (d *Client) Select(ctx context.Context, query *query.Query) ([][]interface{
 	return result, nil
 }
 
+// read submits the query as a job instead of using the faster stateless path, so that the job can be cancelled in case
+// the context is cancelled while the job is still running, otherwise it would continue to run in the background.
+func (d *Client) read(ctx context.Context, query *query.Query) (*bigquery.RowIterator, error) {
+	job, err := d.client.Query(query.String()).Run(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := job.Read(ctx)
+	if err != nil && ctx.Err() != nil {
+		cancelErr := job.Cancel(context.Background())
+		if cancelErr != nil {
+			return nil, errors.Wrapf(err, "failed to cancel the job '%s': %s", job.ID(), cancelErr)
+		}
+	}
+
+	return rows, err
+}
+
 func formatError(err error) error {
 	var googleError *googleapi.Error
 	if !errors.As(err, &googleError) {
@@ -8,7 +8,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"cloud.google.com/go/bigquery"
 	"github.com/datablast-analytics/blast/pkg/query"
@@ -272,7 +274,7 @@ func mockBqHandler(t *testing.T, projectID, jobID string, jsr jobSubmitResponse,
 			_, err = w.Write(response)
 			assert.NoError(t, err)
 			return
-		} else if r.Method == "POST" && strings.HasPrefix(r.RequestURI, fmt.Sprintf("/projects/%s/queries", projectID)) {
+		} else if r.Method == "POST" && strings.HasPrefix(r.RequestURI, fmt.Sprintf("/projects/%s/jobs", projectID)) {
 			w.WriteHeader(jsr.statusCode)
 
 			response, err := json.Marshal(jsr.response)
@@ -450,3 +452,68 @@ func TestDB_Select(t *testing.T) {
 		})
 	}
 }
+
+func TestDB_RunQueryWithoutResult_CancelsJobWhenContextIsCancelled(t *testing.T) {
+	t.Parallel()
+
+	projectID := "test-project"
+	jobID := "test-job"
+
+	var cancelled atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var response any
+		switch {
+		case r.Method == "POST" && strings.HasPrefix(r.RequestURI, fmt.Sprintf("/projects/%s/jobs/%s/cancel", projectID, jobID)):
+			cancelled.Store(true)
+			response = &bigquery2.JobCancelResponse{
+				Job: &bigquery2.Job{
+					JobReference: &bigquery2.JobReference{JobId: jobID, ProjectId: projectID},
+				},
+			}
+		case r.Method == "POST" && strings.HasPrefix(r.RequestURI, fmt.Sprintf("/projects/%s/jobs", projectID)):
+			response = &bigquery2.Job{
+				Configuration: &bigquery2.JobConfiguration{
+					Query: &bigquery2.JobConfigurationQuery{Query: "select * from users"},
+				},
+				JobReference: &bigquery2.JobReference{JobId: jobID, ProjectId: projectID},
+				Status:       &bigquery2.JobStatus{State: "RUNNING"},
+			}
+		default:
+			response = &bigquery2.GetQueryResultsResponse{
+				JobReference: &bigquery2.JobReference{JobId: jobID, ProjectId: projectID},
+				JobComplete:  false,
+			}
+		}
+
+		body, err := json.Marshal(response)
+		assert.NoError(t, err)
+
+		w.WriteHeader(http.StatusOK)
+		_, err = w.Write(body)
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client, err := bigquery.NewClient(
+		context.Background(),
+		projectID,
+		option.WithEndpoint(server.URL),
+		option.WithCredentials(&google.Credentials{
+			ProjectID: projectID,
+			TokenSource: oauth2.StaticTokenSource(&oauth2.Token{
+				AccessToken: "some-token",
+			}),
+		}),
+	)
+	assert.NoError(t, err)
+	client.Location = "US"
+
+	d := Client{client: client}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	err = d.RunQueryWithoutResult(ctx, &query.Query{Query: "select * from users"})
+	assert.Error(t, err)
+	assert.True(t, cancelled.Load())
+}
@@ -3,6 +3,7 @@ package bigquery
 import (
 	"context"
 
+	"github.com/datablast-analytics/blast/pkg/checks"
 	"github.com/datablast-analytics/blast/pkg/pipeline"
 	"github.com/datablast-analytics/blast/pkg/query"
 	"github.com/datablast-analytics/blast/pkg/scheduler"
@@ -69,23 +70,12 @@ func (o BasicOperator) RunTask(ctx context.Context, p *pipeline.Pipeline, t *pip
 	return conn.RunQueryWithoutResult(ctx, q)
 }
 
-type testRunner interface {
-	Check(ctx context.Context, ti *scheduler.ColumnCheckInstance) error
-}
-
 type ColumnCheckOperator struct {
-	testRunners map[string]testRunner
+	conn connectionFetcher
 }
 
 func NewColumnCheckOperator(manager connectionFetcher) (*ColumnCheckOperator, error) {
-	return &ColumnCheckOperator{
-		testRunners: map[string]testRunner{
-			"not_null":        &NotNullCheck{conn: manager},
-			"unique":          &UniqueCheck{conn: manager},
-			"positive":        &PositiveCheck{conn: manager},
-			"accepted_values": &AcceptedValuesCheck{conn: manager},
-		},
-	}, nil
+	return &ColumnCheckOperator{conn: manager}, nil
 }
 
 func (o ColumnCheckOperator) Run(ctx context.Context, ti scheduler.TaskInstance) error {
@@ -94,10 +84,15 @@ func (o ColumnCheckOperator) Run(ctx context.Context, ti scheduler.TaskInstance)
 		return errors.New("cannot run a non-column test instance")
 	}
 
-	executor, ok := o.testRunners[test.Check.Name]
-	if !ok {
-		return errors.New("there is no executor configured for the test type, test cannot be run: " + test.Check.Name)
+	check, err := checks.NewColumnCheck(checkDialect{}, test)
+	if err != nil {
+		return err
+	}
+
+	q, err := o.conn.GetBqConnection(test.Pipeline.GetConnectionNameForAsset(test.GetAsset()))
+	if err != nil {
+		return errors.Wrapf(err, "failed to get connection for '%s' check", test.Check.Name)
 	}
 
-	return executor.Check(ctx, test)
+	return check.Run(ctx, q)
 }
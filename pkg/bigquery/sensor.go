@@ -0,0 +1,149 @@
+package bigquery
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/datablast-analytics/blast/pkg/pipeline"
+	"github.com/datablast-analytics/blast/pkg/query"
+	"github.com/datablast-analytics/blast/pkg/scheduler"
+	"github.com/pkg/errors"
+)
+
+var (
+	identifierRegex  = regexp.MustCompile(`^[\w-]+$`)
+	partitionIDRegex = regexp.MustCompile(`^\w+$`)
+)
+
+// TableSensor waits for the table given in the `table` parameter to exist.
+type TableSensor struct {
+	conn connectionFetcher
+}
+
+func NewTableSensor(conn connectionFetcher) *TableSensor {
+	return &TableSensor{conn: conn}
+}
+
+func (s TableSensor) Poke(ctx context.Context, ti scheduler.TaskInstance) (bool, error) {
+	dataset, table, err := sensorTable(ti.GetAsset())
+	if err != nil {
+		return false, err
+	}
+
+	q := fmt.Sprintf("SELECT COUNT(*) FROM `%s`.INFORMATION_SCHEMA.TABLES WHERE table_name = '%s'", dataset, table)
+	return selectTruthy(ctx, s.conn, ti, q)
+}
+
+// QuerySensor waits for the query given in the `query` parameter to return a truthy value in its first column.
+type QuerySensor struct {
+	conn     connectionFetcher
+	renderer renderer
+}
+
+func NewQuerySensor(conn connectionFetcher, renderer renderer) *QuerySensor {
+	return &QuerySensor{conn: conn, renderer: renderer}
+}
+
+func (s QuerySensor) Poke(ctx context.Context, ti scheduler.TaskInstance) (bool, error) {
+	q := ti.GetAsset().Parameters["query"]
+	if q == "" {
+		return false, errors.New("the `query` parameter is required for the query sensors")
+	}
+
+	return selectTruthy(ctx, s.conn, ti, s.renderer.Render(q))
+}
+
+// PartitionSensor waits for the partition given in the `partition` parameter of the table given in the `table`
+// parameter to have rows, the partition is the partition ID in BigQuery, e.g. `{{ end_date_nodash }}`.
+type PartitionSensor struct {
+	conn     connectionFetcher
+	renderer renderer
+}
+
+func NewPartitionSensor(conn connectionFetcher, renderer renderer) *PartitionSensor {
+	return &PartitionSensor{conn: conn, renderer: renderer}
+}
+
+func (s PartitionSensor) Poke(ctx context.Context, ti scheduler.TaskInstance) (bool, error) {
+	dataset, table, err := sensorTable(ti.GetAsset())
+	if err != nil {
+		return false, err
+	}
+
+	partition := strings.TrimSpace(s.renderer.Render(ti.GetAsset().Parameters["partition"]))
+	if partition == "" {
+		return false, errors.New("the `partition` parameter is required for the partition sensors")
+	}
+
+	if !partitionIDRegex.MatchString(partition) {
+		return false, errors.Errorf("invalid partition '%s', it must be a partition ID such as '20230101'", partition)
+	}
+
+	q := fmt.Sprintf(
+		"SELECT COUNT(*) FROM `%s`.INFORMATION_SCHEMA.PARTITIONS WHERE table_name = '%s' AND partition_id = '%s' AND total_rows > 0",
+		dataset, table, partition,
+	)
+	return selectTruthy(ctx, s.conn, ti, q)
+}
+
+// sensorTable splits the `table` parameter in the `dataset.table` or `project.dataset.table` format into the dataset,
+// including the project if given, and the table name.
+func sensorTable(asset *pipeline.Asset) (string, string, error) {
+	table := asset.Parameters["table"]
+	if table == "" {
+		return "", "", errors.New("the `table` parameter is required for the table sensors")
+	}
+
+	parts := strings.Split(table, ".")
+	if len(parts) != 2 && len(parts) != 3 {
+		return "", "", errors.Errorf("invalid table '%s', it must be in the 'dataset.table' or 'project.dataset.table' format", table)
+	}
+
+	for _, part := range parts {
+		if !identifierRegex.MatchString(part) {
+			return "", "", errors.Errorf("invalid table '%s', it must be in the 'dataset.table' or 'project.dataset.table' format", table)
+		}
+	}
+
+	return strings.Join(parts[:len(parts)-1], "."), parts[len(parts)-1], nil
+}
+
+func selectTruthy(ctx context.Context, conn connectionFetcher, ti scheduler.TaskInstance, q string) (bool, error) {
+	db, err := conn.GetBqConnection(ti.GetPipeline().GetConnectionNameForAsset(ti.GetAsset()))
+	if err != nil {
+		return false, err
+	}
+
+	res, err := db.Select(ctx, &query.Query{Query: q})
+	if err != nil {
+		return false, err
+	}
+
+	if len(res) == 0 || len(res[0]) == 0 {
+		return false, nil
+	}
+
+	return isTruthy(res[0][0])
+}
+
+// isTruthy follows the usual rules, the null values, false, zero and empty strings are falsy.
+func isTruthy(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return v, nil
+	case int64:
+		return v != 0, nil
+	case int:
+		return v != 0, nil
+	case float64:
+		return v != 0, nil
+	case string:
+		return v != "", nil
+	}
+
+	return false, errors.Errorf("unexpected result from the sensor query, cannot evaluate the value of type %T", value)
+}
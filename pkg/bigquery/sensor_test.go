@@ -0,0 +1,152 @@
+package bigquery
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/datablast-analytics/blast/pkg/executor"
+	"github.com/datablast-analytics/blast/pkg/jinja"
+	"github.com/datablast-analytics/blast/pkg/pipeline"
+	"github.com/datablast-analytics/blast/pkg/query"
+	"github.com/datablast-analytics/blast/pkg/scheduler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type poker interface {
+	Poke(ctx context.Context, ti scheduler.TaskInstance) (bool, error)
+}
+
+func TestSensors_Poke(t *testing.T) {
+	t.Parallel()
+
+	startDate := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+	renderer := jinja.NewRendererWithStartEndDates(&startDate, &endDate)
+
+	tests := []struct {
+		name          string
+		sensor        func(conn connectionFetcher) poker
+		assetType     pipeline.AssetType
+		parameters    map[string]string
+		expectedQuery string
+		result        [][]interface{}
+		err           error
+		want          bool
+		wantErr       string
+	}{
+		{
+			name:          "table exists",
+			sensor:        func(conn connectionFetcher) poker { return NewTableSensor(conn) },
+			assetType:     executor.TaskTypeBigqueryTableSensor,
+			parameters:    map[string]string{"table": "dataset.events"},
+			expectedQuery: "SELECT COUNT(*) FROM `dataset`.INFORMATION_SCHEMA.TABLES WHERE table_name = 'events'",
+			result:        [][]interface{}{{int64(1)}},
+			want:          true,
+		},
+		{
+			name:          "table in another project does not exist yet",
+			sensor:        func(conn connectionFetcher) poker { return NewTableSensor(conn) },
+			assetType:     executor.TaskTypeBigqueryTableSensor,
+			parameters:    map[string]string{"table": "other-project.dataset.events"},
+			expectedQuery: "SELECT COUNT(*) FROM `other-project.dataset`.INFORMATION_SCHEMA.TABLES WHERE table_name = 'events'",
+			result:        [][]interface{}{{int64(0)}},
+			want:          false,
+		},
+		{
+			name:       "table parameter is invalid",
+			sensor:     func(conn connectionFetcher) poker { return NewTableSensor(conn) },
+			assetType:  executor.TaskTypeBigqueryTableSensor,
+			parameters: map[string]string{"table": "events' OR 1=1 --"},
+			wantErr:    "invalid table 'events' OR 1=1 --', it must be in the 'dataset.table' or 'project.dataset.table' format",
+		},
+		{
+			name:          "query returns true",
+			sensor:        func(conn connectionFetcher) poker { return NewQuerySensor(conn, renderer) },
+			assetType:     executor.TaskTypeBigqueryQuerySensor,
+			parameters:    map[string]string{"query": "SELECT count(*) > 0 FROM dataset.events WHERE dt = '{{ start_date }}'"},
+			expectedQuery: "SELECT count(*) > 0 FROM dataset.events WHERE dt = '2023-01-01'",
+			result:        [][]interface{}{{true}},
+			want:          true,
+		},
+		{
+			name:          "query returns no rows",
+			sensor:        func(conn connectionFetcher) poker { return NewQuerySensor(conn, renderer) },
+			assetType:     executor.TaskTypeBigqueryQuerySensor,
+			parameters:    map[string]string{"query": "SELECT 1 FROM dataset.events LIMIT 0"},
+			expectedQuery: "SELECT 1 FROM dataset.events LIMIT 0",
+			result:        [][]interface{}{},
+			want:          false,
+		},
+		{
+			name:          "query fails",
+			sensor:        func(conn connectionFetcher) poker { return NewQuerySensor(conn, renderer) },
+			assetType:     executor.TaskTypeBigqueryQuerySensor,
+			parameters:    map[string]string{"query": "SELECT true"},
+			expectedQuery: "SELECT true",
+			err:           assert.AnError,
+			wantErr:       assert.AnError.Error(),
+		},
+		{
+			name:      "query parameter is missing",
+			sensor:    func(conn connectionFetcher) poker { return NewQuerySensor(conn, renderer) },
+			assetType: executor.TaskTypeBigqueryQuerySensor,
+			wantErr:   "the `query` parameter is required for the query sensors",
+		},
+		{
+			name:          "partition has rows",
+			sensor:        func(conn connectionFetcher) poker { return NewPartitionSensor(conn, renderer) },
+			assetType:     executor.TaskTypeBigqueryPartitionSensor,
+			parameters:    map[string]string{"table": "dataset.events", "partition": "{{ start_date_nodash }}"},
+			expectedQuery: "SELECT COUNT(*) FROM `dataset`.INFORMATION_SCHEMA.PARTITIONS WHERE table_name = 'events' AND partition_id = '20230101' AND total_rows > 0",
+			result:        [][]interface{}{{int64(1)}},
+			want:          true,
+		},
+		{
+			name:       "partition parameter is missing",
+			sensor:     func(conn connectionFetcher) poker { return NewPartitionSensor(conn, renderer) },
+			assetType:  executor.TaskTypeBigqueryPartitionSensor,
+			parameters: map[string]string{"table": "dataset.events"},
+			wantErr:    "the `partition` parameter is required for the partition sensors",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			q := new(mockQuerierWithResult)
+			if tt.expectedQuery != "" {
+				q.On("Select", mock.Anything, &query.Query{Query: tt.expectedQuery}).Return(tt.result, tt.err).Once()
+			}
+			defer q.AssertExpectations(t)
+
+			conn := new(mockConnectionFetcher)
+			conn.On("GetBqConnection", "gcp-default").Return(q, nil)
+
+			instance := &scheduler.AssetInstance{
+				Asset: &pipeline.Asset{
+					Name:       "wait_for_events",
+					Type:       tt.assetType,
+					Parameters: tt.parameters,
+				},
+				Pipeline: &pipeline.Pipeline{
+					Name: "test",
+					DefaultConnections: map[string]string{
+						"gcp": "gcp-default",
+					},
+				},
+			}
+
+			got, err := tt.sensor(conn).Poke(context.Background(), instance)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,361 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/datablast-analytics/blast/pkg/pipeline"
+	"github.com/datablast-analytics/blast/pkg/query"
+	"github.com/datablast-analytics/blast/pkg/scheduler"
+	"github.com/pkg/errors"
+)
+
+// Selector runs the queries of the column checks on a warehouse.
+type Selector interface {
+	Select(ctx context.Context, q *query.Query) ([][]interface{}, error)
+}
+
+// Dialect contains the SQL differences between the warehouses the column checks run on.
+type Dialect interface {
+	// QuoteIdentifier prepares a possibly-qualified identifier, e.g. `schema.table`, to be used in a query.
+	QuoteIdentifier(identifier string) string
+	// QuoteString returns the value as a string literal.
+	QuoteString(value string) string
+	// StringType is the type the values are cast to in order to be compared as strings.
+	StringType() string
+	// NotMatching returns the condition for the values of the expression that do not match the pattern as a whole.
+	NotMatching(expression string, pattern string) string
+}
+
+// CountCheck counts the rows that violate a column check, the check fails if the count is above the threshold.
+type CountCheck struct {
+	Name       string
+	Query      *query.Query
+	TotalQuery *query.Query
+	Threshold  pipeline.CheckThreshold
+	Error      func(count int64) error
+}
+
+type columnCheckBuilder func(d Dialect, ti *scheduler.ColumnCheckInstance) (*CountCheck, error)
+
+var columnChecks = map[string]columnCheckBuilder{
+	"not_null":         notNullCheck,
+	"unique":           uniqueCheck,
+	"positive":         positiveCheck,
+	"accepted_values":  acceptedValuesCheck,
+	"min":              minCheck,
+	"max":              maxCheck,
+	"between":          betweenCheck,
+	"negative":         negativeCheck,
+	"non_negative":     nonNegativeCheck,
+	"pattern":          patternCheck,
+	"regex":            patternCheck,
+	"not_empty_string": notEmptyStringCheck,
+	"max_length":       maxLengthCheck,
+	"relationship":     relationshipCheck,
+}
+
+// NewColumnCheck builds the queries of the column check in the given dialect.
+func NewColumnCheck(d Dialect, ti *scheduler.ColumnCheckInstance) (*CountCheck, error) {
+	builder, ok := columnChecks[ti.Check.Name]
+	if !ok {
+		return nil, errors.New("there is no executor configured for the test type, test cannot be run: " + ti.Check.Name)
+	}
+
+	return builder(d, ti)
+}
+
+func (c *CountCheck) Run(ctx context.Context, q Selector) error {
+	res, err := q.Select(ctx, c.Query)
+	if err != nil {
+		return errors.Wrapf(err, "failed '%s' check", c.Name)
+	}
+
+	count, err := countResult(c.Name, res)
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return nil
+	}
+
+	exceeded, err := c.thresholdExceeded(ctx, q, count)
+	if err != nil {
+		return err
+	}
+
+	if exceeded {
+		return c.Error(count)
+	}
+
+	return nil
+}
+
+// thresholdExceeded reports whether the number of offending rows is above the threshold of the check, the checks
+// without a threshold do not allow any offending rows.
+func (c *CountCheck) thresholdExceeded(ctx context.Context, q Selector, count int64) (bool, error) {
+	switch {
+	case c.Threshold.Rows != nil:
+		return count > *c.Threshold.Rows, nil
+	case c.Threshold.Percent != nil:
+		res, err := q.Select(ctx, c.TotalQuery)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to count the rows for '%s' check", c.Name)
+		}
+
+		total, err := countResult(c.Name, res)
+		if err != nil {
+			return false, err
+		}
+
+		if total == 0 {
+			return true, nil
+		}
+
+		return float64(count)*100/float64(total) > *c.Threshold.Percent, nil
+	}
+
+	return true, nil
+}
+
+// countResult reads the single number returned by the query of a check, some drivers, e.g. Snowflake, return the
+// numeric values as strings by default.
+func countResult(check string, res [][]interface{}) (int64, error) {
+	if len(res) != 1 || len(res[0]) != 1 {
+		return 0, errors.Errorf("unexpected result from query during %s check", check)
+	}
+
+	switch count := res[0][0].(type) {
+	case int64:
+		return count, nil
+	case int:
+		return int64(count), nil
+	case string:
+		if parsed, err := strconv.ParseInt(count, 10, 64); err == nil {
+			return parsed, nil
+		}
+	}
+
+	return 0, errors.Errorf("unexpected result from query during %s check, cannot cast result to integer", check)
+}
+
+func newCountCheck(d Dialect, ti *scheduler.ColumnCheckInstance, qq string, customError func(count int64) error) *CountCheck {
+	return &CountCheck{
+		Name:       ti.Check.Name,
+		Query:      &query.Query{Query: qq},
+		TotalQuery: &query.Query{Query: fmt.Sprintf("SELECT count(*) FROM %s", d.QuoteIdentifier(ti.GetAsset().Name))},
+		Threshold:  ti.Check.Threshold,
+		Error:      customError,
+	}
+}
+
+func notNullCheck(d Dialect, ti *scheduler.ColumnCheckInstance) (*CountCheck, error) {
+	qq := fmt.Sprintf("SELECT count(*) FROM %s WHERE %s IS NULL", d.QuoteIdentifier(ti.GetAsset().Name), d.QuoteIdentifier(ti.Column.Name))
+	return newCountCheck(d, ti, qq, func(count int64) error {
+		return errors.Errorf("column `%s` has %d null values", ti.Column.Name, count)
+	}), nil
+}
+
+func positiveCheck(d Dialect, ti *scheduler.ColumnCheckInstance) (*CountCheck, error) {
+	qq := fmt.Sprintf("SELECT count(*) FROM %s WHERE %s <= 0", d.QuoteIdentifier(ti.GetAsset().Name), d.QuoteIdentifier(ti.Column.Name))
+	return newCountCheck(d, ti, qq, func(count int64) error {
+		return errors.Errorf("column `%s` has %d non-positive values", ti.Column.Name, count)
+	}), nil
+}
+
+func uniqueCheck(d Dialect, ti *scheduler.ColumnCheckInstance) (*CountCheck, error) {
+	column := d.QuoteIdentifier(ti.Column.Name)
+	qq := fmt.Sprintf("SELECT COUNT(%s) - COUNT(DISTINCT %s) FROM %s", column, column, d.QuoteIdentifier(ti.GetAsset().Name))
+	return newCountCheck(d, ti, qq, func(count int64) error {
+		return errors.Errorf("column `%s` has %d non-unique values", ti.Column.Name, count)
+	}), nil
+}
+
+func acceptedValuesCheck(d Dialect, ti *scheduler.ColumnCheckInstance) (*CountCheck, error) {
+	if ti.Check.Value.StringArray == nil && ti.Check.Value.IntArray == nil {
+		return nil, errors.Errorf("unexpected value for accepted_values check, the values must to be an array, instead %T", ti.Check.Value)
+	}
+
+	if ti.Check.Value.StringArray != nil && len(*ti.Check.Value.StringArray) == 0 {
+		return nil, errors.Errorf("no values provided for accepted_values check")
+	}
+
+	if ti.Check.Value.IntArray != nil && len(*ti.Check.Value.IntArray) == 0 {
+		return nil, errors.Errorf("no values provided for accepted_values check")
+	}
+
+	var val []string
+	if ti.Check.Value.StringArray != nil {
+		val = *ti.Check.Value.StringArray
+	} else {
+		for _, v := range *ti.Check.Value.IntArray {
+			val = append(val, fmt.Sprintf("%d", v))
+		}
+	}
+
+	literals := make([]string, len(val))
+	for i, v := range val {
+		literals[i] = d.QuoteString(v)
+	}
+
+	qq := fmt.Sprintf(
+		"SELECT COUNT(*) FROM %s WHERE CAST(%s as %s) NOT IN (%s)",
+		d.QuoteIdentifier(ti.GetAsset().Name), d.QuoteIdentifier(ti.Column.Name), d.StringType(), strings.Join(literals, ","),
+	)
+	return newCountCheck(d, ti, qq, func(count int64) error {
+		return errors.Errorf("column `%s` has %d rows that are not in the accepted values", ti.Column.Name, count)
+	}), nil
+}
+
+// numericCheckValue returns the number given to a check as a SQL literal.
+func numericCheckValue(ti *scheduler.ColumnCheckInstance) (string, error) {
+	switch {
+	case ti.Check.Value.Int != nil:
+		return strconv.Itoa(*ti.Check.Value.Int), nil
+	case ti.Check.Value.Float != nil:
+		return strconv.FormatFloat(*ti.Check.Value.Float, 'f', -1, 64), nil
+	}
+
+	return "", errors.Errorf("unexpected value for %s check, the value must be a number", ti.Check.Name)
+}
+
+// rangeCheckValue returns the lower and the upper bounds given to a check as SQL literals.
+func rangeCheckValue(ti *scheduler.ColumnCheckInstance) (string, string, error) {
+	invalidValue := errors.Errorf("unexpected value for %s check, the value must be an array of two numbers, e.g. [1, 10]", ti.Check.Name)
+
+	if ti.Check.Value.IntArray != nil {
+		if len(*ti.Check.Value.IntArray) != 2 {
+			return "", "", invalidValue
+		}
+
+		return strconv.Itoa((*ti.Check.Value.IntArray)[0]), strconv.Itoa((*ti.Check.Value.IntArray)[1]), nil
+	}
+
+	// the arrays with floating point numbers are parsed as strings
+	if ti.Check.Value.StringArray != nil {
+		if len(*ti.Check.Value.StringArray) != 2 {
+			return "", "", invalidValue
+		}
+
+		bounds := *ti.Check.Value.StringArray
+		for _, bound := range bounds {
+			if _, err := strconv.ParseFloat(bound, 64); err != nil {
+				return "", "", invalidValue
+			}
+		}
+
+		return bounds[0], bounds[1], nil
+	}
+
+	return "", "", invalidValue
+}
+
+func minCheck(d Dialect, ti *scheduler.ColumnCheckInstance) (*CountCheck, error) {
+	minValue, err := numericCheckValue(ti)
+	if err != nil {
+		return nil, err
+	}
+
+	qq := fmt.Sprintf("SELECT count(*) FROM %s WHERE %s < %s", d.QuoteIdentifier(ti.GetAsset().Name), d.QuoteIdentifier(ti.Column.Name), minValue)
+	return newCountCheck(d, ti, qq, func(count int64) error {
+		return errors.Errorf("column `%s` has %d values below %s", ti.Column.Name, count, minValue)
+	}), nil
+}
+
+func maxCheck(d Dialect, ti *scheduler.ColumnCheckInstance) (*CountCheck, error) {
+	maxValue, err := numericCheckValue(ti)
+	if err != nil {
+		return nil, err
+	}
+
+	qq := fmt.Sprintf("SELECT count(*) FROM %s WHERE %s > %s", d.QuoteIdentifier(ti.GetAsset().Name), d.QuoteIdentifier(ti.Column.Name), maxValue)
+	return newCountCheck(d, ti, qq, func(count int64) error {
+		return errors.Errorf("column `%s` has %d values above %s", ti.Column.Name, count, maxValue)
+	}), nil
+}
+
+func betweenCheck(d Dialect, ti *scheduler.ColumnCheckInstance) (*CountCheck, error) {
+	minValue, maxValue, err := rangeCheckValue(ti)
+	if err != nil {
+		return nil, err
+	}
+
+	qq := fmt.Sprintf("SELECT count(*) FROM %s WHERE %s NOT BETWEEN %s AND %s", d.QuoteIdentifier(ti.GetAsset().Name), d.QuoteIdentifier(ti.Column.Name), minValue, maxValue)
+	return newCountCheck(d, ti, qq, func(count int64) error {
+		return errors.Errorf("column `%s` has %d values outside of the range [%s, %s]", ti.Column.Name, count, minValue, maxValue)
+	}), nil
+}
+
+func negativeCheck(d Dialect, ti *scheduler.ColumnCheckInstance) (*CountCheck, error) {
+	qq := fmt.Sprintf("SELECT count(*) FROM %s WHERE %s >= 0", d.QuoteIdentifier(ti.GetAsset().Name), d.QuoteIdentifier(ti.Column.Name))
+	return newCountCheck(d, ti, qq, func(count int64) error {
+		return errors.Errorf("column `%s` has %d non-negative values", ti.Column.Name, count)
+	}), nil
+}
+
+func nonNegativeCheck(d Dialect, ti *scheduler.ColumnCheckInstance) (*CountCheck, error) {
+	qq := fmt.Sprintf("SELECT count(*) FROM %s WHERE %s < 0", d.QuoteIdentifier(ti.GetAsset().Name), d.QuoteIdentifier(ti.Column.Name))
+	return newCountCheck(d, ti, qq, func(count int64) error {
+		return errors.Errorf("column `%s` has %d negative values", ti.Column.Name, count)
+	}), nil
+}
+
+// patternCheck counts the values that do not match the pattern as a whole.
+func patternCheck(d Dialect, ti *scheduler.ColumnCheckInstance) (*CountCheck, error) {
+	if ti.Check.Value.String == nil || *ti.Check.Value.String == "" {
+		return nil, errors.Errorf("unexpected value for %s check, the value must be a regular expression", ti.Check.Name)
+	}
+
+	column := fmt.Sprintf("CAST(%s as %s)", d.QuoteIdentifier(ti.Column.Name), d.StringType())
+	qq := fmt.Sprintf("SELECT count(*) FROM %s WHERE %s", d.QuoteIdentifier(ti.GetAsset().Name), d.NotMatching(column, *ti.Check.Value.String))
+	return newCountCheck(d, ti, qq, func(count int64) error {
+		return errors.Errorf("column `%s` has %d values that do not match the pattern '%s'", ti.Column.Name, count, *ti.Check.Value.String)
+	}), nil
+}
+
+func notEmptyStringCheck(d Dialect, ti *scheduler.ColumnCheckInstance) (*CountCheck, error) {
+	qq := fmt.Sprintf("SELECT count(*) FROM %s WHERE %s = ''", d.QuoteIdentifier(ti.GetAsset().Name), d.QuoteIdentifier(ti.Column.Name))
+	return newCountCheck(d, ti, qq, func(count int64) error {
+		return errors.Errorf("column `%s` has %d empty strings", ti.Column.Name, count)
+	}), nil
+}
+
+func maxLengthCheck(d Dialect, ti *scheduler.ColumnCheckInstance) (*CountCheck, error) {
+	if ti.Check.Value.Int == nil || *ti.Check.Value.Int < 0 {
+		return nil, errors.Errorf("unexpected value for %s check, the value must be a non-negative integer", ti.Check.Name)
+	}
+
+	maxLength := *ti.Check.Value.Int
+	qq := fmt.Sprintf("SELECT count(*) FROM %s WHERE LENGTH(%s) > %d", d.QuoteIdentifier(ti.GetAsset().Name), d.QuoteIdentifier(ti.Column.Name), maxLength)
+	return newCountCheck(d, ti, qq, func(count int64) error {
+		return errors.Errorf("column `%s` has %d values longer than %d characters", ti.Column.Name, count, maxLength)
+	}), nil
+}
+
+// relationshipCheck counts the values that do not exist in the referenced column, which is given as
+// `<asset name>.<column name>`.
+func relationshipCheck(d Dialect, ti *scheduler.ColumnCheckInstance) (*CountCheck, error) {
+	if ti.Check.Value.String == nil {
+		return nil, errors.Errorf("unexpected value for %s check, the value must be the referenced column in the '<asset name>.<column name>' format", ti.Check.Name)
+	}
+
+	separator := strings.LastIndex(*ti.Check.Value.String, ".")
+	if separator <= 0 || separator == len(*ti.Check.Value.String)-1 {
+		return nil, errors.Errorf("unexpected value for %s check, the value must be the referenced column in the '<asset name>.<column name>' format", ti.Check.Name)
+	}
+
+	referencedAsset := (*ti.Check.Value.String)[:separator]
+	referencedColumn := (*ti.Check.Value.String)[separator+1:]
+
+	column := d.QuoteIdentifier(ti.Column.Name)
+	qq := fmt.Sprintf(
+		"SELECT count(*) FROM %s AS child WHERE child.%s IS NOT NULL AND NOT EXISTS (SELECT 1 FROM %s AS parent WHERE parent.%s = child.%s)",
+		d.QuoteIdentifier(ti.GetAsset().Name), column, d.QuoteIdentifier(referencedAsset), d.QuoteIdentifier(referencedColumn), column,
+	)
+	return newCountCheck(d, ti, qq, func(count int64) error {
+		return errors.Errorf("column `%s` has %d values that do not exist in `%s`.`%s`", ti.Column.Name, count, referencedAsset, referencedColumn)
+	}), nil
+}
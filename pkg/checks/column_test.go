@@ -0,0 +1,286 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/datablast-analytics/blast/pkg/pipeline"
+	"github.com/datablast-analytics/blast/pkg/query"
+	"github.com/datablast-analytics/blast/pkg/scheduler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type testDialect struct{}
+
+func (testDialect) QuoteIdentifier(identifier string) string {
+	return fmt.Sprintf("[%s]", identifier)
+}
+
+func (testDialect) QuoteString(value string) string {
+	return fmt.Sprintf("'%s'", value)
+}
+
+func (testDialect) StringType() string {
+	return "TEXT"
+}
+
+func (testDialect) NotMatching(expression string, pattern string) string {
+	return fmt.Sprintf("%s NOT SIMILAR TO '%s'", expression, pattern)
+}
+
+type mockSelector struct {
+	mock.Mock
+}
+
+func (m *mockSelector) Select(ctx context.Context, q *query.Query) ([][]interface{}, error) {
+	args := m.Called(ctx, q)
+	get := args.Get(0)
+	if get == nil {
+		return nil, args.Error(1)
+	}
+
+	return get.([][]interface{}), args.Error(1)
+}
+
+func columnCheckInstance(check *pipeline.ColumnCheck) *scheduler.ColumnCheckInstance {
+	return &scheduler.ColumnCheckInstance{
+		AssetInstance: &scheduler.AssetInstance{Asset: &pipeline.Asset{Name: "dataset.test_asset"}},
+		Column:        &pipeline.Column{Name: "test_column"},
+		Check:         check,
+	}
+}
+
+func TestNewColumnCheck(t *testing.T) {
+	t.Parallel()
+
+	pattern := `\d+`
+	reference := "dataset.users.id"
+	values := []string{"a", "b"}
+
+	tests := []struct {
+		name      string
+		check     *pipeline.ColumnCheck
+		wantQuery string
+	}{
+		{
+			name:      "not null",
+			check:     &pipeline.ColumnCheck{Name: "not_null"},
+			wantQuery: "SELECT count(*) FROM [dataset.test_asset] WHERE [test_column] IS NULL",
+		},
+		{
+			name:      "accepted values",
+			check:     &pipeline.ColumnCheck{Name: "accepted_values", Value: pipeline.ColumnCheckValue{StringArray: &values}},
+			wantQuery: "SELECT COUNT(*) FROM [dataset.test_asset] WHERE CAST([test_column] as TEXT) NOT IN ('a','b')",
+		},
+		{
+			name:      "pattern",
+			check:     &pipeline.ColumnCheck{Name: "pattern", Value: pipeline.ColumnCheckValue{String: &pattern}},
+			wantQuery: `SELECT count(*) FROM [dataset.test_asset] WHERE CAST([test_column] as TEXT) NOT SIMILAR TO '\d+'`,
+		},
+		{
+			name:  "relationship",
+			check: &pipeline.ColumnCheck{Name: "relationship", Value: pipeline.ColumnCheckValue{String: &reference}},
+			wantQuery: "SELECT count(*) FROM [dataset.test_asset] AS child WHERE child.[test_column] IS NOT NULL AND NOT EXISTS " +
+				"(SELECT 1 FROM [dataset.users] AS parent WHERE parent.[id] = child.[test_column])",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			check, err := NewColumnCheck(testDialect{}, columnCheckInstance(tt.check))
+			require.NoError(t, err)
+			assert.Equal(t, tt.check.Name, check.Name)
+			assert.Equal(t, tt.wantQuery, check.Query.Query)
+			assert.Equal(t, "SELECT count(*) FROM [dataset.test_asset]", check.TotalQuery.Query)
+		})
+	}
+}
+
+func TestNewColumnCheck_InvalidValues(t *testing.T) {
+	t.Parallel()
+
+	text := "some text"
+	noColumn := "users"
+	negative := -1
+
+	tests := []struct {
+		name    string
+		check   string
+		value   pipeline.ColumnCheckValue
+		wantErr string
+	}{
+		{
+			name:    "unknown check",
+			check:   "unknown",
+			wantErr: "there is no executor configured for the test type, test cannot be run: unknown",
+		},
+		{
+			name:    "min without a number",
+			check:   "min",
+			value:   pipeline.ColumnCheckValue{String: &text},
+			wantErr: "unexpected value for min check, the value must be a number",
+		},
+		{
+			name:    "between with a single bound",
+			check:   "between",
+			value:   pipeline.ColumnCheckValue{IntArray: &[]int{1}},
+			wantErr: "unexpected value for between check, the value must be an array of two numbers, e.g. [1, 10]",
+		},
+		{
+			name:    "between with non-numeric bounds",
+			check:   "between",
+			value:   pipeline.ColumnCheckValue{StringArray: &[]string{"a", "b"}},
+			wantErr: "unexpected value for between check, the value must be an array of two numbers, e.g. [1, 10]",
+		},
+		{
+			name:    "accepted values without values",
+			check:   "accepted_values",
+			value:   pipeline.ColumnCheckValue{StringArray: &[]string{}},
+			wantErr: "no values provided for accepted_values check",
+		},
+		{
+			name:    "pattern without a pattern",
+			check:   "regex",
+			wantErr: "unexpected value for regex check, the value must be a regular expression",
+		},
+		{
+			name:    "negative max length",
+			check:   "max_length",
+			value:   pipeline.ColumnCheckValue{Int: &negative},
+			wantErr: "unexpected value for max_length check, the value must be a non-negative integer",
+		},
+		{
+			name:    "relationship without a column",
+			check:   "relationship",
+			value:   pipeline.ColumnCheckValue{String: &noColumn},
+			wantErr: "unexpected value for relationship check, the value must be the referenced column in the '<asset name>.<column name>' format",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewColumnCheck(testDialect{}, columnCheckInstance(&pipeline.ColumnCheck{Name: tt.check, Value: tt.value}))
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestCountCheck_Run(t *testing.T) {
+	t.Parallel()
+
+	countQuery := &query.Query{Query: "SELECT count(*) FROM [dataset.test_asset] WHERE [test_column] IS NULL"}
+	totalQuery := &query.Query{Query: "SELECT count(*) FROM [dataset.test_asset]"}
+
+	rows := int64(5)
+	percent := 10.0
+
+	tests := []struct {
+		name      string
+		threshold pipeline.CheckThreshold
+		setup     func(q *mockSelector)
+		wantErr   string
+	}{
+		{
+			name: "failed to run query",
+			setup: func(q *mockSelector) {
+				q.On("Select", mock.Anything, countQuery).Return(nil, assert.AnError).Once()
+			},
+			wantErr: "failed 'not_null' check: " + assert.AnError.Error(),
+		},
+		{
+			name: "multiple results are returned",
+			setup: func(q *mockSelector) {
+				q.On("Select", mock.Anything, countQuery).Return([][]interface{}{{1}, {2}}, nil).Once()
+			},
+			wantErr: "unexpected result from query during not_null check",
+		},
+		{
+			name: "non-numeric results are rejected",
+			setup: func(q *mockSelector) {
+				q.On("Select", mock.Anything, countQuery).Return([][]interface{}{{"abc"}}, nil).Once()
+			},
+			wantErr: "unexpected result from query during not_null check, cannot cast result to integer",
+		},
+		{
+			name: "no offending rows",
+			setup: func(q *mockSelector) {
+				q.On("Select", mock.Anything, countQuery).Return([][]interface{}{{"0"}}, nil).Once()
+			},
+		},
+		{
+			name: "offending rows without a threshold",
+			setup: func(q *mockSelector) {
+				q.On("Select", mock.Anything, countQuery).Return([][]interface{}{{"5"}}, nil).Once()
+			},
+			wantErr: "column `test_column` has 5 null values",
+		},
+		{
+			name:      "offending rows within the row threshold",
+			threshold: pipeline.CheckThreshold{Rows: &rows},
+			setup: func(q *mockSelector) {
+				q.On("Select", mock.Anything, countQuery).Return([][]interface{}{{int64(5)}}, nil).Once()
+			},
+		},
+		{
+			name:      "offending rows above the row threshold",
+			threshold: pipeline.CheckThreshold{Rows: &rows},
+			setup: func(q *mockSelector) {
+				q.On("Select", mock.Anything, countQuery).Return([][]interface{}{{int64(6)}}, nil).Once()
+			},
+			wantErr: "column `test_column` has 6 null values",
+		},
+		{
+			name:      "offending rows within the percentage threshold",
+			threshold: pipeline.CheckThreshold{Percent: &percent},
+			setup: func(q *mockSelector) {
+				q.On("Select", mock.Anything, countQuery).Return([][]interface{}{{int64(10)}}, nil).Once()
+				q.On("Select", mock.Anything, totalQuery).Return([][]interface{}{{int64(100)}}, nil).Once()
+			},
+		},
+		{
+			name:      "offending rows above the percentage threshold",
+			threshold: pipeline.CheckThreshold{Percent: &percent},
+			setup: func(q *mockSelector) {
+				q.On("Select", mock.Anything, countQuery).Return([][]interface{}{{int64(11)}}, nil).Once()
+				q.On("Select", mock.Anything, totalQuery).Return([][]interface{}{{int64(100)}}, nil).Once()
+			},
+			wantErr: "column `test_column` has 11 null values",
+		},
+		{
+			name:      "failed to count the rows",
+			threshold: pipeline.CheckThreshold{Percent: &percent},
+			setup: func(q *mockSelector) {
+				q.On("Select", mock.Anything, countQuery).Return([][]interface{}{{int64(11)}}, nil).Once()
+				q.On("Select", mock.Anything, totalQuery).Return(nil, assert.AnError).Once()
+			},
+			wantErr: "failed to count the rows for 'not_null' check: " + assert.AnError.Error(),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			q := new(mockSelector)
+			tt.setup(q)
+			defer q.AssertExpectations(t)
+
+			check, err := NewColumnCheck(testDialect{}, columnCheckInstance(&pipeline.ColumnCheck{Name: "not_null", Threshold: tt.threshold}))
+			require.NoError(t, err)
+
+			err = check.Run(context.Background(), q)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
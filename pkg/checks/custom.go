@@ -0,0 +1,57 @@
+package checks
+
+import (
+	"strconv"
+
+	"github.com/datablast-analytics/blast/pkg/pipeline"
+	"github.com/pkg/errors"
+)
+
+// CompareCustomCheckResult compares the single value returned by the query of a custom check with its expected value.
+func CompareCustomCheckResult(check *pipeline.CustomCheck, res [][]interface{}) error {
+	value, err := customCheckResult(check.Name, res)
+	if err != nil {
+		return err
+	}
+
+	if value != check.Value {
+		return errors.Errorf("custom check '%s' has returned %d instead of %d", check.Name, value, check.Value)
+	}
+
+	return nil
+}
+
+// customCheckResult reads the single value returned by the query of a custom check, the booleans are returned as 1 and 0.
+// Some drivers, e.g. Snowflake, return the numeric and the boolean values as strings by default.
+func customCheckResult(check string, res [][]interface{}) (int64, error) {
+	if len(res) != 1 || len(res[0]) != 1 {
+		return 0, errors.Errorf("unexpected result from query during custom check '%s', the query must return a single value", check)
+	}
+
+	switch value := res[0][0].(type) {
+	case bool:
+		return boolToInt(value), nil
+	case int64:
+		return value, nil
+	case int:
+		return int64(value), nil
+	case string:
+		if parsed, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return parsed, nil
+		}
+
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return boolToInt(parsed), nil
+		}
+	}
+
+	return 0, errors.Errorf("unexpected result from query during custom check '%s', cannot cast result to integer or boolean", check)
+}
+
+func boolToInt(value bool) int64 {
+	if value {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,78 @@
+package checks
+
+import (
+	"testing"
+
+	"github.com/datablast-analytics/blast/pkg/pipeline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareCustomCheckResult(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		expected int64
+		result   [][]interface{}
+		wantErr  string
+	}{
+		{
+			name:    "no results are returned",
+			result:  [][]interface{}{},
+			wantErr: "unexpected result from query during custom check 'has_rows', the query must return a single value",
+		},
+		{
+			name:    "multiple columns are returned",
+			result:  [][]interface{}{{int64(1), int64(2)}},
+			wantErr: "unexpected result from query during custom check 'has_rows', the query must return a single value",
+		},
+		{
+			name:     "integer matches the expected value",
+			expected: 5,
+			result:   [][]interface{}{{int64(5)}},
+		},
+		{
+			name:     "int matches the expected value",
+			expected: 5,
+			result:   [][]interface{}{{5}},
+		},
+		{
+			name:     "boolean is compared as an integer",
+			expected: 1,
+			result:   [][]interface{}{{true}},
+		},
+		{
+			name:     "numeric string matches the expected value",
+			expected: 3,
+			result:   [][]interface{}{{"3"}},
+		},
+		{
+			name:   "boolean string is compared as an integer",
+			result: [][]interface{}{{"false"}},
+		},
+		{
+			name:    "result does not match the expected value",
+			result:  [][]interface{}{{true}},
+			wantErr: "custom check 'has_rows' has returned 1 instead of 0",
+		},
+		{
+			name:    "unsupported result",
+			result:  [][]interface{}{{3.5}},
+			wantErr: "unexpected result from query during custom check 'has_rows', cannot cast result to integer or boolean",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := CompareCustomCheckResult(&pipeline.CustomCheck{Name: "has_rows", Value: tt.expected}, tt.result)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
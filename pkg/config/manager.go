@@ -44,9 +44,32 @@ type SnowflakeConnection struct {
 	Warehouse string `yaml:"warehouse"`
 }
 
+type SlackConnection struct {
+	Name       string `yaml:"name"`
+	WebhookURL string `yaml:"webhook_url"`
+}
+
+type WebhookConnection struct {
+	Name    string            `yaml:"name"`
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+}
+
+type SMTPConnection struct {
+	Name     string `yaml:"name"`
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+}
+
 type Connections struct {
 	GoogleCloudPlatform []GoogleCloudPlatformConnection `yaml:"google_cloud_platform"`
-	Snowflake           []SnowflakeConnection
+	Snowflake           []SnowflakeConnection           `yaml:"snowflake"`
+	Slack               []SlackConnection               `yaml:"slack"`
+	Webhook             []WebhookConnection             `yaml:"webhook"`
+	SMTP                []SMTPConnection                `yaml:"smtp"`
 }
 
 type Environment struct {
@@ -33,6 +33,29 @@ func TestLoadFromFile(t *testing.T) {
 					Warehouse: "wh",
 				},
 			},
+			Slack: []SlackConnection{
+				{
+					Name:       "slack-alerts",
+					WebhookURL: "https://hooks.slack.com/services/T000/B000/XXXX",
+				},
+			},
+			Webhook: []WebhookConnection{
+				{
+					Name:    "pagerduty",
+					URL:     "https://events.example.com/v2/enqueue",
+					Headers: map[string]string{"Authorization": "Token token=abc"},
+				},
+			},
+			SMTP: []SMTPConnection{
+				{
+					Name:     "mail",
+					Host:     "smtp.example.com",
+					Port:     587,
+					Username: "blast",
+					Password: "secret",
+					From:     "blast@example.com",
+				},
+			},
 		},
 	}
 
@@ -110,6 +133,9 @@ func TestLoadOrCreate(t *testing.T) {
 				},
 			},
 			Snowflake: []SnowflakeConnection{},
+			Slack:     []SlackConnection{},
+			Webhook:   []WebhookConnection{},
+			SMTP:      []SMTPConnection{},
 		},
 	}
 	existingConfig := &Config{
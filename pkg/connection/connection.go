@@ -5,14 +5,24 @@ import (
 
 	"github.com/datablast-analytics/blast/pkg/bigquery"
 	"github.com/datablast-analytics/blast/pkg/config"
+	"github.com/datablast-analytics/blast/pkg/snowflake"
 )
 
 type Manager struct {
-	BigQuery map[string]*bigquery.Client
+	BigQuery  map[string]*bigquery.Client
+	Snowflake map[string]*snowflake.DB
 }
 
 func (m *Manager) GetConnection(name string) (interface{}, error) {
-	return m.GetBqConnection(name)
+	if db, err := m.GetBqConnection(name); err == nil {
+		return db, nil
+	}
+
+	if db, err := m.GetSfConnection(name); err == nil {
+		return db, nil
+	}
+
+	return nil, errors.New("connection not found: " + name)
 }
 
 func (m *Manager) GetBqConnection(name string) (bigquery.DB, error) {
@@ -28,6 +38,19 @@ func (m *Manager) GetBqConnection(name string) (bigquery.DB, error) {
 	return db, nil
 }
 
+func (m *Manager) GetSfConnection(name string) (snowflake.SfClient, error) {
+	if m.Snowflake == nil {
+		return nil, errors.New("no snowflake connections found")
+	}
+
+	db, ok := m.Snowflake[name]
+	if !ok {
+		return nil, errors.New("snowflake connection not found")
+	}
+
+	return db, nil
+}
+
 func (m *Manager) AddBqConnectionFromConfig(connection *config.GoogleCloudPlatformConnection) error {
 	if m.BigQuery == nil {
 		m.BigQuery = make(map[string]*bigquery.Client)
@@ -48,6 +71,30 @@ func (m *Manager) AddBqConnectionFromConfig(connection *config.GoogleCloudPlatfo
 	return nil
 }
 
+func (m *Manager) AddSfConnectionFromConfig(connection *config.SnowflakeConnection) error {
+	if m.Snowflake == nil {
+		m.Snowflake = make(map[string]*snowflake.DB)
+	}
+
+	db, err := snowflake.NewDB(&snowflake.Config{
+		Account:   connection.Account,
+		Username:  connection.Username,
+		Password:  connection.Password,
+		Region:    connection.Region,
+		Role:      connection.Role,
+		Database:  connection.Database,
+		Schema:    connection.Schema,
+		Warehouse: connection.Warehouse,
+	})
+	if err != nil {
+		return err
+	}
+
+	m.Snowflake[connection.Name] = db
+
+	return nil
+}
+
 func NewManagerFromConfig(cm *config.Config) (*Manager, error) {
 	connectionManager := &Manager{}
 	for _, conn := range cm.SelectedEnvironment.Connections.GoogleCloudPlatform {
@@ -58,5 +105,13 @@ func NewManagerFromConfig(cm *config.Config) (*Manager, error) {
 		}
 	}
 
+	for _, conn := range cm.SelectedEnvironment.Connections.Snowflake {
+		conn := conn
+		err := connectionManager.AddSfConnectionFromConfig(&conn)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return connectionManager, nil
 }
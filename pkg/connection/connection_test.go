@@ -5,6 +5,7 @@ import (
 
 	"github.com/datablast-analytics/blast/pkg/bigquery"
 	"github.com/datablast-analytics/blast/pkg/config"
+	"github.com/datablast-analytics/blast/pkg/snowflake"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
@@ -51,6 +52,47 @@ func TestManager_GetBqConnection(t *testing.T) {
 	}
 }
 
+func TestManager_GetSfConnection(t *testing.T) {
+	t.Parallel()
+
+	existingDB := new(snowflake.DB)
+	m := Manager{
+		Snowflake: map[string]*snowflake.DB{
+			"another":  new(snowflake.DB),
+			"existing": existingDB,
+		},
+	}
+
+	tests := []struct {
+		name           string
+		connectionName string
+		want           snowflake.SfClient
+		wantErr        assert.ErrorAssertionFunc
+	}{
+		{
+			name:           "should return error when no connections are found",
+			connectionName: "non-existing",
+			wantErr:        assert.Error,
+		},
+		{
+			name:           "should find the correct connection",
+			connectionName: "existing",
+			want:           existingDB,
+			wantErr:        assert.NoError,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := m.GetSfConnection(tt.connectionName)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestManager_AddBqConnectionFromConfig(t *testing.T) {
 	t.Parallel()
 
@@ -78,3 +120,39 @@ func TestManager_AddBqConnectionFromConfig(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, res)
 }
+
+func TestManager_AddSfConnectionFromConfig(t *testing.T) {
+	t.Parallel()
+
+	m := Manager{}
+
+	res, err := m.GetSfConnection("test")
+	assert.Error(t, err)
+	assert.Nil(t, res)
+
+	connection := &config.SnowflakeConnection{
+		Name:      "test",
+		Username:  "user",
+		Password:  "pass",
+		Account:   "account",
+		Region:    "region",
+		Role:      "role",
+		Database:  "db",
+		Schema:    "schema",
+		Warehouse: "wh",
+	}
+
+	err = m.AddSfConnectionFromConfig(connection)
+	assert.NoError(t, err)
+
+	res, err = m.GetSfConnection("test")
+	assert.NoError(t, err)
+	assert.NotNil(t, res)
+
+	generic, err := m.GetConnection("test")
+	assert.NoError(t, err)
+	assert.Equal(t, res, generic)
+
+	_, err = m.GetConnection("non-existing")
+	assert.Error(t, err)
+}
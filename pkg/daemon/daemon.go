@@ -0,0 +1,199 @@
+package daemon
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/datablast-analytics/blast/pkg/date"
+	"github.com/datablast-analytics/blast/pkg/pipeline"
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+// maxWait bounds the time the daemon sleeps between the checks, so that the changes in the system clock are noticed.
+const maxWait = time.Minute
+
+// RunFunc executes the pipeline once for the given interval.
+type RunFunc func(ctx context.Context, p *pipeline.Pipeline, interval date.Interval) error
+
+type Config struct {
+	// MaxActiveRuns is the number of intervals of a single pipeline that can run at the same time.
+	MaxActiveRuns int
+	// Catchup runs every interval since the start date of the pipeline, otherwise only the latest due interval is run.
+	Catchup bool
+}
+
+type scheduledPipeline struct {
+	pipeline   *pipeline.Pipeline
+	schedule   cron.Schedule
+	next       time.Time
+	activeRuns int
+}
+
+// Daemon runs the pipelines on their schedules, an interval is run once its end has passed.
+type Daemon struct {
+	logger *zap.SugaredLogger
+	run    RunFunc
+	config Config
+	now    func() time.Time
+
+	lock      sync.Mutex
+	pipelines []*scheduledPipeline
+	finished  chan struct{}
+	wg        sync.WaitGroup
+}
+
+func New(logger *zap.SugaredLogger, run RunFunc, config Config) *Daemon {
+	if config.MaxActiveRuns < 1 {
+		config.MaxActiveRuns = 1
+	}
+
+	return &Daemon{
+		logger:   logger,
+		run:      run,
+		config:   config,
+		now:      time.Now,
+		finished: make(chan struct{}, 1),
+	}
+}
+
+// Add schedules the given pipeline and returns the first interval that will be run. The intervals start from the start
+// date of the pipeline, or from the end of the last run if it is later, e.g. when the daemon is restarted. The pipelines
+// without a start date are scheduled from the current time.
+func (d *Daemon) Add(p *pipeline.Pipeline, lastRunEndDate time.Time) (date.Interval, error) {
+	if p.Schedule == "" {
+		return date.Interval{}, errors.Errorf("the pipeline '%s' does not have a schedule", p.Name)
+	}
+
+	schedule, err := date.ParseSchedule(string(p.Schedule))
+	if err != nil {
+		return date.Interval{}, errors.Wrapf(err, "invalid schedule '%s' in the pipeline '%s'", p.Schedule, p.Name)
+	}
+
+	start := d.now()
+	if p.StartDate != "" {
+		start, err = time.Parse("2006-01-02", p.StartDate)
+		if err != nil {
+			return date.Interval{}, errors.Wrapf(err, "invalid start date '%s' in the pipeline '%s'", p.StartDate, p.Name)
+		}
+	}
+
+	if lastRunEndDate.After(start) {
+		start = lastRunEndDate
+	}
+
+	next := schedule.Next(start.Add(-time.Nanosecond))
+	if !d.config.Catchup {
+		now := d.now()
+		for !schedule.Next(schedule.Next(next)).After(now) {
+			next = schedule.Next(next)
+		}
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	for _, existing := range d.pipelines {
+		if existing.pipeline.Name == p.Name {
+			return date.Interval{}, errors.Errorf("there are multiple pipelines with the name '%s'", p.Name)
+		}
+	}
+
+	d.pipelines = append(d.pipelines, &scheduledPipeline{
+		pipeline: p,
+		schedule: schedule,
+		next:     next,
+	})
+
+	return date.Interval{Start: next, End: schedule.Next(next)}, nil
+}
+
+// Start runs the due intervals until the context is cancelled, then it waits for the active runs to finish.
+func (d *Daemon) Start(ctx context.Context) {
+	for {
+		d.startDueRuns(ctx)
+
+		timer := time.NewTimer(d.untilNextDue())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			d.wg.Wait()
+			return
+		case <-d.finished:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// startDueRuns starts the intervals that have ended as long as their pipelines are below the limit of active runs.
+func (d *Daemon) startDueRuns(ctx context.Context) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	now := d.now()
+	for _, p := range d.pipelines {
+		for p.activeRuns < d.config.MaxActiveRuns {
+			end := p.schedule.Next(p.next)
+			if end.IsZero() || end.After(now) {
+				break
+			}
+
+			interval := date.Interval{Start: p.next, End: end}
+			p.next = end
+			p.activeRuns++
+
+			d.wg.Add(1)
+			go d.runInterval(ctx, p, interval)
+		}
+	}
+}
+
+func (d *Daemon) runInterval(ctx context.Context, p *scheduledPipeline, interval date.Interval) {
+	defer d.wg.Done()
+
+	err := d.run(ctx, p.pipeline, interval)
+	if err != nil {
+		d.logger.Errorf("the run of the pipeline '%s' for the interval %s - %s has failed: %v", p.pipeline.Name, interval.Start, interval.End, err)
+	}
+
+	d.lock.Lock()
+	p.activeRuns--
+	d.lock.Unlock()
+
+	select {
+	case d.finished <- struct{}{}:
+	default:
+	}
+}
+
+// untilNextDue returns the time until the earliest interval that is not started yet ends.
+func (d *Daemon) untilNextDue() time.Duration {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	wait := maxWait
+	now := d.now()
+	for _, p := range d.pipelines {
+		if p.activeRuns >= d.config.MaxActiveRuns {
+			continue
+		}
+
+		end := p.schedule.Next(p.next)
+		if end.IsZero() {
+			continue
+		}
+
+		if until := end.Sub(now); until < wait {
+			wait = until
+		}
+	}
+
+	if wait < 0 {
+		return 0
+	}
+
+	return wait
+}
@@ -0,0 +1,164 @@
+package daemon
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/datablast-analytics/blast/pkg/date"
+	"github.com/datablast-analytics/blast/pkg/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func day(d int) time.Time {
+	return time.Date(2023, 1, d, 0, 0, 0, 0, time.UTC)
+}
+
+type recordingRunner struct {
+	lock      sync.Mutex
+	intervals []date.Interval
+	release   chan struct{}
+}
+
+func (r *recordingRunner) run(ctx context.Context, p *pipeline.Pipeline, interval date.Interval) error {
+	r.lock.Lock()
+	r.intervals = append(r.intervals, interval)
+	r.lock.Unlock()
+
+	<-r.release
+	return nil
+}
+
+func (r *recordingRunner) started() []date.Interval {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return append([]date.Interval{}, r.intervals...)
+}
+
+func TestDaemon_Add(t *testing.T) {
+	t.Parallel()
+
+	now := day(5).Add(10 * time.Hour)
+
+	tests := []struct {
+		name           string
+		pipeline       *pipeline.Pipeline
+		lastRunEndDate time.Time
+		catchup        bool
+		want           date.Interval
+		wantErr        bool
+	}{
+		{
+			name:     "catch-up starts from the start date",
+			pipeline: &pipeline.Pipeline{Name: "p1", Schedule: "daily", StartDate: "2023-01-01"},
+			catchup:  true,
+			want:     date.Interval{Start: day(1), End: day(2)},
+		},
+		{
+			name:           "catch-up continues after the last run",
+			pipeline:       &pipeline.Pipeline{Name: "p1", Schedule: "daily", StartDate: "2023-01-01"},
+			lastRunEndDate: day(3),
+			catchup:        true,
+			want:           date.Interval{Start: day(3), End: day(4)},
+		},
+		{
+			name:     "without catch-up only the latest due interval is run",
+			pipeline: &pipeline.Pipeline{Name: "p1", Schedule: "daily", StartDate: "2023-01-01"},
+			want:     date.Interval{Start: day(4), End: day(5)},
+		},
+		{
+			name:     "pipelines without a start date start from now",
+			pipeline: &pipeline.Pipeline{Name: "p1", Schedule: "daily"},
+			catchup:  true,
+			want:     date.Interval{Start: day(6), End: day(7)},
+		},
+		{
+			name:     "pipelines without a schedule are rejected",
+			pipeline: &pipeline.Pipeline{Name: "p1", StartDate: "2023-01-01"},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid schedules are rejected",
+			pipeline: &pipeline.Pipeline{Name: "p1", Schedule: "every day", StartDate: "2023-01-01"},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := New(zap.NewNop().Sugar(), nil, Config{Catchup: tt.catchup})
+			d.now = func() time.Time { return now }
+
+			got, err := d.Add(tt.pipeline, tt.lastRunEndDate)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDaemon_RejectsDuplicateNames(t *testing.T) {
+	t.Parallel()
+
+	d := New(zap.NewNop().Sugar(), nil, Config{Catchup: true})
+	_, err := d.Add(&pipeline.Pipeline{Name: "p1", Schedule: "daily"}, time.Time{})
+	require.NoError(t, err)
+
+	_, err = d.Add(&pipeline.Pipeline{Name: "p1", Schedule: "hourly"}, time.Time{})
+	assert.Error(t, err)
+}
+
+func TestDaemon_StartsDueRunsWithinTheLimit(t *testing.T) {
+	t.Parallel()
+
+	runner := &recordingRunner{release: make(chan struct{})}
+	d := New(zap.NewNop().Sugar(), runner.run, Config{MaxActiveRuns: 2, Catchup: true})
+	d.now = func() time.Time { return day(4).Add(time.Hour) }
+
+	_, err := d.Add(&pipeline.Pipeline{Name: "p1", Schedule: "daily", StartDate: "2023-01-01"}, time.Time{})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	d.startDueRuns(ctx)
+	assert.Eventually(t, func() bool { return len(runner.started()) == 2 }, time.Second, time.Millisecond)
+
+	// the limit is reached, nothing else is started until one of the runs finishes
+	d.startDueRuns(ctx)
+	assert.Len(t, runner.started(), 2)
+
+	runner.release <- struct{}{}
+	<-d.finished
+	d.startDueRuns(ctx)
+	assert.Eventually(t, func() bool { return len(runner.started()) == 3 }, time.Second, time.Millisecond)
+
+	// the interval that has not ended yet is not started
+	runner.release <- struct{}{}
+	<-d.finished
+	d.startDueRuns(ctx)
+	assert.Len(t, runner.started(), 3)
+
+	close(runner.release)
+	d.wg.Wait()
+
+	assert.ElementsMatch(t, []date.Interval{
+		{Start: day(1), End: day(2)},
+		{Start: day(2), End: day(3)},
+		{Start: day(3), End: day(4)},
+	}, runner.started())
+
+	// the next interval ends in 23 hours, the daemon checks again in a minute at the latest
+	assert.Equal(t, maxWait, d.untilNextDue())
+	d.now = func() time.Time { return day(5).Add(-time.Second) }
+	assert.Equal(t, time.Second, d.untilNextDue())
+}
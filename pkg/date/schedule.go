@@ -0,0 +1,57 @@
+package date
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+)
+
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// ParseSchedule parses the given cron expression, the shorthands such as `daily` or `hourly` are accepted as well.
+func ParseSchedule(schedule string) (cron.Schedule, error) {
+	if schedule == "daily" || schedule == "hourly" || schedule == "weekly" || schedule == "monthly" {
+		schedule = "@" + schedule
+	}
+
+	return cron.ParseStandard(schedule)
+}
+
+// SplitIntoIntervals splits the given range into consecutive intervals between the ticks of the schedule, the parts of
+// the range that do not cover a full interval at the edges are left out.
+func SplitIntoIntervals(schedule string, start, end time.Time) ([]Interval, error) {
+	if schedule == "" {
+		return nil, errors.New("the pipeline does not have a schedule")
+	}
+
+	parsed, err := ParseSchedule(schedule)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid schedule '%s'", schedule)
+	}
+
+	if !start.Before(end) {
+		return nil, errors.New("the start date must be before the end date")
+	}
+
+	intervals := make([]Interval, 0)
+	current := parsed.Next(start.Add(-time.Nanosecond))
+	for {
+		next := parsed.Next(current)
+		if next.After(end) || next.IsZero() {
+			break
+		}
+
+		intervals = append(intervals, Interval{Start: current, End: next})
+		current = next
+	}
+
+	if len(intervals) == 0 {
+		return nil, errors.Errorf("the range between %s and %s does not cover a full interval of the schedule '%s'", start.Format(time.RFC3339), end.Format(time.RFC3339), schedule)
+	}
+
+	return intervals, nil
+}
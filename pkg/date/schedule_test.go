@@ -0,0 +1,111 @@
+package date
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitIntoIntervals(t *testing.T) {
+	t.Parallel()
+
+	day := func(d, h int) time.Time {
+		return time.Date(2023, 3, d, h, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		schedule string
+		start    time.Time
+		end      time.Time
+		want     []Interval
+		wantErr  bool
+	}{
+		{
+			name:     "empty schedule",
+			schedule: "",
+			start:    day(1, 0),
+			end:      day(3, 0),
+			wantErr:  true,
+		},
+		{
+			name:     "invalid schedule",
+			schedule: "every now and then",
+			start:    day(1, 0),
+			end:      day(3, 0),
+			wantErr:  true,
+		},
+		{
+			name:     "start date after the end date",
+			schedule: "daily",
+			start:    day(3, 0),
+			end:      day(1, 0),
+			wantErr:  true,
+		},
+		{
+			name:     "range shorter than an interval",
+			schedule: "daily",
+			start:    day(1, 0),
+			end:      day(1, 12),
+			wantErr:  true,
+		},
+		{
+			name:     "daily",
+			schedule: "daily",
+			start:    day(1, 0),
+			end:      day(4, 0),
+			want: []Interval{
+				{Start: day(1, 0), End: day(2, 0)},
+				{Start: day(2, 0), End: day(3, 0)},
+				{Start: day(3, 0), End: day(4, 0)},
+			},
+		},
+		{
+			name:     "partial intervals at the edges are left out",
+			schedule: "@daily",
+			start:    day(1, 6),
+			end:      day(3, 18),
+			want: []Interval{
+				{Start: day(2, 0), End: day(3, 0)},
+			},
+		},
+		{
+			name:     "hourly",
+			schedule: "hourly",
+			start:    day(1, 22),
+			end:      day(2, 1),
+			want: []Interval{
+				{Start: day(1, 22), End: day(1, 23)},
+				{Start: day(1, 23), End: day(2, 0)},
+				{Start: day(2, 0), End: day(2, 1)},
+			},
+		},
+		{
+			name:     "cron",
+			schedule: "0 6 * * *",
+			start:    day(1, 0),
+			end:      day(3, 12),
+			want: []Interval{
+				{Start: day(1, 6), End: day(2, 6)},
+				{Start: day(2, 6), End: day(3, 6)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := SplitIntoIntervals(tt.schedule, tt.start, tt.end)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package dryrun
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/datablast-analytics/blast/pkg/bigquery"
+	"github.com/datablast-analytics/blast/pkg/executor"
+	"github.com/datablast-analytics/blast/pkg/query"
+	"github.com/datablast-analytics/blast/pkg/snowflake"
+)
+
+// Connections replaces the connection manager during dry runs, the operators receive connections that print the
+// queries along with the name of the connection instead of sending them to the warehouse.
+type Connections struct{}
+
+func (Connections) GetBqConnection(name string) (bigquery.DB, error) {
+	return &queryPrinter{connection: name}, nil
+}
+
+func (Connections) GetSfConnection(name string) (snowflake.SfClient, error) {
+	return &queryPrinter{connection: name}, nil
+}
+
+type queryPrinter struct {
+	connection string
+}
+
+func (p *queryPrinter) RunQueryWithoutResult(ctx context.Context, q *query.Query) error {
+	p.print(ctx, q)
+	return nil
+}
+
+// Select returns a single zero, which is the passing result for the checks that count the offending rows.
+func (p *queryPrinter) Select(ctx context.Context, q *query.Query) ([][]interface{}, error) {
+	p.print(ctx, q)
+	return [][]interface{}{{int64(0)}}, nil
+}
+
+func (p *queryPrinter) print(ctx context.Context, q *query.Query) {
+	writer, ok := ctx.Value(executor.KeyPrinter).(io.Writer)
+	if !ok {
+		return
+	}
+
+	_, _ = fmt.Fprintf(writer, "connection: %s\nquery:\n%s\n", p.connection, indent(strings.TrimSpace(q.String()), "    "))
+}
+
+func indent(text, prefix string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
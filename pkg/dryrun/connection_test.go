@@ -0,0 +1,41 @@
+package dryrun
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/datablast-analytics/blast/pkg/executor"
+	"github.com/datablast-analytics/blast/pkg/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnections_PrintQueries(t *testing.T) {
+	t.Parallel()
+
+	var output bytes.Buffer
+	ctx := context.WithValue(context.Background(), executor.KeyPrinter, &output)
+
+	bq, err := Connections{}.GetBqConnection("gcp-default")
+	require.NoError(t, err)
+	require.NoError(t, bq.RunQueryWithoutResult(ctx, &query.Query{Query: "CREATE TABLE t1 AS\nSELECT 1\n"}))
+
+	sf, err := Connections{}.GetSfConnection("sf-default")
+	require.NoError(t, err)
+	res, err := sf.Select(ctx, &query.Query{Query: "SELECT count(*) FROM t1"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]interface{}{{int64(0)}}, res)
+
+	expected := "connection: gcp-default\nquery:\n    CREATE TABLE t1 AS\n    SELECT 1\n" +
+		"connection: sf-default\nquery:\n    SELECT count(*) FROM t1\n"
+	assert.Equal(t, expected, output.String())
+}
+
+func TestConnections_NoPrinter(t *testing.T) {
+	t.Parallel()
+
+	bq, err := Connections{}.GetBqConnection("gcp-default")
+	require.NoError(t, err)
+	assert.NoError(t, bq.RunQueryWithoutResult(context.Background(), &query.Query{Query: "SELECT 1"}))
+}
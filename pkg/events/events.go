@@ -0,0 +1,130 @@
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type Type string
+
+const (
+	RunStarted       Type = "run_started"
+	InstanceQueued   Type = "instance_queued"
+	InstanceStarted  Type = "instance_started"
+	InstanceLog      Type = "instance_log"
+	InstanceFinished Type = "instance_finished"
+	RunFinished      Type = "run_finished"
+)
+
+// Event is a single step of a run, the instance fields are empty for the run-level events.
+type Event struct {
+	Type      Type       `json:"type"`
+	Time      time.Time  `json:"time"`
+	RunID     string     `json:"run_id"`
+	Pipeline  string     `json:"pipeline,omitempty"`
+	StartDate *time.Time `json:"start_date,omitempty"`
+	EndDate   *time.Time `json:"end_date,omitempty"`
+
+	InstanceID   string `json:"instance_id,omitempty"`
+	InstanceType string `json:"instance_type,omitempty"`
+	Asset        string `json:"asset,omitempty"`
+
+	Status          string  `json:"status,omitempty"`
+	Attempts        int     `json:"attempts,omitempty"`
+	DurationSeconds float64 `json:"duration_seconds,omitempty"`
+	Message         string  `json:"message,omitempty"`
+	Error           string  `json:"error,omitempty"`
+	Warning         string  `json:"warning,omitempty"`
+}
+
+// Publisher is implemented by anything that accepts the events of a run, e.g. the scheduler and the executors publish
+// to it without knowing about the listeners.
+type Publisher interface {
+	Publish(e *Event)
+}
+
+// Listener receives every event published to the bus it is subscribed to, the events are delivered one at a time.
+type Listener interface {
+	Handle(e *Event)
+}
+
+type ListenerFunc func(e *Event)
+
+func (f ListenerFunc) Handle(e *Event) {
+	f(e)
+}
+
+type Bus struct {
+	lock      sync.Mutex
+	listeners []Listener
+}
+
+func NewBus() *Bus {
+	return &Bus{listeners: make([]Listener, 0)}
+}
+
+func (b *Bus) Subscribe(l Listener) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.listeners = append(b.listeners, l)
+}
+
+func (b *Bus) Publish(e *Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for _, l := range b.listeners {
+		l.Handle(e)
+	}
+}
+
+// ForRun returns a publisher that sets the given run ID on the events, which allows the parallel runs of a backfill to
+// share the same bus.
+func (b *Bus) ForRun(runID string) Publisher {
+	return &runPublisher{bus: b, runID: runID}
+}
+
+type runPublisher struct {
+	bus   *Bus
+	runID string
+}
+
+func (p *runPublisher) Publish(e *Event) {
+	e.RunID = p.runID
+	p.bus.Publish(e)
+}
+
+// JSONLinesWriter writes every event as a single line of JSON, the first write error is kept and the following events
+// are dropped.
+type JSONLinesWriter struct {
+	encoder *json.Encoder
+	err     error
+}
+
+func NewJSONLinesWriter(w io.Writer) *JSONLinesWriter {
+	return &JSONLinesWriter{encoder: json.NewEncoder(w)}
+}
+
+func (w *JSONLinesWriter) Handle(e *Event) {
+	if w.err != nil {
+		return
+	}
+
+	err := w.encoder.Encode(e)
+	if err != nil {
+		w.err = errors.Wrap(err, "failed to write the event")
+	}
+}
+
+func (w *JSONLinesWriter) Err() error {
+	return w.err
+}
@@ -0,0 +1,48 @@
+package events_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/datablast-analytics/blast/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBus_Publish(t *testing.T) {
+	t.Parallel()
+
+	var first, second []*events.Event
+	bus := events.NewBus()
+	bus.Subscribe(events.ListenerFunc(func(e *events.Event) { first = append(first, e) }))
+	bus.Subscribe(events.ListenerFunc(func(e *events.Event) { second = append(second, e) }))
+
+	bus.ForRun("run-1").Publish(&events.Event{Type: events.RunStarted})
+	bus.ForRun("run-2").Publish(&events.Event{Type: events.InstanceQueued, InstanceID: "asset1"})
+
+	require.Len(t, first, 2)
+	assert.Equal(t, first, second)
+	assert.Equal(t, "run-1", first[0].RunID)
+	assert.Equal(t, "run-2", first[1].RunID)
+	assert.False(t, first[0].Time.IsZero())
+}
+
+func TestJSONLinesWriter_Handle(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	writer := events.NewJSONLinesWriter(&buf)
+
+	eventTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	writer.Handle(&events.Event{Type: events.RunStarted, Time: eventTime, RunID: "run-1", Pipeline: "pipeline1"})
+	writer.Handle(&events.Event{Type: events.InstanceLog, Time: eventTime, RunID: "run-1", InstanceID: "asset1", Message: "hello"})
+	require.NoError(t, writer.Err())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, []string{
+		`{"type":"run_started","time":"2023-01-01T00:00:00Z","run_id":"run-1","pipeline":"pipeline1"}`,
+		`{"type":"instance_log","time":"2023-01-01T00:00:00Z","run_id":"run-1","instance_id":"asset1","message":"hello"}`,
+	}, lines)
+}
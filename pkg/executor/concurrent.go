@@ -4,13 +4,15 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"os"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/datablast-analytics/blast/pkg/events"
 	"github.com/datablast-analytics/blast/pkg/pipeline"
 	"github.com/datablast-analytics/blast/pkg/scheduler"
 	"github.com/fatih/color"
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
 )
 
@@ -68,9 +70,16 @@ func NewConcurrent(
 	}
 }
 
-func (c Concurrent) Start(input chan scheduler.TaskInstance, result chan<- *scheduler.TaskExecutionResult) {
+// SetEventPublisher sets where the workers publish the started instances and their log lines.
+func (c *Concurrent) SetEventPublisher(publisher events.Publisher) {
+	for _, w := range c.workers {
+		w.events = publisher
+	}
+}
+
+func (c Concurrent) Start(ctx context.Context, input chan scheduler.TaskInstance, result chan<- *scheduler.TaskExecutionResult) {
 	for i := 0; i < c.workerCount; i++ {
-		go c.workers[i].run(input, result)
+		go c.workers[i].run(ctx, input, result)
 	}
 }
 
@@ -80,25 +89,40 @@ type worker struct {
 	logger    *zap.SugaredLogger
 	printer   *color.Color
 	printLock *sync.Mutex
+	events    events.Publisher
 }
 
-func (w worker) run(taskChannel <-chan scheduler.TaskInstance, results chan<- *scheduler.TaskExecutionResult) {
+func (w worker) run(ctx context.Context, taskChannel <-chan scheduler.TaskInstance, results chan<- *scheduler.TaskExecutionResult) {
 	for task := range taskChannel {
+		// the instances that were already queued when the run got cancelled should not be started at all
+		if ctx.Err() != nil {
+			results <- &scheduler.TaskExecutionResult{
+				Instance: task,
+				Error:    ctx.Err(),
+			}
+			continue
+		}
+
 		w.printLock.Lock()
 		w.printer.Printf("[%s] Starting: %s\n", time.Now().Format(timeFormat), task.GetHumanID())
 		w.printLock.Unlock()
 
 		start := time.Now()
+		if w.events != nil {
+			w.events.Publish(scheduler.NewInstanceEvent(events.InstanceStarted, task))
+		}
 
 		printer := &workerWriter{
-			w:           os.Stdout,
+			w:           color.Output,
 			task:        task.GetAsset(),
 			sprintfFunc: w.printer.SprintfFunc(),
 			worker:      w.id,
+			instance:    task,
+			events:      w.events,
 		}
 
-		ctx := context.WithValue(context.Background(), KeyPrinter, printer)
-		err := w.executor.RunSingleTask(ctx, task)
+		taskCtx := context.WithValue(ctx, KeyPrinter, printer)
+		attempts, err := w.runWithRetries(taskCtx, task)
 
 		duration := time.Since(start)
 		durationString := fmt.Sprintf("(%s)", duration.Truncate(time.Millisecond).String())
@@ -107,20 +131,86 @@ func (w worker) run(taskChannel <-chan scheduler.TaskInstance, results chan<- *s
 		w.printLock.Unlock()
 
 		results <- &scheduler.TaskExecutionResult{
-			Instance: task,
-			Error:    err,
+			Instance:   task,
+			Error:      err,
+			Attempts:   attempts,
+			StartedAt:  start,
+			FinishedAt: start.Add(duration),
 		}
 	}
 }
 
+// runWithRetries executes the given instance and retries the main instances upon failure with an exponential backoff.
+// The checks are not retried since their failures are mostly caused by the data rather than transient issues.
+func (w worker) runWithRetries(ctx context.Context, task scheduler.TaskInstance) (int, error) {
+	retries := 0
+	var baseDelay time.Duration
+	if task.GetType() == scheduler.TaskInstanceTypeMain {
+		retries = task.GetPipeline().GetRetriesForAsset(task.GetAsset())
+		baseDelay = task.GetPipeline().GetRetryDelayForAsset(task.GetAsset())
+	}
+
+	attempt := 1
+	for {
+		err := w.runAttempt(ctx, task)
+		if err == nil || attempt > retries || ctx.Err() != nil {
+			return attempt, err
+		}
+
+		delay := backoffDelay(baseDelay, attempt)
+		w.printLock.Lock()
+		w.printer.Printf("[%s] Failed: %s (attempt %d/%d), retrying in %s: %s\n", time.Now().Format(timeFormat), task.GetHumanID(), attempt, retries+1, delay, err)
+		w.printLock.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return attempt, err
+		case <-time.After(delay):
+		}
+
+		attempt++
+	}
+}
+
+// runAttempt executes the instance once, the main instances are bound by the timeout of their assets.
+func (w worker) runAttempt(ctx context.Context, task scheduler.TaskInstance) error {
+	timeout := task.GetAsset().Timeout
+	if timeout <= 0 || task.GetType() != scheduler.TaskInstanceTypeMain {
+		return w.executor.RunSingleTask(ctx, task)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := w.executor.RunSingleTask(timeoutCtx, task)
+	if err != nil && ctx.Err() == nil && errors.Is(timeoutCtx.Err(), context.DeadlineExceeded) {
+		return errors.Errorf("the asset has timed out after %s: %s", timeout, err)
+	}
+
+	return err
+}
+
+// backoffDelay doubles the base delay after every failed attempt.
+func backoffDelay(baseDelay time.Duration, attempt int) time.Duration {
+	return baseDelay * time.Duration(1<<(attempt-1))
+}
+
 type workerWriter struct {
 	w           io.Writer
 	task        *pipeline.Asset
 	sprintfFunc func(format string, a ...interface{}) string
 	worker      string
+	instance    scheduler.TaskInstance
+	events      events.Publisher
 }
 
 func (w *workerWriter) Write(p []byte) (int, error) {
+	if w.events != nil {
+		e := scheduler.NewInstanceEvent(events.InstanceLog, w.instance)
+		e.Message = strings.TrimRight(string(p), "\n")
+		w.events.Publish(e)
+	}
+
 	formatted := w.sprintfFunc("[%s] [%s] %s", time.Now().Format(timeFormat), w.task.Name, string(p))
 
 	n, err := w.w.Write([]byte(formatted))
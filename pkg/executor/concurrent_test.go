@@ -2,7 +2,9 @@ package executor
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/datablast-analytics/blast/pkg/pipeline"
 	"github.com/datablast-analytics/blast/pkg/scheduler"
@@ -67,10 +69,178 @@ func TestConcurrent_Start(t *testing.T) {
 	}
 
 	ex := NewConcurrent(logger, ops, 8)
-	ex.Start(s.WorkQueue, s.Results)
+	ex.Start(context.Background(), s.WorkQueue, s.Results)
 
 	results := s.Run(context.Background())
 	assert.Len(t, results, len(p.Tasks))
 
 	mockOperator.AssertExpectations(t)
 }
+
+func TestConcurrent_Retries(t *testing.T) {
+	t.Parallel()
+
+	retries := 2
+	t11 := &pipeline.Asset{
+		Name:    "task11",
+		Type:    "test",
+		Retries: &retries,
+	}
+
+	t12 := &pipeline.Asset{
+		Name:      "task12",
+		Type:      "test",
+		DependsOn: []string{"task11"},
+	}
+
+	t21 := &pipeline.Asset{
+		Name: "task21",
+		Type: "test",
+	}
+
+	p := &pipeline.Pipeline{
+		Tasks:   []*pipeline.Asset{t11, t12, t21},
+		Retries: 1,
+	}
+
+	matchAsset := func(name string) interface{} {
+		return mock.MatchedBy(func(ti scheduler.TaskInstance) bool {
+			return ti.GetAsset().Name == name
+		})
+	}
+
+	mockOperator := new(mockOperator)
+	mockOperator.On("Run", mock.Anything, matchAsset("task11")).Return(errors.New("quota exceeded")).Twice()
+	mockOperator.On("Run", mock.Anything, matchAsset("task11")).Return(nil).Once()
+	mockOperator.On("Run", mock.Anything, matchAsset("task12")).Return(nil).Once()
+	mockOperator.On("Run", mock.Anything, matchAsset("task21")).Return(errors.New("some error")).Twice()
+
+	logger := zap.NewNop().Sugar()
+	s := scheduler.NewScheduler(logger, p)
+
+	ops := map[pipeline.AssetType]Config{
+		"test": {
+			scheduler.TaskInstanceTypeMain: mockOperator,
+		},
+	}
+
+	ex := NewConcurrent(logger, ops, 8)
+	ex.Start(context.Background(), s.WorkQueue, s.Results)
+
+	results := s.Run(context.Background())
+	assert.Len(t, results, len(p.Tasks))
+
+	attempts := make(map[string]int)
+	for _, res := range results {
+		attempts[res.Instance.GetAsset().Name] = res.Attempts
+		if res.Instance.GetAsset().Name == "task21" {
+			assert.Error(t, res.Error)
+		} else {
+			assert.NoError(t, res.Error)
+		}
+	}
+
+	assert.Equal(t, map[string]int{"task11": 3, "task12": 1, "task21": 2}, attempts)
+	mockOperator.AssertExpectations(t)
+}
+
+func Test_backoffDelay(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 10*time.Second, backoffDelay(10*time.Second, 1))
+	assert.Equal(t, 20*time.Second, backoffDelay(10*time.Second, 2))
+	assert.Equal(t, 40*time.Second, backoffDelay(10*time.Second, 3))
+	assert.Equal(t, time.Duration(0), backoffDelay(0, 3))
+}
+
+func TestConcurrent_Timeout(t *testing.T) {
+	t.Parallel()
+
+	t11 := &pipeline.Asset{
+		Name:    "task11",
+		Type:    "test",
+		Timeout: 50 * time.Millisecond,
+	}
+
+	t12 := &pipeline.Asset{
+		Name:      "task12",
+		Type:      "test",
+		DependsOn: []string{"task11"},
+	}
+
+	p := &pipeline.Pipeline{
+		Tasks: []*pipeline.Asset{t11, t12},
+	}
+
+	mockOperator := new(mockOperator)
+	mockOperator.On("Run", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			<-args.Get(0).(context.Context).Done()
+		}).
+		Return(context.DeadlineExceeded).
+		Once()
+
+	logger := zap.NewNop().Sugar()
+	s := scheduler.NewScheduler(logger, p)
+
+	ops := map[pipeline.AssetType]Config{
+		"test": {
+			scheduler.TaskInstanceTypeMain: mockOperator,
+		},
+	}
+
+	ex := NewConcurrent(logger, ops, 8)
+	ex.Start(context.Background(), s.WorkQueue, s.Results)
+
+	results := s.Run(context.Background())
+	assert.Len(t, results, 1)
+	assert.ErrorContains(t, results[0].Error, "the asset has timed out after 50ms")
+
+	assert.Equal(t, scheduler.Failed, s.GetInstanceStatuses()["task11"])
+	assert.Equal(t, scheduler.UpstreamFailed, s.GetInstanceStatuses()["task12"])
+	mockOperator.AssertExpectations(t)
+}
+
+func TestConcurrent_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	p := &pipeline.Pipeline{
+		Tasks: []*pipeline.Asset{
+			{Name: "task11", Type: "test"},
+			{Name: "task12", Type: "test", DependsOn: []string{"task11"}},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockOperator := new(mockOperator)
+	mockOperator.On("Run", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			cancel()
+			<-args.Get(0).(context.Context).Done()
+		}).
+		Return(context.Canceled).
+		Once()
+
+	logger := zap.NewNop().Sugar()
+	s := scheduler.NewScheduler(logger, p)
+
+	ops := map[pipeline.AssetType]Config{
+		"test": {
+			scheduler.TaskInstanceTypeMain: mockOperator,
+		},
+	}
+
+	ex := NewConcurrent(logger, ops, 8)
+	ex.Start(ctx, s.WorkQueue, s.Results)
+
+	results := s.Run(ctx)
+	assert.Len(t, results, 1)
+
+	assert.Equal(t, map[string]scheduler.TaskInstanceStatus{
+		"task11": scheduler.Cancelled,
+		"task12": scheduler.Cancelled,
+	}, s.GetInstanceStatuses())
+	mockOperator.AssertExpectations(t)
+}
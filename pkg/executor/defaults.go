@@ -10,6 +10,10 @@ const (
 	TaskTypeSnowflakeQuery = pipeline.AssetType("sf.sql")
 	TaskTypeBigqueryQuery  = pipeline.AssetType("bq.sql")
 	TaskTypeEmpty          = pipeline.AssetType("empty")
+
+	TaskTypeBigqueryTableSensor     = pipeline.AssetType("bq.sensor.table")
+	TaskTypeBigqueryQuerySensor     = pipeline.AssetType("bq.sensor.query")
+	TaskTypeBigqueryPartitionSensor = pipeline.AssetType("bq.sensor.partition")
 )
 
 type Config map[scheduler.TaskInstanceType]Operator
@@ -18,11 +22,12 @@ var DefaultExecutorsV2 = map[pipeline.AssetType]Config{
 	TaskTypeBigqueryQuery: {
 		scheduler.TaskInstanceTypeMain:        NoOpOperator{},
 		scheduler.TaskInstanceTypeColumnCheck: NoOpOperator{},
+		scheduler.TaskInstanceTypeCustomTest:  NoOpOperator{},
 	},
-	"bq.sensor.table": {
+	TaskTypeBigqueryTableSensor: {
 		scheduler.TaskInstanceTypeMain: NoOpOperator{},
 	},
-	"bq.sensor.query": {
+	TaskTypeBigqueryQuerySensor: {
 		scheduler.TaskInstanceTypeMain: NoOpOperator{},
 	},
 	"bq.cost_tracker": {
@@ -31,7 +36,7 @@ var DefaultExecutorsV2 = map[pipeline.AssetType]Config{
 	"bq.transfer": {
 		scheduler.TaskInstanceTypeMain: NoOpOperator{},
 	},
-	"bq.sensor.partition": {
+	TaskTypeBigqueryPartitionSensor: {
 		scheduler.TaskInstanceTypeMain: NoOpOperator{},
 	},
 	"gcs.from.s3": {
@@ -68,7 +73,9 @@ var DefaultExecutorsV2 = map[pipeline.AssetType]Config{
 		scheduler.TaskInstanceTypeMain: NoOpOperator{},
 	},
 	TaskTypeSnowflakeQuery: {
-		scheduler.TaskInstanceTypeMain: NoOpOperator{},
+		scheduler.TaskInstanceTypeMain:        NoOpOperator{},
+		scheduler.TaskInstanceTypeColumnCheck: NoOpOperator{},
+		scheduler.TaskInstanceTypeCustomTest:  NoOpOperator{},
 	},
 	"adjust.export.bq": {
 		scheduler.TaskInstanceTypeMain: NoOpOperator{},
@@ -77,3 +84,18 @@ var DefaultExecutorsV2 = map[pipeline.AssetType]Config{
 		scheduler.TaskInstanceTypeMain: NoOpOperator{},
 	},
 }
+
+// CopyDefaultExecutors returns a copy of the default executors that can be modified without affecting the other runs
+// in the same process, e.g. the intervals of a backfill.
+func CopyDefaultExecutors() map[pipeline.AssetType]Config {
+	executors := make(map[pipeline.AssetType]Config, len(DefaultExecutorsV2))
+	for assetType, config := range DefaultExecutorsV2 {
+		copied := make(Config, len(config))
+		for instanceType, operator := range config {
+			copied[instanceType] = operator
+		}
+		executors[assetType] = copied
+	}
+
+	return executors
+}
@@ -0,0 +1,18 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/datablast-analytics/blast/pkg/scheduler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopyDefaultExecutors(t *testing.T) {
+	t.Parallel()
+
+	executors := CopyDefaultExecutors()
+	assert.Len(t, executors, len(DefaultExecutorsV2))
+
+	executors[TaskTypePython][scheduler.TaskInstanceTypeMain] = new(mockOperator)
+	assert.Equal(t, NoOpOperator{}, DefaultExecutorsV2[TaskTypePython][scheduler.TaskInstanceTypeMain])
+}
@@ -0,0 +1,388 @@
+package history
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/datablast-analytics/blast/pkg/scheduler"
+	"github.com/pkg/errors"
+	"go.etcd.io/bbolt"
+)
+
+var runsBucket = []byte("runs")
+
+// The triggers tell what has started a run.
+const (
+	TriggerManual   = "manual"
+	TriggerBackfill = "backfill"
+	TriggerSchedule = "schedule"
+)
+
+// Run is a single execution of a pipeline. A resumed run keeps the ID of the original one, therefore the instances
+// that were not executed again keep their records from the previous executions.
+type Run struct {
+	ID          string      `json:"id"`
+	Pipeline    string      `json:"pipeline"`
+	Environment string      `json:"environment"`
+	Trigger     string      `json:"trigger,omitempty"`
+	StartDate   time.Time   `json:"start_date"`
+	EndDate     time.Time   `json:"end_date"`
+	StartedAt   time.Time   `json:"started_at"`
+	FinishedAt  time.Time   `json:"finished_at"`
+	Instances   []*Instance `json:"instances"`
+}
+
+func (r *Run) Duration() time.Duration {
+	return r.FinishedAt.Sub(r.StartedAt)
+}
+
+// Succeeded reports whether none of the instances of the run has failed or has been cancelled.
+func (r *Run) Succeeded() bool {
+	return r.InstanceCountByStatus(scheduler.Failed) == 0 &&
+		r.InstanceCountByStatus(scheduler.UpstreamFailed) == 0 &&
+		r.InstanceCountByStatus(scheduler.Cancelled) == 0
+}
+
+func (r *Run) InstanceCountByStatus(status scheduler.TaskInstanceStatus) int {
+	count := 0
+	for _, instance := range r.Instances {
+		if instance.Status == status {
+			count++
+		}
+	}
+
+	return count
+}
+
+type Instance struct {
+	ID         string                       `json:"id"`
+	Asset      string                       `json:"asset"`
+	Type       string                       `json:"type"`
+	Status     scheduler.TaskInstanceStatus `json:"status"`
+	StartedAt  time.Time                    `json:"started_at"`
+	FinishedAt time.Time                    `json:"finished_at"`
+	Attempts   int                          `json:"attempts,omitempty"`
+	Error      string                       `json:"error,omitempty"`
+}
+
+func (i *Instance) Executed() bool {
+	return !i.StartedAt.IsZero()
+}
+
+func (i *Instance) Duration() time.Duration {
+	if !i.Executed() {
+		return 0
+	}
+
+	return i.FinishedAt.Sub(i.StartedAt)
+}
+
+// AssetExecution is a single execution of the main instance of an asset in a given run.
+type AssetExecution struct {
+	RunID    string
+	Instance *Instance
+}
+
+type AssetStats struct {
+	Executions      []*AssetExecution
+	Failures        int
+	AverageDuration time.Duration
+}
+
+func (s AssetStats) FailureRate() float64 {
+	if len(s.Executions) == 0 {
+		return 0
+	}
+
+	return float64(s.Failures) / float64(len(s.Executions))
+}
+
+type Store struct {
+	db *bbolt.DB
+}
+
+// Open opens the history database at the given path, it waits for a while if another process is holding the database,
+// e.g. when multiple runs finish at the same time.
+func Open(path string) (*Store, error) {
+	db, err := bbolt.Open(path, 0o600, &bbolt.Options{Timeout: 10 * time.Second})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open the history database at '%s'", path)
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(runsBucket)
+		return err
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, errors.Wrap(err, "failed to initialize the history database")
+	}
+
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func (s *Store) SaveRun(run *Run) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(runsBucket)
+
+		existing := bucket.Get([]byte(run.ID))
+		if existing != nil {
+			var previous Run
+			err := json.Unmarshal(existing, &previous)
+			if err != nil {
+				return errors.Wrapf(err, "failed to parse the existing record for run '%s'", run.ID)
+			}
+
+			run.StartedAt = previous.StartedAt
+			mergePreviousInstances(run, &previous)
+		}
+
+		content, err := json.Marshal(run)
+		if err != nil {
+			return errors.Wrap(err, "failed to serialize the run")
+		}
+
+		return bucket.Put([]byte(run.ID), content)
+	})
+}
+
+func mergePreviousInstances(run, previous *Run) {
+	previousInstances := make(map[string]*Instance, len(previous.Instances))
+	for _, instance := range previous.Instances {
+		previousInstances[instance.ID] = instance
+	}
+
+	for i, instance := range run.Instances {
+		if instance.Executed() {
+			continue
+		}
+
+		if old, ok := previousInstances[instance.ID]; ok && old.Executed() {
+			run.Instances[i] = old
+		}
+	}
+}
+
+func (s *Store) GetRun(id string) (*Run, error) {
+	var run *Run
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		content := tx.Bucket(runsBucket).Get([]byte(id))
+		if content == nil {
+			return errors.Errorf("there is no run with the ID '%s'", id)
+		}
+
+		run = &Run{}
+		return json.Unmarshal(content, run)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return run, nil
+}
+
+// ListRuns returns the most recent runs first, a non-positive limit returns all the runs.
+func (s *Store) ListRuns(limit int) ([]*Run, error) {
+	runs := make([]*Run, 0)
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(runsBucket).ForEach(func(_, content []byte) error {
+			var run Run
+			err := json.Unmarshal(content, &run)
+			if err != nil {
+				return err
+			}
+
+			runs = append(runs, &run)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the runs")
+	}
+
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].StartedAt.After(runs[j].StartedAt)
+	})
+
+	if limit > 0 && len(runs) > limit {
+		runs = runs[:limit]
+	}
+
+	return runs, nil
+}
+
+// GetAssetStats returns the most recent executions of the given asset of the pipeline along with their failure rate and
+// average duration, the runs where the asset was not executed are ignored. An empty environment covers all of them.
+func (s *Store) GetAssetStats(pipeline, environment, asset string, limit int) (*AssetStats, error) {
+	runs, err := s.ListRuns(0)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &AssetStats{
+		Executions: make([]*AssetExecution, 0),
+	}
+
+	var totalDuration time.Duration
+	for _, run := range runs {
+		if limit > 0 && len(stats.Executions) >= limit {
+			break
+		}
+
+		if run.Pipeline != pipeline || (environment != "" && run.Environment != environment) {
+			continue
+		}
+
+		for _, instance := range run.Instances {
+			if instance.Asset != asset || instance.Type != scheduler.TaskInstanceTypeMain.String() || !instance.Executed() {
+				continue
+			}
+
+			stats.Executions = append(stats.Executions, &AssetExecution{RunID: run.ID, Instance: instance})
+			totalDuration += instance.Duration()
+			if instance.Status == scheduler.Failed {
+				stats.Failures++
+			}
+		}
+	}
+
+	if len(stats.Executions) > 0 {
+		stats.AverageDuration = totalDuration / time.Duration(len(stats.Executions))
+	}
+
+	return stats, nil
+}
+
+// GetAverageDurations returns the average duration of every instance of the pipeline keyed by their IDs, only the most
+// recent executions up to the given limit are taken into account for every instance.
+func (s *Store) GetAverageDurations(pipeline string, limit int) (map[string]time.Duration, error) {
+	runs, err := s.ListRuns(0)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]time.Duration)
+	counts := make(map[string]int)
+	for _, run := range runs {
+		if run.Pipeline != pipeline {
+			continue
+		}
+
+		for _, instance := range run.Instances {
+			if !instance.Executed() || (limit > 0 && counts[instance.ID] >= limit) {
+				continue
+			}
+
+			totals[instance.ID] += instance.Duration()
+			counts[instance.ID]++
+		}
+	}
+
+	durations := make(map[string]time.Duration, len(totals))
+	for id, total := range totals {
+		durations[id] = total / time.Duration(counts[id])
+	}
+
+	return durations, nil
+}
+
+// GetLastRunEndDate returns the latest end date among the successful runs the scheduler has started for the pipeline
+// in the given environment, it is zero if there are none. The manual runs and the backfills may cover only a part of
+// the pipeline or of the schedule, therefore they are not taken into account.
+func (s *Store) GetLastRunEndDate(pipeline, environment string) (time.Time, error) {
+	runs, err := s.ListRuns(0)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var last time.Time
+	for _, run := range runs {
+		if run.Pipeline != pipeline || run.Environment != environment || run.Trigger != TriggerSchedule || !run.Succeeded() {
+			continue
+		}
+
+		if run.EndDate.After(last) {
+			last = run.EndDate
+		}
+	}
+
+	return last, nil
+}
+
+// GetFailedScheduledRuns returns the failed runs the scheduler has started for the pipeline in the given environment for
+// the intervals that end by the given time, starting from the earliest interval. The scheduler does not run these
+// intervals again since it continues after the last successful run, the intervals that have succeeded in another run
+// later on are left out.
+func (s *Store) GetFailedScheduledRuns(pipeline, environment string, until time.Time) ([]*Run, error) {
+	runs, err := s.ListRuns(0)
+	if err != nil {
+		return nil, err
+	}
+
+	succeededIntervals := make(map[time.Time]bool)
+	failed := make([]*Run, 0)
+	for _, run := range runs {
+		if run.Pipeline != pipeline || run.Environment != environment || run.Trigger != TriggerSchedule {
+			continue
+		}
+
+		if run.Succeeded() {
+			succeededIntervals[run.StartDate.UTC()] = true
+			continue
+		}
+
+		if !run.EndDate.After(until) {
+			failed = append(failed, run)
+		}
+	}
+
+	unresolved := make([]*Run, 0, len(failed))
+	for _, run := range failed {
+		if !succeededIntervals[run.StartDate.UTC()] {
+			unresolved = append(unresolved, run)
+		}
+	}
+
+	sort.SliceStable(unresolved, func(i, j int) bool {
+		return unresolved[i].StartDate.Before(unresolved[j].StartDate)
+	})
+
+	return unresolved, nil
+}
+
+// NewInstances builds the instance records from the final state of the scheduler, the instances that were not executed
+// in this run only carry their statuses.
+func NewInstances(instances []scheduler.TaskInstance, results []*scheduler.TaskExecutionResult) []*Instance {
+	resultsByID := make(map[string]*scheduler.TaskExecutionResult, len(results))
+	for _, result := range results {
+		resultsByID[result.Instance.GetHumanID()] = result
+	}
+
+	records := make([]*Instance, 0, len(instances))
+	for _, instance := range instances {
+		record := &Instance{
+			ID:     instance.GetHumanID(),
+			Asset:  instance.GetAsset().Name,
+			Type:   instance.GetType().String(),
+			Status: instance.GetStatus(),
+		}
+
+		if result, ok := resultsByID[record.ID]; ok {
+			record.StartedAt = result.StartedAt
+			record.FinishedAt = result.FinishedAt
+			record.Attempts = result.Attempts
+			if result.Error != nil {
+				record.Error = result.Error.Error()
+			}
+		}
+
+		records = append(records, record)
+	}
+
+	return records
+}
@@ -0,0 +1,334 @@
+package history
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/datablast-analytics/blast/pkg/pipeline"
+	"github.com/datablast-analytics/blast/pkg/scheduler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func openTestStore(t *testing.T) *Store {
+	s, err := Open(filepath.Join(t.TempDir(), "history.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+
+	return s
+}
+
+func executed(id string, status scheduler.TaskInstanceStatus, startedAt time.Time, duration time.Duration) *Instance {
+	return &Instance{
+		ID:         id,
+		Asset:      id,
+		Type:       "main",
+		Status:     status,
+		StartedAt:  startedAt,
+		FinishedAt: startedAt.Add(duration),
+		Attempts:   1,
+	}
+}
+
+func TestStore_SaveAndListRuns(t *testing.T) {
+	t.Parallel()
+
+	s := openTestStore(t)
+	now := time.Now().Truncate(time.Second)
+
+	for i, id := range []string{"run-1", "run-2", "run-3"} {
+		err := s.SaveRun(&Run{
+			ID:          id,
+			Pipeline:    "pipeline",
+			Environment: "dev",
+			StartedAt:   now.Add(time.Duration(i) * time.Minute),
+			FinishedAt:  now.Add(time.Duration(i)*time.Minute + time.Second),
+		})
+		require.NoError(t, err)
+	}
+
+	runs, err := s.ListRuns(0)
+	require.NoError(t, err)
+	require.Len(t, runs, 3)
+	assert.Equal(t, "run-3", runs[0].ID)
+	assert.Equal(t, "run-1", runs[2].ID)
+	assert.Equal(t, time.Second, runs[0].Duration())
+
+	runs, err = s.ListRuns(2)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, "run-2", runs[1].ID)
+
+	_, err = s.GetRun("some-missing-run")
+	assert.Error(t, err)
+}
+
+func TestStore_SaveRunMergesResumedRuns(t *testing.T) {
+	t.Parallel()
+
+	s := openTestStore(t)
+	now := time.Now().Truncate(time.Second)
+
+	err := s.SaveRun(&Run{
+		ID:        "run-1",
+		StartedAt: now,
+		Instances: []*Instance{
+			executed("task1", scheduler.Succeeded, now, time.Second),
+			executed("task2", scheduler.Failed, now, time.Second),
+			{ID: "task3", Asset: "task3", Type: "main", Status: scheduler.UpstreamFailed},
+		},
+	})
+	require.NoError(t, err)
+
+	resumedAt := now.Add(time.Hour)
+	err = s.SaveRun(&Run{
+		ID:        "run-1",
+		StartedAt: resumedAt,
+		Instances: []*Instance{
+			{ID: "task1", Asset: "task1", Type: "main", Status: scheduler.Succeeded},
+			executed("task2", scheduler.Succeeded, resumedAt, 2*time.Second),
+			executed("task3", scheduler.Succeeded, resumedAt, 3*time.Second),
+		},
+	})
+	require.NoError(t, err)
+
+	run, err := s.GetRun("run-1")
+	require.NoError(t, err)
+	assert.True(t, run.StartedAt.Equal(now))
+	assert.Equal(t, 3, run.InstanceCountByStatus(scheduler.Succeeded))
+	assert.Equal(t, time.Second, run.Instances[0].Duration())
+	assert.Equal(t, 2*time.Second, run.Instances[1].Duration())
+	assert.Equal(t, 3*time.Second, run.Instances[2].Duration())
+}
+
+func TestStore_GetAssetStats(t *testing.T) {
+	t.Parallel()
+
+	s := openTestStore(t)
+	now := time.Now().Truncate(time.Second)
+
+	statuses := []scheduler.TaskInstanceStatus{scheduler.Succeeded, scheduler.Failed, scheduler.Succeeded, scheduler.Succeeded}
+	for i, status := range statuses {
+		startedAt := now.Add(time.Duration(i) * time.Hour)
+		err := s.SaveRun(&Run{
+			ID:          string(rune('a' + i)),
+			Pipeline:    "pipeline",
+			Environment: "prod",
+			StartedAt:   startedAt,
+			Instances: []*Instance{
+				executed("task1", status, startedAt, time.Duration(i+1)*time.Second),
+				{ID: "task1:col1:not_null", Asset: "task1", Type: "column_test", Status: status, StartedAt: startedAt, FinishedAt: startedAt},
+				executed("task2", scheduler.Succeeded, startedAt, time.Second),
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	// the assets with the same name in other pipelines or environments are not mixed in
+	for i, run := range []*Run{{ID: "other-pipeline", Pipeline: "other", Environment: "prod"}, {ID: "dev", Pipeline: "pipeline", Environment: "dev"}} {
+		run.StartedAt = now.Add(time.Duration(10+i) * time.Hour)
+		run.Instances = []*Instance{executed("task1", scheduler.Failed, run.StartedAt, time.Minute)}
+		require.NoError(t, s.SaveRun(run))
+	}
+
+	stats, err := s.GetAssetStats("pipeline", "prod", "task1", 0)
+	require.NoError(t, err)
+	assert.Len(t, stats.Executions, 4)
+	assert.Equal(t, 1, stats.Failures)
+	assert.InDelta(t, 0.25, stats.FailureRate(), 0.0001)
+	assert.Equal(t, 2500*time.Millisecond, stats.AverageDuration)
+	assert.Equal(t, "d", stats.Executions[0].RunID)
+
+	stats, err = s.GetAssetStats("pipeline", "prod", "task1", 2)
+	require.NoError(t, err)
+	assert.Len(t, stats.Executions, 2)
+	assert.Equal(t, 0, stats.Failures)
+	assert.Equal(t, 3500*time.Millisecond, stats.AverageDuration)
+
+	stats, err = s.GetAssetStats("pipeline", "", "task1", 0)
+	require.NoError(t, err)
+	assert.Len(t, stats.Executions, 5)
+	assert.Equal(t, "dev", stats.Executions[0].RunID)
+
+	stats, err = s.GetAssetStats("pipeline", "prod", "some-other-task", 0)
+	require.NoError(t, err)
+	assert.Empty(t, stats.Executions)
+	assert.Zero(t, stats.FailureRate())
+}
+
+func TestStore_GetAverageDurations(t *testing.T) {
+	t.Parallel()
+
+	s := openTestStore(t)
+	now := time.Now().Truncate(time.Second)
+
+	runs := []*Run{
+		{
+			ID:        "run-1",
+			Pipeline:  "pipeline",
+			StartedAt: now,
+			Instances: []*Instance{
+				executed("asset1", scheduler.Succeeded, now, 10*time.Minute),
+				executed("asset2", scheduler.Succeeded, now, time.Minute),
+			},
+		},
+		{
+			ID:        "run-2",
+			Pipeline:  "pipeline",
+			StartedAt: now.Add(time.Hour),
+			Instances: []*Instance{
+				executed("asset1", scheduler.Failed, now.Add(time.Hour), 2*time.Minute),
+				{ID: "asset2", Status: scheduler.UpstreamFailed},
+			},
+		},
+		{
+			ID:        "run-3",
+			Pipeline:  "pipeline",
+			StartedAt: now.Add(2 * time.Hour),
+			Instances: []*Instance{
+				executed("asset1", scheduler.Succeeded, now.Add(2*time.Hour), 4*time.Minute),
+			},
+		},
+		{
+			ID:        "run-4",
+			Pipeline:  "other-pipeline",
+			StartedAt: now.Add(3 * time.Hour),
+			Instances: []*Instance{
+				executed("asset1", scheduler.Succeeded, now.Add(3*time.Hour), time.Hour),
+			},
+		},
+	}
+	for _, run := range runs {
+		require.NoError(t, s.SaveRun(run))
+	}
+
+	durations, err := s.GetAverageDurations("pipeline", 0)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{"asset1": 16 * time.Minute / 3, "asset2": time.Minute}, durations)
+
+	durations, err = s.GetAverageDurations("pipeline", 2)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{"asset1": 3 * time.Minute, "asset2": time.Minute}, durations)
+}
+
+func TestStore_GetLastRunEndDate(t *testing.T) {
+	t.Parallel()
+
+	s := openTestStore(t)
+	day := func(d int) time.Time {
+		return time.Date(2023, 1, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	succeeded := []*Instance{{ID: "task1", Asset: "task1", Type: "main", Status: scheduler.Succeeded}}
+	failed := []*Instance{
+		{ID: "task1", Asset: "task1", Type: "main", Status: scheduler.Failed},
+		{ID: "task2", Asset: "task2", Type: "main", Status: scheduler.UpstreamFailed},
+	}
+	runs := []*Run{
+		{ID: "run-1", Pipeline: "pipeline", Environment: "prod", Trigger: TriggerSchedule, StartDate: day(1), EndDate: day(2), StartedAt: day(2), Instances: succeeded},
+		{ID: "run-2", Pipeline: "pipeline", Environment: "prod", Trigger: TriggerSchedule, StartDate: day(3), EndDate: day(4), StartedAt: day(4), Instances: succeeded},
+		{ID: "run-3", Pipeline: "pipeline", Environment: "prod", Trigger: TriggerSchedule, StartDate: day(2), EndDate: day(3), StartedAt: day(5), Instances: succeeded},
+		{ID: "run-4", Pipeline: "pipeline", Environment: "dev", Trigger: TriggerSchedule, StartDate: day(9), EndDate: day(10), StartedAt: day(10), Instances: succeeded},
+		{ID: "run-5", Pipeline: "other-pipeline", Environment: "prod", Trigger: TriggerSchedule, StartDate: day(9), EndDate: day(10), StartedAt: day(10), Instances: succeeded},
+		{ID: "run-6", Pipeline: "pipeline", Environment: "prod", Trigger: TriggerSchedule, StartDate: day(4), EndDate: day(5), StartedAt: day(6), Instances: failed},
+		{ID: "run-7", Pipeline: "pipeline", Environment: "prod", Trigger: TriggerManual, StartDate: day(5), EndDate: day(6), StartedAt: day(7), Instances: succeeded},
+		{ID: "run-8", Pipeline: "pipeline", Environment: "prod", Trigger: TriggerBackfill, StartDate: day(6), EndDate: day(7), StartedAt: day(8), Instances: succeeded},
+		{ID: "run-9", Pipeline: "pipeline", Environment: "prod", StartDate: day(7), EndDate: day(8), StartedAt: day(9), Instances: succeeded},
+	}
+	for _, run := range runs {
+		require.NoError(t, s.SaveRun(run))
+	}
+
+	last, err := s.GetLastRunEndDate("pipeline", "prod")
+	require.NoError(t, err)
+	assert.True(t, day(4).Equal(last))
+
+	last, err = s.GetLastRunEndDate("missing", "prod")
+	require.NoError(t, err)
+	assert.True(t, last.IsZero())
+}
+
+func TestStore_GetFailedScheduledRuns(t *testing.T) {
+	t.Parallel()
+
+	s := openTestStore(t)
+	day := func(d int) time.Time {
+		return time.Date(2023, 1, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	succeeded := []*Instance{{ID: "task1", Asset: "task1", Type: "main", Status: scheduler.Succeeded}}
+	failed := []*Instance{{ID: "task1", Asset: "task1", Type: "main", Status: scheduler.Failed}}
+	runs := []*Run{
+		{ID: "run-1", Pipeline: "pipeline", Environment: "prod", Trigger: TriggerSchedule, StartDate: day(1), EndDate: day(2), StartedAt: day(2), Instances: succeeded},
+		{ID: "run-2", Pipeline: "pipeline", Environment: "prod", Trigger: TriggerSchedule, StartDate: day(3), EndDate: day(4), StartedAt: day(4), Instances: failed},
+		{ID: "run-3", Pipeline: "pipeline", Environment: "prod", Trigger: TriggerSchedule, StartDate: day(2), EndDate: day(3), StartedAt: day(3), Instances: failed},
+		{ID: "run-4", Pipeline: "pipeline", Environment: "prod", Trigger: TriggerSchedule, StartDate: day(4), EndDate: day(5), StartedAt: day(5), Instances: failed},
+		{ID: "run-5", Pipeline: "pipeline", Environment: "prod", Trigger: TriggerSchedule, StartDate: day(4), EndDate: day(5), StartedAt: day(6), Instances: succeeded},
+		{ID: "run-6", Pipeline: "pipeline", Environment: "prod", Trigger: TriggerSchedule, StartDate: day(6), EndDate: day(7), StartedAt: day(7), Instances: failed},
+		{ID: "run-7", Pipeline: "pipeline", Environment: "dev", Trigger: TriggerSchedule, StartDate: day(1), EndDate: day(2), StartedAt: day(2), Instances: failed},
+		{ID: "run-8", Pipeline: "pipeline", Environment: "prod", Trigger: TriggerManual, StartDate: day(1), EndDate: day(2), StartedAt: day(2), Instances: failed},
+	}
+	for _, run := range runs {
+		require.NoError(t, s.SaveRun(run))
+	}
+
+	got, err := s.GetFailedScheduledRuns("pipeline", "prod", day(5))
+	require.NoError(t, err)
+
+	ids := make([]string, 0, len(got))
+	for _, run := range got {
+		ids = append(ids, run.ID)
+	}
+	assert.Equal(t, []string{"run-3", "run-2"}, ids)
+}
+
+func TestNewInstances(t *testing.T) {
+	t.Parallel()
+
+	p := &pipeline.Pipeline{
+		Tasks: []*pipeline.Asset{
+			{Name: "task1"},
+			{Name: "task2", DependsOn: []string{"task1"}},
+		},
+	}
+
+	s := scheduler.NewScheduler(zap.NewNop().Sugar(), p)
+	instances := s.GetTaskInstances()
+	instances[0].MarkAs(scheduler.Failed)
+	instances[1].MarkAs(scheduler.UpstreamFailed)
+
+	now := time.Now()
+	records := NewInstances(instances, []*scheduler.TaskExecutionResult{
+		{
+			Instance:   instances[0],
+			Error:      errors.New("some error"),
+			Attempts:   2,
+			StartedAt:  now,
+			FinishedAt: now.Add(time.Second),
+		},
+	})
+
+	assert.Equal(t, []*Instance{
+		{
+			ID:         "task1",
+			Asset:      "task1",
+			Type:       "main",
+			Status:     scheduler.Failed,
+			StartedAt:  now,
+			FinishedAt: now.Add(time.Second),
+			Attempts:   2,
+			Error:      "some error",
+		},
+		{
+			ID:     "task2",
+			Asset:  "task2",
+			Type:   "main",
+			Status: scheduler.UpstreamFailed,
+		},
+	}, records)
+}
@@ -43,14 +43,46 @@ func GetRules(logger *zap.SugaredLogger, fs afero.Fs) ([]Rule, error) {
 			Identifier: "valid-task-schedule",
 			Validator:  EnsureTaskScheduleIsValid,
 		},
+		&SimpleRule{
+			Identifier: "valid-asset-metadata",
+			Validator:  EnsureAssetMetadataIsValid,
+		},
+		&SimpleRule{
+			Identifier: "valid-pools",
+			Validator:  EnsurePoolsAreValid,
+		},
+		&SimpleRule{
+			Identifier: "valid-column-checks",
+			Validator:  EnsureColumnChecksAreValid,
+		},
+		&SimpleRule{
+			Identifier: "valid-custom-checks",
+			Validator:  EnsureCustomChecksAreValid,
+		},
 		&SimpleRule{
 			Identifier: "valid-athena-sql-task",
 			Validator:  EnsureAthenaSQLTypeTasksHasDatabaseAndS3FilePath,
 		},
+		&SimpleRule{
+			Identifier: "valid-sensor-parameters",
+			Validator:  EnsureSensorParametersAreValid,
+		},
 		&SimpleRule{
 			Identifier: "valid-slack-notification",
 			Validator:  EnsureSlackFieldInPipelineIsValid,
 		},
+		&SimpleRule{
+			Identifier: "valid-webhook-notification",
+			Validator:  EnsureWebhookFieldInPipelineIsValid,
+		},
+		&SimpleRule{
+			Identifier: "valid-email-notification",
+			Validator:  EnsureEmailFieldInPipelineIsValid,
+		},
+		&SimpleRule{
+			Identifier: "valid-notify-on-failure",
+			Validator:  EnsureNotifyOnFailureIsValid,
+		},
 		&SimpleRule{
 			Identifier: "valid-start-date",
 			Validator:  EnsureStartDateIsValid,
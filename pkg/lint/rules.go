@@ -4,13 +4,15 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/datablast-analytics/blast/pkg/date"
 	"github.com/datablast-analytics/blast/pkg/executor"
 	"github.com/datablast-analytics/blast/pkg/pipeline"
+	"github.com/datablast-analytics/blast/pkg/sensor"
 	"github.com/pkg/errors"
-	"github.com/robfig/cron/v3"
 	"github.com/spf13/afero"
 	"github.com/yourbasic/graph"
 )
@@ -33,23 +35,59 @@ const (
 	pipelineStartDateCannotBeEmpty   = "The start_date in the pipeline.yml file cannot be empty, it must be a valid datetime in 'YYYY-MM-DD' format, e.g. 2021-01-01"
 	pipelineStartDateMustBeValidDate = "The start_date in the pipeline.yml file must be a valid datetime in 'YYYY-MM-DD' format, e.g. 2021-01-01"
 
+	pipelineMaxActiveTasksCannotBeNegative = "The `max_active_tasks` in the pipeline.yml file cannot be negative"
+	pipelinePoolSlotsMustBePositive        = "The concurrency pool limits in the pipeline.yml file must be positive"
+	assetPoolDoesNotExist                  = "The asset pool must be defined under `pools.custom` in the pipeline.yml file"
+
+	checkSeverityIsInvalid  = "The `severity` of a check must be either `warn` or `error`"
+	checkThresholdIsInvalid = "The `threshold` of a check must have either a non-negative `rows` or a `percent` between 0 and 100"
+
+	customCheckNameCannotBeEmpty     = "Custom checks must have a `name` attribute"
+	customCheckNameNotUnique         = "The `name` attribute of the custom checks must be unique within the asset"
+	customCheckQueryCannotBeEmpty    = "Custom checks must have a `query` attribute"
+	customCheckAssetTypeNotSupported = "Custom checks are only supported for the `bq.sql` and `sf.sql` assets"
+
 	pipelineContainsCycle = "The pipeline has a cycle with dependencies, make sure there are no cyclic dependencies"
 
 	taskScheduleDayDoesNotExist = "Asset schedule day must be a valid weekday"
 
+	assetTagIsInvalid     = "Asset tags cannot be empty or contain whitespaces or commas, otherwise they cannot be selected"
+	assetTagIsDuplicated  = "Asset tags must be unique"
+	assetMetaKeyIsInvalid = "Asset meta keys cannot be empty or contain whitespaces or colons, otherwise they cannot be selected"
+
 	pipelineSlackFieldEmptyName           = "Slack notifications must have a `name` attribute"
 	pipelineSlackFieldEmptyConnection     = "Slack notifications must have a `connection` attribute"
 	pipelineSlackNameFieldNotUnique       = "The `name` attribute under the Slack notifications must be unique"
 	pipelineSlackConnectionFieldNotUnique = "The `connection` attribute under the Slack notifications must be unique"
 
+	pipelineWebhookFieldEmptyName       = "Webhook notifications must have a `name` attribute"
+	pipelineWebhookFieldEmptyConnection = "Webhook notifications must have a `connection` attribute"
+	pipelineWebhookNameFieldNotUnique   = "The `name` attribute under the webhook notifications must be unique"
+
+	pipelineEmailFieldEmptyName       = "Email notifications must have a `name` attribute"
+	pipelineEmailFieldEmptyConnection = "Email notifications must have a `connection` attribute"
+	pipelineEmailFieldEmptyTo         = "Email notifications must have at least one recipient in the `to` attribute"
+	pipelineEmailNameFieldNotUnique   = "The `name` attribute under the email notifications must be unique"
+
+	assetNotifyOnFailureNotFound = "The `notify_on_failure` attribute must only contain the names of the notifications in the pipeline"
+
 	athenaSQLEmptyDatabaseField       = "The `database` parameter cannot be empty"
 	athenaSQLInvalidS3FilePath        = "The `s3_file_path` parameter must start with `s3://`"
 	athenaSQLMissingDatabaseParameter = "The `database` parameter is required for Athena SQL tasks"
 	athenaSQLEmptyS3FilePath          = "The `s3_file_path` parameter cannot be empty"
+
+	sensorParametersAreInvalid = "The `poke_interval`, `timeout` and `mode` parameters of the sensor are invalid"
+	sensorParameterIsMissing   = "The sensor is missing a required parameter"
 )
 
 var validIDRegexCompiled = regexp.MustCompile(validIDRegex)
 
+var requiredSensorParameters = map[pipeline.AssetType][]string{
+	executor.TaskTypeBigqueryTableSensor:     {"table"},
+	executor.TaskTypeBigqueryQuerySensor:     {"query"},
+	executor.TaskTypeBigqueryPartitionSensor: {"table", "partition"},
+}
+
 func EnsureTaskNameIsValid(pipeline *pipeline.Pipeline) ([]*Issue, error) {
 	issues := make([]*Issue, 0)
 
@@ -237,12 +275,7 @@ func EnsurePipelineScheduleIsValidCron(p *pipeline.Pipeline) ([]*Issue, error) {
 		return issues, nil
 	}
 
-	schedule := p.Schedule
-	if schedule == "daily" || schedule == "hourly" || schedule == "weekly" || schedule == "monthly" {
-		schedule = "@" + schedule
-	}
-
-	_, err := cron.ParseStandard(string(schedule))
+	_, err := date.ParseSchedule(string(p.Schedule))
 	if err != nil {
 		issues = append(issues, &Issue{
 			Description: fmt.Sprintf("Invalid cron schedule '%s'", p.Schedule),
@@ -364,6 +397,209 @@ func EnsureTaskScheduleIsValid(p *pipeline.Pipeline) ([]*Issue, error) {
 	return issues, nil
 }
 
+func EnsureAssetMetadataIsValid(p *pipeline.Pipeline) ([]*Issue, error) {
+	issues := make([]*Issue, 0)
+	for _, task := range p.Tasks {
+		invalidTags := make([]string, 0)
+		duplicateTags := make([]string, 0)
+		seenTags := make(map[string]bool, len(task.Tags))
+		for _, tag := range task.Tags {
+			if tag == "" || strings.ContainsAny(tag, " \t,") {
+				invalidTags = append(invalidTags, fmt.Sprintf("Given tag: '%s'", tag))
+				continue
+			}
+
+			if seenTags[tag] {
+				duplicateTags = append(duplicateTags, fmt.Sprintf("Duplicate tag: '%s'", tag))
+			}
+			seenTags[tag] = true
+		}
+
+		invalidKeys := make([]string, 0)
+		for key := range task.Meta {
+			if key == "" || strings.ContainsAny(key, " \t:") {
+				invalidKeys = append(invalidKeys, fmt.Sprintf("Given key: '%s'", key))
+			}
+		}
+		sort.Strings(invalidKeys)
+
+		if len(invalidTags) > 0 {
+			issues = append(issues, &Issue{
+				Task:        task,
+				Description: assetTagIsInvalid,
+				Context:     invalidTags,
+			})
+		}
+
+		if len(duplicateTags) > 0 {
+			issues = append(issues, &Issue{
+				Task:        task,
+				Description: assetTagIsDuplicated,
+				Context:     duplicateTags,
+			})
+		}
+
+		if len(invalidKeys) > 0 {
+			issues = append(issues, &Issue{
+				Task:        task,
+				Description: assetMetaKeyIsInvalid,
+				Context:     invalidKeys,
+			})
+		}
+	}
+
+	return issues, nil
+}
+
+func EnsurePoolsAreValid(p *pipeline.Pipeline) ([]*Issue, error) {
+	issues := make([]*Issue, 0)
+	if p.MaxActiveTasks < 0 {
+		issues = append(issues, &Issue{
+			Description: pipelineMaxActiveTasksCannotBeNegative,
+		})
+	}
+
+	invalidPools := p.Pools.Invalid()
+	if len(invalidPools) > 0 {
+		for i, pool := range invalidPools {
+			invalidPools[i] = "Given pool: " + pool
+		}
+
+		issues = append(issues, &Issue{
+			Description: pipelinePoolSlotsMustBePositive,
+			Context:     invalidPools,
+		})
+	}
+
+	for _, task := range p.Tasks {
+		if task.Pool == "" {
+			continue
+		}
+
+		if _, ok := p.Pools.Custom[task.Pool]; !ok {
+			issues = append(issues, &Issue{
+				Task:        task,
+				Description: assetPoolDoesNotExist,
+				Context:     []string{fmt.Sprintf("Given pool: %s", task.Pool)},
+			})
+		}
+	}
+
+	return issues, nil
+}
+
+func EnsureCustomChecksAreValid(p *pipeline.Pipeline) ([]*Issue, error) {
+	issues := make([]*Issue, 0)
+	for _, task := range p.Tasks {
+		if len(task.CustomChecks) == 0 {
+			continue
+		}
+
+		if task.Type != executor.TaskTypeBigqueryQuery && task.Type != executor.TaskTypeSnowflakeQuery {
+			issues = append(issues, &Issue{
+				Task:        task,
+				Description: customCheckAssetTypeNotSupported,
+				Context:     []string{fmt.Sprintf("Given type: %s", task.Type)},
+			})
+		}
+
+		seenNames := make(map[string]bool, len(task.CustomChecks))
+		for i, check := range task.CustomChecks {
+			if check.Name == "" {
+				issues = append(issues, &Issue{
+					Task:        task,
+					Description: customCheckNameCannotBeEmpty,
+					Context:     []string{fmt.Sprintf("Custom check at index %d", i)},
+				})
+			} else if seenNames[check.Name] {
+				issues = append(issues, &Issue{
+					Task:        task,
+					Description: customCheckNameNotUnique,
+					Context:     []string{fmt.Sprintf("Duplicate name: '%s'", check.Name)},
+				})
+			}
+			seenNames[check.Name] = true
+
+			if strings.TrimSpace(check.Query) == "" {
+				issues = append(issues, &Issue{
+					Task:        task,
+					Description: customCheckQueryCannotBeEmpty,
+					Context:     []string{fmt.Sprintf("Custom check at index %d", i)},
+				})
+			}
+
+			if !isValidCheckSeverity(check.Severity) {
+				issues = append(issues, &Issue{
+					Task:        task,
+					Description: checkSeverityIsInvalid,
+					Context:     []string{fmt.Sprintf("Custom check '%s' has the severity '%s'", check.Name, check.Severity)},
+				})
+			}
+		}
+	}
+
+	return issues, nil
+}
+
+func EnsureColumnChecksAreValid(p *pipeline.Pipeline) ([]*Issue, error) {
+	issues := make([]*Issue, 0)
+	for _, task := range p.Tasks {
+		invalidSeverities := make([]string, 0)
+		invalidThresholds := make([]string, 0)
+		for _, column := range task.Columns {
+			for _, check := range column.Checks {
+				if !isValidCheckSeverity(check.Severity) {
+					invalidSeverities = append(invalidSeverities, fmt.Sprintf("Check '%s' of the column '%s' has the severity '%s'", check.Name, column.Name, check.Severity))
+				}
+
+				if !isValidCheckThreshold(check.Threshold) {
+					invalidThresholds = append(invalidThresholds, fmt.Sprintf("Check '%s' of the column '%s'", check.Name, column.Name))
+				}
+			}
+		}
+
+		if len(invalidSeverities) > 0 {
+			sort.Strings(invalidSeverities)
+			issues = append(issues, &Issue{
+				Task:        task,
+				Description: checkSeverityIsInvalid,
+				Context:     invalidSeverities,
+			})
+		}
+
+		if len(invalidThresholds) > 0 {
+			sort.Strings(invalidThresholds)
+			issues = append(issues, &Issue{
+				Task:        task,
+				Description: checkThresholdIsInvalid,
+				Context:     invalidThresholds,
+			})
+		}
+	}
+
+	return issues, nil
+}
+
+func isValidCheckSeverity(severity pipeline.CheckSeverity) bool {
+	return severity == "" || severity == pipeline.CheckSeverityError || severity == pipeline.CheckSeverityWarn
+}
+
+func isValidCheckThreshold(threshold pipeline.CheckThreshold) bool {
+	if threshold.Rows != nil && threshold.Percent != nil {
+		return false
+	}
+
+	if threshold.Rows != nil && *threshold.Rows < 0 {
+		return false
+	}
+
+	if threshold.Percent != nil && (*threshold.Percent < 0 || *threshold.Percent > 100) {
+		return false
+	}
+
+	return true
+}
+
 func isStringInArray(arr []string, str string) bool {
 	for _, a := range arr {
 		if str == a {
@@ -454,3 +690,120 @@ func EnsureSlackFieldInPipelineIsValid(p *pipeline.Pipeline) ([]*Issue, error) {
 
 	return issues, nil
 }
+
+func EnsureWebhookFieldInPipelineIsValid(p *pipeline.Pipeline) ([]*Issue, error) {
+	issues := make([]*Issue, 0)
+
+	webhookNames := make([]string, 0, len(p.Notifications.Webhook))
+	for _, webhook := range p.Notifications.Webhook {
+		if webhook.Name == "" {
+			issues = append(issues, &Issue{
+				Description: pipelineWebhookFieldEmptyName,
+			})
+		}
+
+		if webhook.Connection == "" {
+			issues = append(issues, &Issue{
+				Description: pipelineWebhookFieldEmptyConnection,
+			})
+		}
+
+		if isStringInArray(webhookNames, webhook.Name) {
+			issues = append(issues, &Issue{
+				Description: pipelineWebhookNameFieldNotUnique,
+			})
+		}
+		if webhook.Name != "" {
+			webhookNames = append(webhookNames, webhook.Name)
+		}
+	}
+
+	return issues, nil
+}
+
+func EnsureEmailFieldInPipelineIsValid(p *pipeline.Pipeline) ([]*Issue, error) {
+	issues := make([]*Issue, 0)
+
+	emailNames := make([]string, 0, len(p.Notifications.Email))
+	for _, email := range p.Notifications.Email {
+		if email.Name == "" {
+			issues = append(issues, &Issue{
+				Description: pipelineEmailFieldEmptyName,
+			})
+		}
+
+		if email.Connection == "" {
+			issues = append(issues, &Issue{
+				Description: pipelineEmailFieldEmptyConnection,
+			})
+		}
+
+		if len(email.To) == 0 {
+			issues = append(issues, &Issue{
+				Description: pipelineEmailFieldEmptyTo,
+			})
+		}
+
+		if isStringInArray(emailNames, email.Name) {
+			issues = append(issues, &Issue{
+				Description: pipelineEmailNameFieldNotUnique,
+			})
+		}
+		if email.Name != "" {
+			emailNames = append(emailNames, email.Name)
+		}
+	}
+
+	return issues, nil
+}
+
+func EnsureNotifyOnFailureIsValid(p *pipeline.Pipeline) ([]*Issue, error) {
+	issues := make([]*Issue, 0)
+
+	notificationNames := p.Notifications.Names()
+	for _, task := range p.Tasks {
+		for _, name := range task.NotifyOnFailure {
+			if !isStringInArray(notificationNames, name) {
+				issues = append(issues, &Issue{
+					Task:        task,
+					Description: assetNotifyOnFailureNotFound,
+					Context:     []string{fmt.Sprintf("Notification '%s' is not found", name)},
+				})
+			}
+		}
+	}
+
+	return issues, nil
+}
+
+func EnsureSensorParametersAreValid(p *pipeline.Pipeline) ([]*Issue, error) {
+	issues := make([]*Issue, 0)
+
+	for _, task := range p.Tasks {
+		required, ok := requiredSensorParameters[task.Type]
+		if !ok {
+			continue
+		}
+
+		_, err := sensor.ParseConfig(task.Parameters)
+		if err != nil {
+			issues = append(issues, &Issue{
+				Task:        task,
+				Description: sensorParametersAreInvalid,
+				Context:     []string{err.Error()},
+			})
+		}
+
+		for _, param := range required {
+			if task.Parameters[param] == "" {
+				issues = append(issues, &Issue{
+					Task:        task,
+					Description: sensorParameterIsMissing,
+					Context:     []string{fmt.Sprintf("The `%s` parameter is required for the `%s` assets", param, task.Type)},
+				})
+			}
+		}
+	}
+
+	return issues, nil
+}
@@ -1000,6 +1000,149 @@ func TestEnsureTaskScheduleIsValid(t *testing.T) {
 	}
 }
 
+func TestEnsureAssetMetadataIsValid(t *testing.T) {
+	t.Parallel()
+
+	validAsset := &pipeline.Asset{
+		Name:   "task1",
+		Tags:   []string{"finance", "daily"},
+		Owner:  "someone@example.com",
+		Domain: "finance",
+		Meta:   map[string]string{"sla": "6h", "tier": "gold"},
+	}
+	invalidAsset := &pipeline.Asset{
+		Name: "task2",
+		Tags: []string{"finance", "", "two words", "a,b", "finance"},
+		Meta: map[string]string{"sla": "6h", "": "empty", "some key": "value", "a:b": "c"},
+	}
+
+	tests := []struct {
+		name string
+		p    *pipeline.Pipeline
+		want []*Issue
+	}{
+		{
+			name: "assets without metadata",
+			p: &pipeline.Pipeline{
+				Tasks: []*pipeline.Asset{{Name: "task1"}},
+			},
+			want: noIssues,
+		},
+		{
+			name: "valid metadata",
+			p: &pipeline.Pipeline{
+				Tasks: []*pipeline.Asset{validAsset},
+			},
+			want: noIssues,
+		},
+		{
+			name: "invalid tags and meta keys are caught",
+			p: &pipeline.Pipeline{
+				Tasks: []*pipeline.Asset{validAsset, invalidAsset},
+			},
+			want: []*Issue{
+				{
+					Task:        invalidAsset,
+					Description: assetTagIsInvalid,
+					Context:     []string{"Given tag: ''", "Given tag: 'two words'", "Given tag: 'a,b'"},
+				},
+				{
+					Task:        invalidAsset,
+					Description: assetTagIsDuplicated,
+					Context:     []string{"Duplicate tag: 'finance'"},
+				},
+				{
+					Task:        invalidAsset,
+					Description: assetMetaKeyIsInvalid,
+					Context:     []string{"Given key: ''", "Given key: 'a:b'", "Given key: 'some key'"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := EnsureAssetMetadataIsValid(tt.p)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEnsurePoolsAreValid(t *testing.T) {
+	t.Parallel()
+
+	assetWithPool := &pipeline.Asset{Name: "task1", Pool: "heavy"}
+	assetWithMissingPool := &pipeline.Asset{Name: "task2", Pool: "missing"}
+
+	tests := []struct {
+		name string
+		p    *pipeline.Pipeline
+		want []*Issue
+	}{
+		{
+			name: "no pools",
+			p: &pipeline.Pipeline{
+				Tasks: []*pipeline.Asset{{Name: "task1"}},
+			},
+			want: noIssues,
+		},
+		{
+			name: "valid pools",
+			p: &pipeline.Pipeline{
+				MaxActiveTasks: 4,
+				Pools: pipeline.Pools{
+					Connections: map[string]int{"gcp": 2},
+					AssetTypes:  map[string]int{"python": 1},
+					Custom:      map[string]int{"heavy": 1},
+				},
+				Tasks: []*pipeline.Asset{assetWithPool},
+			},
+			want: noIssues,
+		},
+		{
+			name: "invalid limits and missing pools are caught",
+			p: &pipeline.Pipeline{
+				MaxActiveTasks: -1,
+				Pools: pipeline.Pools{
+					Connections: map[string]int{"gcp": 0},
+					AssetTypes:  map[string]int{"python": -2},
+					Custom:      map[string]int{"heavy": 1},
+				},
+				Tasks: []*pipeline.Asset{assetWithPool, assetWithMissingPool},
+			},
+			want: []*Issue{
+				{
+					Description: pipelineMaxActiveTasksCannotBeNegative,
+				},
+				{
+					Description: pipelinePoolSlotsMustBePositive,
+					Context:     []string{"Given pool: asset_types.python = -2", "Given pool: connections.gcp = 0"},
+				},
+				{
+					Task:        assetWithMissingPool,
+					Description: assetPoolDoesNotExist,
+					Context:     []string{"Given pool: missing"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := EnsurePoolsAreValid(tt.p)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestEnsureAthenaSQLTypeTasksHasDatabaseAndS3FilePath(t *testing.T) {
 	t.Parallel()
 	type args struct {
@@ -1534,3 +1677,341 @@ func TestEnsureStartDateIsValid(t *testing.T) {
 		})
 	}
 }
+
+func TestEnsureCustomChecksAreValid(t *testing.T) {
+	t.Parallel()
+
+	validAsset := &pipeline.Asset{
+		Name: "task1",
+		Type: executor.TaskTypeBigqueryQuery,
+		CustomChecks: []pipeline.CustomCheck{
+			{Name: "has_rows", Query: "SELECT count(*) > 0 FROM task1", Value: 1},
+			{Name: "no_negative_amounts", Query: "SELECT count(*) FROM task1 WHERE amount < 0"},
+		},
+	}
+	invalidAsset := &pipeline.Asset{
+		Name: "task2",
+		Type: executor.TaskTypePython,
+		CustomChecks: []pipeline.CustomCheck{
+			{Name: "", Query: "SELECT 1"},
+			{Name: "has_rows", Query: " "},
+			{Name: "has_rows", Query: "SELECT 1", Severity: "fatal"},
+		},
+	}
+
+	tests := []struct {
+		name string
+		p    *pipeline.Pipeline
+		want []*Issue
+	}{
+		{
+			name: "no custom checks",
+			p: &pipeline.Pipeline{
+				Tasks: []*pipeline.Asset{{Name: "task1", Type: executor.TaskTypePython}},
+			},
+			want: noIssues,
+		},
+		{
+			name: "valid custom checks",
+			p: &pipeline.Pipeline{
+				Tasks: []*pipeline.Asset{validAsset},
+			},
+			want: noIssues,
+		},
+		{
+			name: "invalid custom checks are caught",
+			p: &pipeline.Pipeline{
+				Tasks: []*pipeline.Asset{validAsset, invalidAsset},
+			},
+			want: []*Issue{
+				{
+					Task:        invalidAsset,
+					Description: customCheckAssetTypeNotSupported,
+					Context:     []string{"Given type: python"},
+				},
+				{
+					Task:        invalidAsset,
+					Description: customCheckNameCannotBeEmpty,
+					Context:     []string{"Custom check at index 0"},
+				},
+				{
+					Task:        invalidAsset,
+					Description: customCheckQueryCannotBeEmpty,
+					Context:     []string{"Custom check at index 1"},
+				},
+				{
+					Task:        invalidAsset,
+					Description: customCheckNameNotUnique,
+					Context:     []string{"Duplicate name: 'has_rows'"},
+				},
+				{
+					Task:        invalidAsset,
+					Description: checkSeverityIsInvalid,
+					Context:     []string{"Custom check 'has_rows' has the severity 'fatal'"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := EnsureCustomChecksAreValid(tt.p)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEnsureColumnChecksAreValid(t *testing.T) {
+	t.Parallel()
+
+	rows := int64(10)
+	negativeRows := int64(-1)
+	percent := 2.5
+	tooHighPercent := 120.0
+
+	validAsset := &pipeline.Asset{
+		Name: "task1",
+		Columns: map[string]pipeline.Column{
+			"id": {
+				Name: "id",
+				Checks: []pipeline.ColumnCheck{
+					{Name: "not_null"},
+					{Name: "unique", Severity: pipeline.CheckSeverityError, Threshold: pipeline.CheckThreshold{Rows: &rows}},
+					{Name: "positive", Severity: pipeline.CheckSeverityWarn, Threshold: pipeline.CheckThreshold{Percent: &percent}},
+				},
+			},
+		},
+	}
+	invalidAsset := &pipeline.Asset{
+		Name: "task2",
+		Columns: map[string]pipeline.Column{
+			"id": {
+				Name: "id",
+				Checks: []pipeline.ColumnCheck{
+					{Name: "not_null", Severity: "fatal"},
+					{Name: "unique", Threshold: pipeline.CheckThreshold{Rows: &negativeRows}},
+				},
+			},
+			"amount": {
+				Name: "amount",
+				Checks: []pipeline.ColumnCheck{
+					{Name: "positive", Threshold: pipeline.CheckThreshold{Percent: &tooHighPercent}},
+					{Name: "not_null", Threshold: pipeline.CheckThreshold{Rows: &rows, Percent: &percent}},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name string
+		p    *pipeline.Pipeline
+		want []*Issue
+	}{
+		{
+			name: "valid column checks",
+			p: &pipeline.Pipeline{
+				Tasks: []*pipeline.Asset{validAsset},
+			},
+			want: noIssues,
+		},
+		{
+			name: "invalid severities and thresholds are caught",
+			p: &pipeline.Pipeline{
+				Tasks: []*pipeline.Asset{validAsset, invalidAsset},
+			},
+			want: []*Issue{
+				{
+					Task:        invalidAsset,
+					Description: checkSeverityIsInvalid,
+					Context:     []string{"Check 'not_null' of the column 'id' has the severity 'fatal'"},
+				},
+				{
+					Task:        invalidAsset,
+					Description: checkThresholdIsInvalid,
+					Context: []string{
+						"Check 'not_null' of the column 'amount'",
+						"Check 'positive' of the column 'amount'",
+						"Check 'unique' of the column 'id'",
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := EnsureColumnChecksAreValid(tt.p)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEnsureWebhookFieldInPipelineIsValid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		webhooks []pipeline.WebhookNotification
+		want     []*Issue
+	}{
+		{
+			name:     "no issues",
+			webhooks: []pipeline.WebhookNotification{{Name: "name", Connection: "connect"}},
+			want:     noIssues,
+		},
+		{
+			name:     "empty name and connection fields",
+			webhooks: []pipeline.WebhookNotification{{}},
+			want: []*Issue{
+				{Description: pipelineWebhookFieldEmptyName},
+				{Description: pipelineWebhookFieldEmptyConnection},
+			},
+		},
+		{
+			name: "duplicate names",
+			webhooks: []pipeline.WebhookNotification{
+				{Name: "name", Connection: "connect"},
+				{Name: "name", Connection: "connect"},
+			},
+			want: []*Issue{
+				{Description: pipelineWebhookNameFieldNotUnique},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := EnsureWebhookFieldInPipelineIsValid(&pipeline.Pipeline{
+				Notifications: pipeline.Notifications{Webhook: tt.webhooks},
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEnsureEmailFieldInPipelineIsValid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		emails []pipeline.EmailNotification
+		want   []*Issue
+	}{
+		{
+			name:   "no issues",
+			emails: []pipeline.EmailNotification{{Name: "name", Connection: "connect", To: []string{"team@example.com"}}},
+			want:   noIssues,
+		},
+		{
+			name:   "empty name, connection and recipient fields",
+			emails: []pipeline.EmailNotification{{}},
+			want: []*Issue{
+				{Description: pipelineEmailFieldEmptyName},
+				{Description: pipelineEmailFieldEmptyConnection},
+				{Description: pipelineEmailFieldEmptyTo},
+			},
+		},
+		{
+			name: "duplicate names",
+			emails: []pipeline.EmailNotification{
+				{Name: "name", Connection: "connect", To: []string{"team@example.com"}},
+				{Name: "name", Connection: "connect", To: []string{"oncall@example.com"}},
+			},
+			want: []*Issue{
+				{Description: pipelineEmailNameFieldNotUnique},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := EnsureEmailFieldInPipelineIsValid(&pipeline.Pipeline{
+				Notifications: pipeline.Notifications{Email: tt.emails},
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEnsureNotifyOnFailureIsValid(t *testing.T) {
+	t.Parallel()
+
+	validAsset := &pipeline.Asset{Name: "task1", NotifyOnFailure: []string{"alerts", "team"}}
+	silentAsset := &pipeline.Asset{Name: "task2", NotifyOnFailure: []string{}}
+	invalidAsset := &pipeline.Asset{Name: "task3", NotifyOnFailure: []string{"alerts", "missing"}}
+
+	p := &pipeline.Pipeline{
+		Notifications: pipeline.Notifications{
+			Slack: []pipeline.SlackNotification{{Name: "alerts", Connection: "slack"}},
+			Email: []pipeline.EmailNotification{{Name: "team", Connection: "mail", To: []string{"team@example.com"}}},
+		},
+		Tasks: []*pipeline.Asset{validAsset, silentAsset, invalidAsset},
+	}
+
+	got, err := EnsureNotifyOnFailureIsValid(p)
+	assert.NoError(t, err)
+	assert.Equal(t, []*Issue{
+		{
+			Task:        invalidAsset,
+			Description: assetNotifyOnFailureNotFound,
+			Context:     []string{"Notification 'missing' is not found"},
+		},
+	}, got)
+}
+
+func TestEnsureSensorParametersAreValid(t *testing.T) {
+	t.Parallel()
+
+	validSensor := &pipeline.Asset{
+		Name:       "task1",
+		Type:       executor.TaskTypeBigqueryPartitionSensor,
+		Parameters: map[string]string{"table": "dataset.events", "partition": "{{ end_date_nodash }}", "poke_interval": "5m", "mode": "poke"},
+	}
+	invalidConfig := &pipeline.Asset{
+		Name:       "task2",
+		Type:       executor.TaskTypeBigqueryTableSensor,
+		Parameters: map[string]string{"table": "dataset.events", "timeout": "forever"},
+	}
+	missingQuery := &pipeline.Asset{
+		Name: "task3",
+		Type: executor.TaskTypeBigqueryQuerySensor,
+	}
+	otherAsset := &pipeline.Asset{
+		Name:       "task4",
+		Type:       executor.TaskTypeBigqueryQuery,
+		Parameters: map[string]string{"timeout": "forever"},
+	}
+
+	got, err := EnsureSensorParametersAreValid(&pipeline.Pipeline{
+		Tasks: []*pipeline.Asset{validSensor, invalidConfig, missingQuery, otherAsset},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []*Issue{
+		{
+			Task:        invalidConfig,
+			Description: sensorParametersAreInvalid,
+			Context:     []string{"invalid `timeout` 'forever', it must be a positive duration such as '1h'"},
+		},
+		{
+			Task:        missingQuery,
+			Description: sensorParameterIsMissing,
+			Context:     []string{"The `query` parameter is required for the `bq.sensor.query` assets"},
+		},
+	}, got)
+}
@@ -0,0 +1,163 @@
+package notification
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/datablast-analytics/blast/pkg/config"
+	"github.com/datablast-analytics/blast/pkg/pipeline"
+	"github.com/datablast-analytics/blast/pkg/report"
+	"github.com/pkg/errors"
+)
+
+// Email sends the outcome of the runs as a plain text email through an SMTP server.
+type Email struct {
+	connection   config.SMTPConnection
+	notification pipeline.EmailNotification
+	timeout      time.Duration
+}
+
+func NewEmail(connection config.SMTPConnection, notification pipeline.EmailNotification) *Email {
+	return &Email{
+		connection:   connection,
+		notification: notification,
+		timeout:      requestTimeout,
+	}
+}
+
+func (e *Email) Notify(ctx context.Context, run *report.Run) error {
+	body := defaultMessage(run)
+	if template := messageTemplate(run, e.notification.Success, e.notification.Failure); template != "" {
+		var err error
+		body, err = renderTemplate(template, run)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := e.send(ctx, e.message(run, body))
+	return errors.Wrapf(err, "failed to send the email '%s'", e.notification.Name)
+}
+
+// send delivers the message the same way as smtp.SendMail does, but the whole conversation with the server is bound
+// to the context and the timeout so that an unresponsive server cannot block the run.
+func (e *Email) send(ctx context.Context, msg []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(e.connection.Host, strconv.Itoa(e.connection.Port)))
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to the SMTP server")
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	err = conn.SetDeadline(deadline)
+	if err != nil {
+		return errors.Wrap(err, "failed to set the deadline of the SMTP connection")
+	}
+
+	// the deadline does not cover the cancellation of the context, the connection is interrupted explicitly
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.SetDeadline(time.Now())
+		case <-done:
+		}
+	}()
+
+	client, err := smtp.NewClient(conn, e.connection.Host)
+	if err != nil {
+		return contextError(ctx, err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		err = client.StartTLS(&tls.Config{ServerName: e.connection.Host, MinVersion: tls.VersionTLS12})
+		if err != nil {
+			return contextError(ctx, err)
+		}
+	}
+
+	if e.connection.Username != "" {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("the SMTP server does not support authentication")
+		}
+
+		err = client.Auth(smtp.PlainAuth("", e.connection.Username, e.connection.Password, e.connection.Host))
+		if err != nil {
+			return contextError(ctx, err)
+		}
+	}
+
+	err = client.Mail(e.connection.From)
+	if err != nil {
+		return contextError(ctx, err)
+	}
+
+	for _, to := range e.notification.To {
+		err = client.Rcpt(to)
+		if err != nil {
+			return contextError(ctx, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return contextError(ctx, err)
+	}
+
+	_, err = w.Write(msg)
+	if err != nil {
+		return contextError(ctx, err)
+	}
+
+	err = w.Close()
+	if err != nil {
+		return contextError(ctx, err)
+	}
+
+	return contextError(ctx, client.Quit())
+}
+
+// contextError reports the cancellation or the timeout instead of the I/O error it has caused.
+func contextError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	// the deadline of the connection is the one of the context, it may pass right before the context notices it
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return context.DeadlineExceeded
+	}
+
+	return err
+}
+
+func (e *Email) message(run *report.Run, body string) []byte {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", e.connection.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(e.notification.To, ", "))
+	fmt.Fprintf(&msg, "Subject: [blast] Pipeline %s %s\r\n", run.Pipeline, outcome(run))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	msg.WriteString("\r\n")
+
+	return []byte(msg.String())
+}
@@ -0,0 +1,223 @@
+package notification
+
+import (
+	"context"
+	"encoding/base64"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/datablast-analytics/blast/pkg/config"
+	"github.com/datablast-analytics/blast/pkg/pipeline"
+	"github.com/datablast-analytics/blast/pkg/report"
+	"github.com/datablast-analytics/blast/pkg/scheduler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTPServer accepts a single connection and records the conversation, it rejects the senders in rejectFrom.
+type fakeSMTPServer struct {
+	listener   net.Listener
+	rejectFrom string
+	silent     bool
+
+	lock       sync.Mutex
+	auth       string
+	from       string
+	recipients []string
+	data       string
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	return &fakeSMTPServer{listener: listener}
+}
+
+func (s *fakeSMTPServer) start() {
+	go func() {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		// a silent server accepts the connection but never greets the client
+		if s.silent {
+			_, _ = conn.Read(make([]byte, 1))
+			return
+		}
+
+		tp := textproto.NewConn(conn)
+		_ = tp.PrintfLine("220 localhost ESMTP")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+
+			command, args, _ := strings.Cut(line, " ")
+			s.lock.Lock()
+			switch strings.ToUpper(command) {
+			case "EHLO":
+				_ = tp.PrintfLine("250-localhost")
+				_ = tp.PrintfLine("250 AUTH PLAIN")
+			case "AUTH":
+				s.auth = args
+				_ = tp.PrintfLine("235 Authentication successful")
+			case "MAIL":
+				s.from = args
+				if s.rejectFrom != "" && strings.Contains(args, s.rejectFrom) {
+					_ = tp.PrintfLine("550 Sender rejected")
+				} else {
+					_ = tp.PrintfLine("250 OK")
+				}
+			case "RCPT":
+				s.recipients = append(s.recipients, args)
+				_ = tp.PrintfLine("250 OK")
+			case "DATA":
+				_ = tp.PrintfLine("354 Go ahead")
+				data, _ := tp.ReadDotBytes()
+				s.data = string(data)
+				_ = tp.PrintfLine("250 OK")
+			case "QUIT":
+				_ = tp.PrintfLine("221 Bye")
+				s.lock.Unlock()
+				return
+			default:
+				_ = tp.PrintfLine("502 Unknown command")
+			}
+			s.lock.Unlock()
+		}
+	}()
+}
+
+func (s *fakeSMTPServer) connection() config.SMTPConnection {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	return config.SMTPConnection{
+		Name:     "mail",
+		Host:     "127.0.0.1",
+		Port:     addr.Port,
+		Username: "blast",
+		Password: "secret",
+		From:     "blast@example.com",
+	}
+}
+
+func TestEmail_Notify(t *testing.T) {
+	t.Parallel()
+
+	run := &report.Run{
+		ID:              "run-1",
+		Pipeline:        "dashboard",
+		StartDate:       time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:         time.Date(2023, 3, 2, 0, 0, 0, 0, time.UTC),
+		DurationSeconds: 3,
+		Status:          report.StatusFailed,
+		Instances: []*report.Instance{
+			{ID: "task1", Asset: "task1", Status: scheduler.Failed, Error: "query failed"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		notification pipeline.EmailNotification
+		rejectFrom   string
+		wantBody     string
+		wantErr      bool
+	}{
+		{
+			name:         "default message",
+			notification: pipeline.EmailNotification{Name: "team", Connection: "mail", To: []string{"a@example.com", "b@example.com"}},
+			wantBody: "Pipeline `dashboard` has failed for the interval 2023-03-01 00:00:00 - 2023-03-02 00:00:00 in 3s.\nRun ID: run-1\n\n" +
+				"*Failed assets:*\n• `task1`: query failed\n",
+		},
+		{
+			name: "failure template",
+			notification: pipeline.EmailNotification{
+				Name:       "team",
+				Connection: "mail",
+				To:         []string{"a@example.com", "b@example.com"},
+				Failure:    "{{ pipeline }} failed in run {{ run_id }}",
+			},
+			wantBody: "dashboard failed in run run-1\n",
+		},
+		{
+			name:         "errors from the server are returned",
+			notification: pipeline.EmailNotification{Name: "team", Connection: "mail", To: []string{"a@example.com", "b@example.com"}},
+			rejectFrom:   "blast@example.com",
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := newFakeSMTPServer(t)
+			server.rejectFrom = tt.rejectFrom
+			server.start()
+
+			err := NewEmail(server.connection(), tt.notification).Notify(context.Background(), run)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "Sender rejected")
+				return
+			}
+
+			require.NoError(t, err)
+
+			server.lock.Lock()
+			defer server.lock.Unlock()
+
+			credentials, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(server.auth, "PLAIN "))
+			require.NoError(t, err)
+			assert.Equal(t, "\x00blast\x00secret", string(credentials))
+			assert.Equal(t, "FROM:<blast@example.com>", server.from)
+			assert.Equal(t, []string{"TO:<a@example.com>", "TO:<b@example.com>"}, server.recipients)
+
+			headers := "From: blast@example.com\n" +
+				"To: a@example.com, b@example.com\n" +
+				"Subject: [blast] Pipeline dashboard has failed\n" +
+				"MIME-Version: 1.0\n" +
+				"Content-Type: text/plain; charset=UTF-8\n\n"
+			assert.Equal(t, headers+tt.wantBody, server.data)
+		})
+	}
+}
+
+func TestEmail_NotifyRespectsTheContext(t *testing.T) {
+	t.Parallel()
+
+	server := newFakeSMTPServer(t)
+	server.silent = true
+	server.start()
+
+	notification := pipeline.EmailNotification{Name: "team", Connection: "mail", To: []string{"a@example.com"}}
+	run := &report.Run{Pipeline: "dashboard", Status: report.StatusSucceeded}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	err := NewEmail(server.connection(), notification).Notify(ctx, run)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	// the timeout applies even if the context is never cancelled
+	server = newFakeSMTPServer(t)
+	server.silent = true
+	server.start()
+
+	e := NewEmail(server.connection(), notification)
+	e.timeout = 50 * time.Millisecond
+	err = e.Notify(context.Background(), run)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "failed to send the email 'team'")
+}
@@ -0,0 +1,227 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/datablast-analytics/blast/pkg/report"
+	"github.com/datablast-analytics/blast/pkg/scheduler"
+	"github.com/noirbizarre/gonja"
+	"github.com/pkg/errors"
+)
+
+const (
+	timeFormat = "2006-01-02 15:04:05"
+
+	// maxErrorLength is the number of characters of an error that are included in a message, the full errors are
+	// available in the logs and the reports.
+	maxErrorLength = 300
+)
+
+// Notifier sends the outcome of a finished run to an external channel.
+type Notifier interface {
+	Notify(ctx context.Context, run *report.Run) error
+}
+
+// templateContext returns the variables the custom message templates can use. The failures of a backfill are collected
+// from all of its intervals.
+func templateContext(run *report.Run) gonja.Context {
+	failed := make([]map[string]any, 0)
+	upstreamFailed := make([]map[string]any, 0)
+	for _, r := range runsOf(run) {
+		for _, instance := range r.Instances {
+			switch instance.Status { //nolint:exhaustive
+			case scheduler.Failed:
+				failed = append(failed, map[string]any{
+					"id":     instance.ID,
+					"asset":  instance.Asset,
+					"error":  errorSnippet(instance.Error),
+					"run_id": r.ID,
+				})
+			case scheduler.UpstreamFailed:
+				upstreamFailed = append(upstreamFailed, map[string]any{
+					"id":               instance.ID,
+					"asset":            instance.Asset,
+					"failed_upstreams": instance.FailedUpstreams,
+					"run_id":           r.ID,
+				})
+			}
+		}
+	}
+
+	intervals := make([]map[string]any, 0, len(run.Intervals))
+	for _, interval := range run.Intervals {
+		intervals = append(intervals, map[string]any{
+			"run_id":     interval.ID,
+			"status":     interval.Status,
+			"start_date": interval.StartDate.Format(timeFormat),
+			"end_date":   interval.EndDate.Format(timeFormat),
+			"duration":   duration(interval).String(),
+		})
+	}
+
+	return gonja.Context{
+		"pipeline":        run.Pipeline,
+		"run_id":          run.ID,
+		"status":          run.Status,
+		"start_date":      run.StartDate.Format(timeFormat),
+		"end_date":        run.EndDate.Format(timeFormat),
+		"duration":        duration(run).String(),
+		"failed":          failed,
+		"upstream_failed": upstreamFailed,
+		"failures":        failureDetails(run),
+		"intervals":       intervals,
+	}
+}
+
+// runsOf returns the intervals of a backfill, or the run itself otherwise.
+func runsOf(run *report.Run) []*report.Run {
+	if len(run.Intervals) > 0 {
+		return run.Intervals
+	}
+
+	return []*report.Run{run}
+}
+
+func renderTemplate(template string, run *report.Run) (string, error) {
+	tpl, err := gonja.FromString(template)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse the message template")
+	}
+
+	message, err := tpl.Execute(templateContext(run))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to render the message template")
+	}
+
+	return message, nil
+}
+
+// messageTemplate returns the template of the notification for the status of the run.
+func messageTemplate(run *report.Run, success, failure string) string {
+	if run.Status == report.StatusSucceeded {
+		return success
+	}
+
+	return failure
+}
+
+func outcome(run *report.Run) string {
+	switch run.Status {
+	case report.StatusFailed:
+		return "has failed"
+	case report.StatusCancelled:
+		return "has been cancelled"
+	default:
+		return "has succeeded"
+	}
+}
+
+// defaultMessage is used when the notification does not define a template for the status of the run.
+func defaultMessage(run *report.Run) string {
+	if len(run.Intervals) > 0 {
+		return defaultBackfillMessage(run)
+	}
+
+	message := fmt.Sprintf(
+		"Pipeline `%s` %s for the interval %s - %s in %s.\nRun ID: %s",
+		run.Pipeline,
+		outcome(run),
+		run.StartDate.Format(timeFormat),
+		run.EndDate.Format(timeFormat),
+		duration(run),
+		run.ID,
+	)
+
+	if details := failureDetails(run); details != "" {
+		message += "\n\n" + details
+	}
+
+	return message
+}
+
+// defaultBackfillMessage lists the outcome of every interval of the backfill, followed by the failures of each interval.
+func defaultBackfillMessage(run *report.Run) string {
+	var message strings.Builder
+	fmt.Fprintf(
+		&message,
+		"Pipeline `%s` %s for the backfill of %d intervals between %s - %s in %s.\n",
+		run.Pipeline,
+		outcome(run),
+		len(run.Intervals),
+		run.StartDate.Format(timeFormat),
+		run.EndDate.Format(timeFormat),
+		duration(run),
+	)
+
+	for _, interval := range run.Intervals {
+		fmt.Fprintf(&message, "• %s - %s: %s", interval.StartDate.Format(timeFormat), interval.EndDate.Format(timeFormat), interval.Status)
+		if interval.ID != "" {
+			fmt.Fprintf(&message, " (Run ID: %s)", interval.ID)
+		}
+		message.WriteString("\n")
+	}
+
+	if details := failureDetails(run); details != "" {
+		message.WriteString("\n" + details)
+	}
+
+	return strings.TrimSpace(message.String())
+}
+
+// failureDetails lists the failed instances with their errors and the instances that were skipped because of them, the
+// failures of a backfill are grouped by their intervals.
+func failureDetails(run *report.Run) string {
+	if len(run.Intervals) == 0 {
+		return runFailureDetails(run)
+	}
+
+	sections := make([]string, 0)
+	for _, interval := range run.Intervals {
+		details := runFailureDetails(interval)
+		if details == "" {
+			continue
+		}
+
+		sections = append(sections, fmt.Sprintf("*Interval %s - %s:*\n%s", interval.StartDate.Format(timeFormat), interval.EndDate.Format(timeFormat), details))
+	}
+
+	return strings.Join(sections, "\n\n")
+}
+
+func runFailureDetails(run *report.Run) string {
+	var failed, upstreamFailed strings.Builder
+	for _, instance := range run.Instances {
+		switch instance.Status { //nolint:exhaustive
+		case scheduler.Failed:
+			fmt.Fprintf(&failed, "• `%s`: %s\n", instance.ID, errorSnippet(instance.Error))
+		case scheduler.UpstreamFailed:
+			fmt.Fprintf(&upstreamFailed, "• `%s` (failed upstreams: %s)\n", instance.ID, strings.Join(instance.FailedUpstreams, ", "))
+		}
+	}
+
+	sections := make([]string, 0, 2)
+	if failed.Len() > 0 {
+		sections = append(sections, "*Failed assets:*\n"+failed.String())
+	}
+	if upstreamFailed.Len() > 0 {
+		sections = append(sections, "*Upstream failed assets:*\n"+upstreamFailed.String())
+	}
+
+	return strings.TrimSpace(strings.Join(sections, "\n"))
+}
+
+func errorSnippet(err string) string {
+	err = strings.Join(strings.Fields(err), " ")
+	if len(err) <= maxErrorLength {
+		return err
+	}
+
+	return err[:maxErrorLength] + "..."
+}
+
+func duration(run *report.Run) time.Duration {
+	return (time.Duration(run.DurationSeconds * float64(time.Second))).Truncate(time.Second)
+}
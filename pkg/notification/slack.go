@@ -0,0 +1,54 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/datablast-analytics/blast/pkg/pipeline"
+	"github.com/datablast-analytics/blast/pkg/report"
+	"github.com/pkg/errors"
+)
+
+const requestTimeout = 10 * time.Second
+
+// Slack posts the outcome of the runs to a Slack incoming webhook.
+type Slack struct {
+	client       *http.Client
+	webhookURL   string
+	notification pipeline.SlackNotification
+}
+
+func NewSlack(webhookURL string, notification pipeline.SlackNotification) *Slack {
+	return &Slack{
+		client:       &http.Client{Timeout: requestTimeout},
+		webhookURL:   webhookURL,
+		notification: notification,
+	}
+}
+
+func (s *Slack) Notify(ctx context.Context, run *report.Run) error {
+	message, err := s.message(run)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(map[string]string{"text": message})
+	if err != nil {
+		return errors.Wrap(err, "failed to build the Slack message")
+	}
+
+	return errors.Wrap(postJSON(ctx, s.client, s.webhookURL, nil, payload), "failed to send the Slack message")
+}
+
+// message renders the template of the notification for the status of the run, the default message is used if there is
+// no template given.
+func (s *Slack) message(run *report.Run) (string, error) {
+	template := messageTemplate(run, s.notification.Success, s.notification.Failure)
+	if template == "" {
+		return defaultMessage(run), nil
+	}
+
+	return renderTemplate(template, run)
+}
@@ -0,0 +1,163 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/datablast-analytics/blast/pkg/pipeline"
+	"github.com/datablast-analytics/blast/pkg/report"
+	"github.com/datablast-analytics/blast/pkg/scheduler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlack_Notify(t *testing.T) {
+	t.Parallel()
+
+	startDate := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2023, 3, 1, 23, 59, 59, 0, time.UTC)
+
+	succeededRun := &report.Run{
+		ID:              "run-1",
+		Pipeline:        "dashboard",
+		StartDate:       startDate,
+		EndDate:         endDate,
+		DurationSeconds: 65.4,
+		Status:          report.StatusSucceeded,
+		Instances: []*report.Instance{
+			{ID: "task1", Asset: "task1", Status: scheduler.Succeeded},
+		},
+	}
+
+	failedRun := &report.Run{
+		ID:              "run-2",
+		Pipeline:        "dashboard",
+		StartDate:       startDate,
+		EndDate:         endDate,
+		DurationSeconds: 12,
+		Status:          report.StatusFailed,
+		Instances: []*report.Instance{
+			{ID: "task1", Asset: "task1", Status: scheduler.Succeeded},
+			{ID: "task2", Asset: "task2", Status: scheduler.Failed, Error: "query failed:\n  table not found"},
+			{ID: "task3", Asset: "task3", Status: scheduler.UpstreamFailed, FailedUpstreams: []string{"task2"}},
+		},
+	}
+
+	secondInterval := *failedRun
+	secondInterval.StartDate = startDate.AddDate(0, 0, 1)
+	secondInterval.EndDate = endDate.AddDate(0, 0, 1)
+	backfill := report.NewBackfill("dashboard", []*report.Run{succeededRun, &secondInterval})
+	backfill.StartedAt = startDate
+	backfill.DurationSeconds = 90
+
+	tests := []struct {
+		name         string
+		notification pipeline.SlackNotification
+		run          *report.Run
+		statusCode   int
+		want         string
+		wantErr      bool
+	}{
+		{
+			name:         "default success message",
+			notification: pipeline.SlackNotification{Name: "alerts", Connection: "slack"},
+			run:          succeededRun,
+			statusCode:   http.StatusOK,
+			want:         "Pipeline `dashboard` has succeeded for the interval 2023-03-01 00:00:00 - 2023-03-01 23:59:59 in 1m5s.\nRun ID: run-1",
+		},
+		{
+			name:         "default failure message lists the failed and upstream failed assets",
+			notification: pipeline.SlackNotification{Name: "alerts", Connection: "slack"},
+			run:          failedRun,
+			statusCode:   http.StatusOK,
+			want: "Pipeline `dashboard` has failed for the interval 2023-03-01 00:00:00 - 2023-03-01 23:59:59 in 12s.\nRun ID: run-2\n\n" +
+				"*Failed assets:*\n• `task2`: query failed: table not found\n\n" +
+				"*Upstream failed assets:*\n• `task3` (failed upstreams: task2)",
+		},
+		{
+			name:         "default backfill message lists every interval",
+			notification: pipeline.SlackNotification{Name: "alerts", Connection: "slack"},
+			run:          backfill,
+			statusCode:   http.StatusOK,
+			want: "Pipeline `dashboard` has failed for the backfill of 2 intervals between 2023-03-01 00:00:00 - 2023-03-02 23:59:59 in 1m30s.\n" +
+				"• 2023-03-01 00:00:00 - 2023-03-01 23:59:59: succeeded (Run ID: run-1)\n" +
+				"• 2023-03-02 00:00:00 - 2023-03-02 23:59:59: failed (Run ID: run-2)\n\n" +
+				"*Interval 2023-03-02 00:00:00 - 2023-03-02 23:59:59:*\n" +
+				"*Failed assets:*\n• `task2`: query failed: table not found\n\n" +
+				"*Upstream failed assets:*\n• `task3` (failed upstreams: task2)",
+		},
+		{
+			name: "backfill template can list the intervals",
+			notification: pipeline.SlackNotification{
+				Name:       "alerts",
+				Connection: "slack",
+				Failure:    "{% for i in intervals %}{{ i.start_date }} {{ i.status }}; {% endfor %}{% for f in failed %}{{ f.run_id }}: {{ f.asset }}{% endfor %}",
+			},
+			run:        backfill,
+			statusCode: http.StatusOK,
+			want:       "2023-03-01 00:00:00 succeeded; 2023-03-02 00:00:00 failed; run-2: task2",
+		},
+		{
+			name: "success template is rendered",
+			notification: pipeline.SlackNotification{
+				Name:       "alerts",
+				Connection: "slack",
+				Success:    "{{ pipeline }} is done for {{ start_date }} ({{ status }})",
+			},
+			run:        succeededRun,
+			statusCode: http.StatusOK,
+			want:       "dashboard is done for 2023-03-01 00:00:00 (succeeded)",
+		},
+		{
+			name: "failure template is rendered",
+			notification: pipeline.SlackNotification{
+				Name:       "alerts",
+				Connection: "slack",
+				Success:    "all good",
+				Failure:    "{{ pipeline }} failed: {% for f in failed %}{{ f.asset }} - {{ f.error }}{% endfor %}",
+			},
+			run:        failedRun,
+			statusCode: http.StatusOK,
+			want:       "dashboard failed: task2 - query failed: table not found",
+		},
+		{
+			name:         "errors from Slack are returned",
+			notification: pipeline.SlackNotification{Name: "alerts", Connection: "slack"},
+			run:          succeededRun,
+			statusCode:   http.StatusNotFound,
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var received map[string]string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte("no_service"))
+			}))
+			defer server.Close()
+
+			err := NewSlack(server.URL, tt.notification).Notify(context.Background(), tt.run)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "no_service")
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, received["text"])
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/datablast-analytics/blast/pkg/pipeline"
+	"github.com/datablast-analytics/blast/pkg/report"
+	"github.com/pkg/errors"
+)
+
+// Webhook posts the outcome of the runs as JSON to an arbitrary HTTP endpoint, the report of the run is sent as is if
+// the notification does not define a template for its status.
+type Webhook struct {
+	client       *http.Client
+	url          string
+	headers      map[string]string
+	notification pipeline.WebhookNotification
+}
+
+func NewWebhook(url string, headers map[string]string, notification pipeline.WebhookNotification) *Webhook {
+	return &Webhook{
+		client:       &http.Client{Timeout: requestTimeout},
+		url:          url,
+		headers:      headers,
+		notification: notification,
+	}
+}
+
+func (w *Webhook) Notify(ctx context.Context, run *report.Run) error {
+	body, err := w.body(run)
+	if err != nil {
+		return err
+	}
+
+	return errors.Wrapf(postJSON(ctx, w.client, w.url, w.headers, body), "failed to call the webhook '%s'", w.notification.Name)
+}
+
+func (w *Webhook) body(run *report.Run) ([]byte, error) {
+	template := messageTemplate(run, w.notification.Success, w.notification.Failure)
+	if template == "" {
+		body, err := json.Marshal(run)
+		return body, errors.Wrap(err, "failed to build the webhook body")
+	}
+
+	body, err := renderTemplate(template, run)
+	if err != nil {
+		return nil, err
+	}
+
+	if !json.Valid([]byte(body)) {
+		return nil, errors.Errorf("the template of the webhook '%s' did not render to a valid JSON document: %s", w.notification.Name, body)
+	}
+
+	return []byte(body), nil
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to create the request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to send the request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		responseBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return errors.Errorf("the server has responded with the status %d: %s", resp.StatusCode, bytes.TrimSpace(responseBody))
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package notification
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/datablast-analytics/blast/pkg/pipeline"
+	"github.com/datablast-analytics/blast/pkg/report"
+	"github.com/datablast-analytics/blast/pkg/scheduler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhook_Notify(t *testing.T) {
+	t.Parallel()
+
+	run := &report.Run{
+		ID:        "run-1",
+		Pipeline:  "dashboard",
+		StartDate: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2023, 3, 2, 0, 0, 0, 0, time.UTC),
+		Status:    report.StatusFailed,
+		Instances: []*report.Instance{
+			{ID: "task1", Asset: "task1", Status: scheduler.Failed, Error: "query \"failed\""},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		notification pipeline.WebhookNotification
+		want         string
+		wantErr      bool
+	}{
+		{
+			name:         "the report of the run is sent by default",
+			notification: pipeline.WebhookNotification{Name: "hook", Connection: "conn"},
+			want:         `{"id":"run-1","pipeline":"dashboard","start_date":"2023-03-01T00:00:00Z","end_date":"2023-03-02T00:00:00Z","started_at":"0001-01-01T00:00:00Z","duration_seconds":0,"status":"failed","instances":[{"id":"task1","asset":"task1","type":"","status":"failed","duration_seconds":0,"error":"query \"failed\""}]}`,
+		},
+		{
+			name: "the failure template is rendered",
+			notification: pipeline.WebhookNotification{
+				Name:       "hook",
+				Connection: "conn",
+				Failure:    `{"summary": "{{ pipeline }} {{ status }}", "details": {{ failures | tojson }}}`,
+			},
+			want: `{"summary": "dashboard failed", "details": "*Failed assets:*\n• ` + "`task1`" + `: query \"failed\""}`,
+		},
+		{
+			name: "templates that are not valid JSON are rejected",
+			notification: pipeline.WebhookNotification{
+				Name:       "hook",
+				Connection: "conn",
+				Failure:    `{"summary": {{ pipeline }}}`,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var received string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				received = string(body)
+			}))
+			defer server.Close()
+
+			headers := map[string]string{"Authorization": "Bearer token"}
+			err := NewWebhook(server.URL, headers, tt.notification).Notify(context.Background(), run)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Empty(t, received)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, received)
+		})
+	}
+}
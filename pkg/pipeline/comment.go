@@ -4,7 +4,9 @@ import (
 	"bufio"
 	"io"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
@@ -129,7 +131,11 @@ func singleLineCommentsToTask(scanner *bufio.Scanner, commentMarker, filePath st
 		return nil, errors.Wrapf(err, "failed to get absolute path for file %s", filePath)
 	}
 
-	task := commentRowsToTask(commentRows)
+	task, err := commentRowsToTask(commentRows)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the comments in file %s", filePath)
+	}
+
 	task.ExecutableFile = ExecutableFile{
 		Name:    filepath.Base(filePath),
 		Path:    absFilePath,
@@ -139,7 +145,7 @@ func singleLineCommentsToTask(scanner *bufio.Scanner, commentMarker, filePath st
 	return task, nil
 }
 
-func commentRowsToTask(commentRows []string) *Asset {
+func commentRowsToTask(commentRows []string) (*Asset, error) {
 	task := Asset{
 		Parameters: make(map[string]string),
 		DependsOn:  []string{},
@@ -171,6 +177,50 @@ func commentRowsToTask(commentRows []string) *Asset {
 		case "connection":
 			task.Connection = value
 
+			continue
+		case "owner":
+			task.Owner = value
+
+			continue
+		case "domain":
+			task.Domain = value
+
+			continue
+		case "pool":
+			task.Pool = value
+
+			continue
+		case "retries":
+			retries, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, errors.Errorf("invalid value for 'retries', it must be an integer: %s", value)
+			}
+
+			task.Retries = &retries
+			continue
+		case "priority":
+			priority, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, errors.Errorf("invalid value for 'priority', it must be an integer: %s", value)
+			}
+
+			task.Priority = priority
+			continue
+		case "retry_delay":
+			retryDelay, err := time.ParseDuration(value)
+			if err != nil {
+				return nil, errors.Errorf("invalid value for 'retry_delay', it must be a duration such as 30s: %s", value)
+			}
+
+			task.RetryDelay = &retryDelay
+			continue
+		case "timeout":
+			timeout, err := time.ParseDuration(value)
+			if err != nil {
+				return nil, errors.Errorf("invalid value for 'timeout', it must be a duration such as 30m: %s", value)
+			}
+
+			task.Timeout = timeout
 			continue
 		case "depends":
 			values := strings.Split(value, ",")
@@ -178,6 +228,22 @@ func commentRowsToTask(commentRows []string) *Asset {
 				task.DependsOn = append(task.DependsOn, strings.TrimSpace(v))
 			}
 
+			continue
+		case "tags":
+			values := strings.Split(value, ",")
+			for _, v := range values {
+				task.Tags = append(task.Tags, strings.TrimSpace(v))
+			}
+
+			continue
+		case "notify_on_failure":
+			task.NotifyOnFailure = []string{}
+			for _, v := range strings.Split(value, ",") {
+				if v = strings.TrimSpace(v); v != "" {
+					task.NotifyOnFailure = append(task.NotifyOnFailure, v)
+				}
+			}
+
 			continue
 		}
 
@@ -191,6 +257,20 @@ func commentRowsToTask(commentRows []string) *Asset {
 			continue
 		}
 
+		if strings.HasPrefix(key, "meta.") {
+			metaKey := strings.TrimPrefix(key, "meta.")
+			if metaKey == "" {
+				continue
+			}
+
+			if task.Meta == nil {
+				task.Meta = make(map[string]string)
+			}
+
+			task.Meta[metaKey] = value
+			continue
+		}
+
 		if strings.HasPrefix(key, "schedule.") {
 			schedule := strings.Split(key, ".")
 			if len(schedule) != 2 {
@@ -233,5 +313,5 @@ func commentRowsToTask(commentRows []string) *Asset {
 		}
 	}
 
-	return &task
+	return &task, nil
 }
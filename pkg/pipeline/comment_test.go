@@ -4,6 +4,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/datablast-analytics/blast/pkg/pipeline"
 	"github.com/spf13/afero"
@@ -41,6 +42,13 @@ func Test_createTaskFromFile(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid retries are reported instead of being ignored",
+			args: args{
+				filePath: "testdata/comments/invalidretries.sql",
+			},
+			wantErr: true,
+		},
 		{
 			name: "existing file with no comments is skipped",
 			args: args{
@@ -76,7 +84,17 @@ func Test_createTaskFromFile(t *testing.T) {
 					IncrementalKey: "dt",
 					ClusterBy:      []string{"event_name"},
 				},
-				Columns: map[string]pipeline.Column{},
+				Columns:         map[string]pipeline.Column{},
+				Tags:            []string{"finance", "daily"},
+				Owner:           "data-team@example.com",
+				Domain:          "finance",
+				Meta:            map[string]string{"sla": "6h", "pii": "false"},
+				Pool:            "heavy",
+				Priority:        10,
+				Retries:         intPtr(3),
+				RetryDelay:      durationPtr(30 * time.Second),
+				Timeout:         time.Hour,
+				NotifyOnFailure: []string{"oncall", "data-team"},
 			},
 		},
 		{
@@ -108,6 +126,10 @@ func Test_createTaskFromFile(t *testing.T) {
 					ClusterBy:      []string{"event_name"},
 				},
 				Columns: map[string]pipeline.Column{},
+				Tags:    []string{"finance"},
+				Owner:   "data-team@example.com",
+				Domain:  "finance",
+				Meta:    map[string]string{"sla": "6h"},
 			},
 		},
 		{
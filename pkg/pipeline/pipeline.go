@@ -1,8 +1,11 @@
 package pipeline
 
 import (
+	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/datablast-analytics/blast/pkg/path"
 	"github.com/pkg/errors"
@@ -43,7 +46,9 @@ type TaskSchedule struct {
 }
 
 type Notifications struct {
-	Slack []SlackNotification
+	Slack   []SlackNotification
+	Webhook []WebhookNotification
+	Email   []EmailNotification
 }
 
 type SlackNotification struct {
@@ -53,6 +58,72 @@ type SlackNotification struct {
 	Failure    string
 }
 
+// WebhookNotification posts a JSON body to the URL of its connection, the Success and Failure templates must render to
+// a valid JSON document.
+type WebhookNotification struct {
+	Name       string
+	Connection string
+	Success    string
+	Failure    string
+}
+
+type EmailNotification struct {
+	Name       string
+	Connection string
+	To         []string
+	Success    string
+	Failure    string
+}
+
+// Names returns the names of all the notifications regardless of their type.
+func (n Notifications) Names() []string {
+	names := make([]string, 0, len(n.Slack)+len(n.Webhook)+len(n.Email))
+	for _, slack := range n.Slack {
+		names = append(names, slack.Name)
+	}
+	for _, webhook := range n.Webhook {
+		names = append(names, webhook.Name)
+	}
+	for _, email := range n.Email {
+		names = append(names, email.Name)
+	}
+
+	return names
+}
+
+// Pools limit the number of instances that can run at the same time for a connection, an asset type or a custom pool
+// referenced by the assets.
+type Pools struct {
+	Connections map[string]int `yaml:"connections"`
+	AssetTypes  map[string]int `yaml:"asset_types"`
+	Custom      map[string]int `yaml:"custom"`
+}
+
+// Invalid returns the pools without any slots as `group.name = slots`, sorted. The instances that need a slot from them
+// would never run and the run would wait for them forever.
+func (p Pools) Invalid() []string {
+	invalidPools := make([]string, 0)
+	for group, pools := range map[string]map[string]int{
+		"connections": p.Connections,
+		"asset_types": p.AssetTypes,
+		"custom":      p.Custom,
+	} {
+		for name, slots := range pools {
+			if slots <= 0 {
+				invalidPools = append(invalidPools, fmt.Sprintf("%s.%s = %d", group, name, slots))
+			}
+		}
+	}
+
+	sort.Strings(invalidPools)
+	return invalidPools
+}
+
+type ConcurrencyPool struct {
+	Name  string
+	Slots int
+}
+
 type MaterializationType string
 
 const (
@@ -86,9 +157,25 @@ type ColumnCheckValue struct {
 	String      *string
 }
 
+type CheckSeverity string
+
+const (
+	CheckSeverityError CheckSeverity = "error"
+	CheckSeverityWarn  CheckSeverity = "warn"
+)
+
+// CheckThreshold allows a number or a percentage of the rows to violate a check before it fails, at most one of them is
+// expected to be set.
+type CheckThreshold struct {
+	Rows    *int64
+	Percent *float64
+}
+
 type ColumnCheck struct {
-	Name  string `yaml:"name"`
-	Value ColumnCheckValue
+	Name      string `yaml:"name"`
+	Value     ColumnCheckValue
+	Severity  CheckSeverity
+	Threshold CheckThreshold
 }
 
 type Column struct {
@@ -97,11 +184,23 @@ type Column struct {
 	Checks      []ColumnCheck `yaml:"checks"`
 }
 
+// CustomCheck is a query-based check of an asset, the query must return a single count or boolean that is compared to
+// the expected value, where the booleans are treated as 1 and 0.
+type CustomCheck struct {
+	Name     string
+	Query    string
+	Value    int64
+	Severity CheckSeverity
+}
+
 type AssetType string
 
 var assetTypeConnectionMapping = map[AssetType][]string{
-	AssetType("bq.sql"): {"google_cloud_platform", "gcp"},
-	AssetType("sf.sql"): {"snowflake", "sf"},
+	AssetType("bq.sql"):              {"google_cloud_platform", "gcp"},
+	AssetType("bq.sensor.table"):     {"google_cloud_platform", "gcp"},
+	AssetType("bq.sensor.query"):     {"google_cloud_platform", "gcp"},
+	AssetType("bq.sensor.partition"): {"google_cloud_platform", "gcp"},
+	AssetType("sf.sql"):              {"snowflake", "sf"},
 }
 
 type Asset struct {
@@ -116,6 +215,18 @@ type Asset struct {
 	Schedule        TaskSchedule
 	Materialization Materialization
 	Columns         map[string]Column
+	CustomChecks    []CustomCheck
+	Tags            []string
+	Owner           string
+	Domain          string
+	Meta            map[string]string
+	Pool            string
+	Priority        int
+	Retries         *int
+	RetryDelay      *time.Duration
+	Timeout         time.Duration
+	// NotifyOnFailure overrides the notifications that are sent when the asset fails, nil means all of them.
+	NotifyOnFailure []string
 
 	Pipeline *Pipeline
 
@@ -185,6 +296,10 @@ type Pipeline struct {
 	DefaultConnections map[string]string `yaml:"default_connections"`
 	Tasks              []*Asset
 	Notifications      Notifications `yaml:"notifications"`
+	Retries            int           `yaml:"retries"`
+	RetryDelay         time.Duration `yaml:"retry_delay"`
+	MaxActiveTasks     int           `yaml:"max_active_tasks"`
+	Pools              Pools         `yaml:"pools"`
 
 	TasksByType map[AssetType][]*Asset
 	tasksByName map[string]*Asset
@@ -209,6 +324,45 @@ func (p *Pipeline) GetConnectionNameForAsset(asset *Asset) string {
 	return ""
 }
 
+// GetRetriesForAsset returns the number of times the asset will be retried upon failure, the asset-level value takes
+// precedence over the pipeline default whenever it is set, even to zero.
+func (p *Pipeline) GetRetriesForAsset(asset *Asset) int {
+	if asset.Retries != nil {
+		return *asset.Retries
+	}
+
+	return p.Retries
+}
+
+func (p *Pipeline) GetRetryDelayForAsset(asset *Asset) time.Duration {
+	if asset.RetryDelay != nil {
+		return *asset.RetryDelay
+	}
+
+	return p.RetryDelay
+}
+
+// GetConcurrencyPoolsForAsset returns the pools the asset takes a slot from while running, only the pools with a limit
+// are returned.
+func (p *Pipeline) GetConcurrencyPoolsForAsset(asset *Asset) []ConcurrencyPool {
+	pools := make([]ConcurrencyPool, 0)
+
+	connection := p.GetConnectionNameForAsset(asset)
+	if slots, ok := p.Pools.Connections[connection]; ok && connection != "" {
+		pools = append(pools, ConcurrencyPool{Name: "connection:" + connection, Slots: slots})
+	}
+
+	if slots, ok := p.Pools.AssetTypes[string(asset.Type)]; ok {
+		pools = append(pools, ConcurrencyPool{Name: "type:" + string(asset.Type), Slots: slots})
+	}
+
+	if slots, ok := p.Pools.Custom[asset.Pool]; ok && asset.Pool != "" {
+		pools = append(pools, ConcurrencyPool{Name: "pool:" + asset.Pool, Slots: slots})
+	}
+
+	return pools
+}
+
 func (p *Pipeline) RelativeAssetPath(t *Asset) string {
 	absolutePipelineRoot := filepath.Dir(p.DefinitionFile.Path)
 
@@ -240,6 +394,16 @@ func (p *Pipeline) GetAssetByPath(assetPath string) *Asset {
 	return nil
 }
 
+func (p *Pipeline) GetAssetByName(name string) *Asset {
+	for _, asset := range p.Tasks {
+		if asset.Name == name {
+			return asset
+		}
+	}
+
+	return nil
+}
+
 type TaskCreator func(path string) (*Asset, error)
 
 type BuilderConfig struct {
@@ -3,6 +3,7 @@ package pipeline_test
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/datablast-analytics/blast/pkg/pipeline"
 	"github.com/spf13/afero"
@@ -132,7 +133,20 @@ func Test_pipelineBuilder_CreatePipelineFromPath(t *testing.T) {
 			"slack":           "slack-connection",
 			"gcpConnectionId": "gcp-connection-id-here",
 		},
-		Tasks: []*pipeline.Asset{asset1, asset2, asset3, asset4},
+		Notifications: pipeline.Notifications{
+			Slack:   []pipeline.SlackNotification{{Name: "alerts", Connection: "slack-alerts"}},
+			Webhook: []pipeline.WebhookNotification{{Name: "oncall", Connection: "pagerduty", Failure: `{"summary": "{{ pipeline }} has failed"}`}},
+			Email:   []pipeline.EmailNotification{{Name: "data-team", Connection: "mail", To: []string{"data-team@example.com"}}},
+		},
+		Tasks:          []*pipeline.Asset{asset1, asset2, asset3, asset4},
+		Retries:        3,
+		RetryDelay:     10 * time.Second,
+		MaxActiveTasks: 8,
+		Pools: pipeline.Pools{
+			Connections: map[string]int{"gcp-connection-id-here": 4},
+			AssetTypes:  map[string]int{"python": 2},
+			Custom:      map[string]int{"heavy": 1},
+		},
 	}
 	fs := afero.NewOsFs()
 	tests := []struct {
@@ -429,3 +443,75 @@ func TestPipeline_GetConnectionNameForAsset(t *testing.T) {
 	assert.Equal(t, "connection2", pipeline1.GetConnectionNameForAsset(asset2))
 	assert.Equal(t, "custom-connection", pipeline1.GetConnectionNameForAsset(asset3))
 }
+
+func TestPipeline_GetRetriesForAsset(t *testing.T) {
+	t.Parallel()
+
+	asset1 := &pipeline.Asset{
+		Name: "asset1",
+	}
+	asset2 := &pipeline.Asset{
+		Name:       "asset2",
+		Retries:    intPtr(5),
+		RetryDelay: durationPtr(time.Minute),
+	}
+	// the assets can opt out of the pipeline defaults
+	asset3 := &pipeline.Asset{
+		Name:       "asset3",
+		Retries:    intPtr(0),
+		RetryDelay: durationPtr(0),
+	}
+
+	p := &pipeline.Pipeline{
+		Name:       "pipeline1",
+		Retries:    3,
+		RetryDelay: 10 * time.Second,
+		Tasks:      []*pipeline.Asset{asset1, asset2, asset3},
+	}
+
+	assert.Equal(t, 3, p.GetRetriesForAsset(asset1))
+	assert.Equal(t, 10*time.Second, p.GetRetryDelayForAsset(asset1))
+	assert.Equal(t, 5, p.GetRetriesForAsset(asset2))
+	assert.Equal(t, time.Minute, p.GetRetryDelayForAsset(asset2))
+	assert.Equal(t, 0, p.GetRetriesForAsset(asset3))
+	assert.Equal(t, time.Duration(0), p.GetRetryDelayForAsset(asset3))
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
+
+func TestPipeline_GetConcurrencyPoolsForAsset(t *testing.T) {
+	t.Parallel()
+
+	p := &pipeline.Pipeline{
+		DefaultConnections: map[string]string{"gcp": "gcp-default"},
+		Pools: pipeline.Pools{
+			Connections: map[string]int{"gcp-default": 4},
+			AssetTypes:  map[string]int{"python": 2},
+			Custom:      map[string]int{"heavy": 1},
+		},
+	}
+
+	assert.Equal(t, []pipeline.ConcurrencyPool{{Name: "connection:gcp-default", Slots: 4}}, p.GetConcurrencyPoolsForAsset(&pipeline.Asset{Type: "bq.sql"}))
+	assert.Equal(t, []pipeline.ConcurrencyPool{{Name: "connection:gcp-default", Slots: 4}, {Name: "pool:heavy", Slots: 1}}, p.GetConcurrencyPoolsForAsset(&pipeline.Asset{Type: "bq.sql", Pool: "heavy"}))
+	assert.Equal(t, []pipeline.ConcurrencyPool{{Name: "type:python", Slots: 2}}, p.GetConcurrencyPoolsForAsset(&pipeline.Asset{Type: "python", Pool: "undefined"}))
+	assert.Empty(t, p.GetConcurrencyPoolsForAsset(&pipeline.Asset{Type: "sf.sql"}))
+}
+
+func TestPools_Invalid(t *testing.T) {
+	t.Parallel()
+
+	pools := pipeline.Pools{
+		Connections: map[string]int{"gcp": 2, "sf": 0},
+		AssetTypes:  map[string]int{"python": -1},
+		Custom:      map[string]int{"heavy": 1},
+	}
+
+	assert.Equal(t, []string{"asset_types.python = -1", "connections.sf = 0"}, pools.Invalid())
+	assert.Empty(t, pipeline.Pools{}.Invalid())
+}
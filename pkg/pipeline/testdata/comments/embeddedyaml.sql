@@ -14,6 +14,12 @@ parameters:
     param2: second-parameter
     s3_file_path: s3://bucket/path
 connection: conn1
+tags:
+    - finance
+owner: data-team@example.com
+domain: finance
+meta:
+    sla: 6h
 materialization:
     type: table
     partition_by: dt
@@ -0,0 +1,5 @@
+-- @blast.name: invalid-retries
+-- @blast.type: bq.sql
+-- @blast.retries: three
+
+select 1
@@ -8,11 +8,22 @@
 -- @blast.parameters.param2: second-parameter
 -- @blast.parameters.s3_file_path: s3://bucket/path
 -- @blast.connection: conn2
+-- @blast.tags: finance, daily
+-- @blast.owner: data-team@example.com
+-- @blast.domain: finance
+-- @blast.pool: heavy
+-- @blast.priority: 10
+-- @blast.meta.sla: 6h
+-- @blast.meta.pii: false
 -- @blast.materialization.type: table
 -- @blast.materialization.partition_by: dt
 -- @blast.materialization.cluster_by: event_name
 -- @blast.materialization.strategy: delete+insert
 -- @blast.materialization.incremental_key: dt
+-- @blast.retries: 3
+-- @blast.retry_delay: 30s
+-- @blast.timeout: 1h
+-- @blast.notify_on_failure: oncall, data-team
 
 select *
 from foo;
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/datablast-analytics/blast/pkg/path"
 	"github.com/pkg/errors"
@@ -32,6 +33,22 @@ func (a *depends) UnmarshalYAML(value *yaml.Node) error {
 	return err
 }
 
+type tags []string
+
+func (a *tags) UnmarshalYAML(value *yaml.Node) error {
+	multi, err := mustBeStringArray("tags", value)
+	*a = multi
+	return err
+}
+
+type notifyOnFailure []string
+
+func (a *notifyOnFailure) UnmarshalYAML(value *yaml.Node) error {
+	multi, err := mustBeStringArray("notify_on_failure", value)
+	*a = multi
+	return err
+}
+
 type clusterBy []string
 
 func (a *clusterBy) UnmarshalYAML(value *yaml.Node) error {
@@ -92,9 +109,16 @@ func (a *columnCheckValue) UnmarshalYAML(value *yaml.Node) error {
 	return nil
 }
 
+type checkThreshold struct {
+	Rows    *int64   `yaml:"rows"`
+	Percent *float64 `yaml:"percent"`
+}
+
 type columnCheck struct {
-	Name  string           `yaml:"name"`
-	Value columnCheckValue `yaml:"value"`
+	Name      string           `yaml:"name"`
+	Value     columnCheckValue `yaml:"value"`
+	Severity  string           `yaml:"severity"`
+	Threshold checkThreshold   `yaml:"threshold"`
 }
 
 type column struct {
@@ -102,6 +126,34 @@ type column struct {
 	Tests       []columnCheck `yaml:"checks"`
 }
 
+type customCheckValue int64
+
+func (v *customCheckValue) UnmarshalYAML(value *yaml.Node) error {
+	var boolean bool
+	if err := value.Decode(&boolean); err == nil {
+		*v = 0
+		if boolean {
+			*v = 1
+		}
+		return nil
+	}
+
+	var integer int64
+	if err := value.Decode(&integer); err != nil {
+		return errors.New("the `value` of a custom check must be an integer or a boolean")
+	}
+
+	*v = customCheckValue(integer)
+	return nil
+}
+
+type customCheck struct {
+	Name     string           `yaml:"name"`
+	Query    string           `yaml:"query"`
+	Value    customCheckValue `yaml:"value"`
+	Severity string           `yaml:"severity"`
+}
+
 type taskDefinition struct {
 	Name            string            `yaml:"name"`
 	Description     string            `yaml:"description"`
@@ -114,6 +166,17 @@ type taskDefinition struct {
 	Schedule        taskSchedule      `yaml:"schedule"`
 	Materialization materialization   `yaml:"materialization"`
 	Columns         map[string]column `yaml:"columns"`
+	CustomChecks    []customCheck     `yaml:"custom_checks"`
+	Tags            tags              `yaml:"tags"`
+	Owner           string            `yaml:"owner"`
+	Domain          string            `yaml:"domain"`
+	Meta            map[string]string `yaml:"meta"`
+	Pool            string            `yaml:"pool"`
+	Priority        int               `yaml:"priority"`
+	Retries         *int              `yaml:"retries"`
+	RetryDelay      *time.Duration    `yaml:"retry_delay"`
+	Timeout         time.Duration     `yaml:"timeout"`
+	NotifyOnFailure notifyOnFailure   `yaml:"notify_on_failure"`
 }
 
 func CreateTaskFromYamlDefinition(fs afero.Fs) TaskCreator {
@@ -182,8 +245,10 @@ func ConvertYamlToTask(content []byte) (*Asset, error) {
 		tests := make([]ColumnCheck, len(column.Tests))
 		for i, test := range column.Tests {
 			tests[i] = ColumnCheck{
-				Name:  test.Name,
-				Value: ColumnCheckValue(test.Value),
+				Name:      test.Name,
+				Value:     ColumnCheckValue(test.Value),
+				Severity:  CheckSeverity(strings.ToLower(test.Severity)),
+				Threshold: CheckThreshold(test.Threshold),
 			}
 		}
 
@@ -194,6 +259,19 @@ func ConvertYamlToTask(content []byte) (*Asset, error) {
 		}
 	}
 
+	var customChecks []CustomCheck
+	if len(definition.CustomChecks) > 0 {
+		customChecks = make([]CustomCheck, len(definition.CustomChecks))
+		for i, check := range definition.CustomChecks {
+			customChecks[i] = CustomCheck{
+				Name:     check.Name,
+				Query:    check.Query,
+				Value:    int64(check.Value),
+				Severity: CheckSeverity(strings.ToLower(check.Severity)),
+			}
+		}
+	}
+
 	task := Asset{
 		Name:            definition.Name,
 		Description:     definition.Description,
@@ -205,6 +283,17 @@ func ConvertYamlToTask(content []byte) (*Asset, error) {
 		Schedule:        TaskSchedule{Days: definition.Schedule.Days},
 		Materialization: mat,
 		Columns:         columns,
+		CustomChecks:    customChecks,
+		Tags:            definition.Tags,
+		Owner:           definition.Owner,
+		Domain:          definition.Domain,
+		Meta:            definition.Meta,
+		Pool:            definition.Pool,
+		Priority:        definition.Priority,
+		Retries:         definition.Retries,
+		RetryDelay:      definition.RetryDelay,
+		Timeout:         definition.Timeout,
+		NotifyOnFailure: definition.NotifyOnFailure,
 	}
 
 	return &task, nil
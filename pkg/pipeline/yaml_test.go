@@ -3,6 +3,7 @@ package pipeline_test
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/datablast-analytics/blast/pkg/pipeline"
 	"github.com/pkg/errors"
@@ -18,6 +19,8 @@ func TestCreateTaskFromYamlDefinition(t *testing.T) {
 		return absolutePath
 	}
 
+	notNullThreshold := 2.5
+
 	type args struct {
 		filePath string
 	}
@@ -63,6 +66,20 @@ func TestCreateTaskFromYamlDefinition(t *testing.T) {
 				},
 				Connection: "conn1",
 				DependsOn:  []string{"gcs-to-bq"},
+				Tags:       []string{"finance", "reporting"},
+				Owner:      "reporting-team@example.com",
+				Domain:     "reporting",
+				Pool:       "reporting",
+				Priority:   -5,
+				Meta:       map[string]string{"sla": "2h", "tier": "gold"},
+				CustomChecks: []pipeline.CustomCheck{
+					{Name: "has_rows", Query: "SELECT count(*) > 0 FROM hello_world", Value: 1},
+					{Name: "no_duplicate_keys", Query: "SELECT count(*) - count(DISTINCT key1) FROM hello_world", Severity: pipeline.CheckSeverityWarn},
+				},
+				Retries:         intPtr(2),
+				RetryDelay:      durationPtr(time.Minute),
+				Timeout:         30 * time.Minute,
+				NotifyOnFailure: []string{"oncall"},
 				Materialization: pipeline.Materialization{
 					Type:           pipeline.MaterializationTypeTable,
 					Strategy:       pipeline.MaterializationStrategyCreateReplace,
@@ -79,7 +96,11 @@ func TestCreateTaskFromYamlDefinition(t *testing.T) {
 								Name: "unique",
 							},
 							{
-								Name: "not_null",
+								Name:     "not_null",
+								Severity: pipeline.CheckSeverityWarn,
+								Threshold: pipeline.CheckThreshold{
+									Percent: &notNullThreshold,
+								},
 							},
 							{
 								Name: "accepted_values",
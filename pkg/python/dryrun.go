@@ -0,0 +1,65 @@
+package python
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/datablast-analytics/blast/pkg/git"
+	"github.com/datablast-analytics/blast/pkg/user"
+	"github.com/spf13/afero"
+)
+
+type virtualEnvPathFinder interface {
+	virtualEnvPath(requirementsTxt string) (string, []byte, error)
+}
+
+// dryRunner prints what would be executed for a Python asset instead of running it, the virtualenvs are not created.
+type dryRunner struct {
+	fs   afero.Fs
+	venv virtualEnvPathFinder
+}
+
+func (d *dryRunner) Run(ctx context.Context, execCtx *executionContext) error {
+	log(ctx, fmt.Sprintf("module: %s", execCtx.module))
+	if execCtx.requirementsTxt == "" {
+		log(ctx, "virtualenv: none, there is no requirements.txt")
+		return nil
+	}
+
+	log(ctx, fmt.Sprintf("requirements: %s", execCtx.requirementsTxt))
+	venvPath, _, err := d.venv.virtualEnvPath(execCtx.requirementsTxt)
+	if err != nil {
+		return err
+	}
+
+	if venvPath == "" {
+		log(ctx, "virtualenv: none, requirements.txt is empty")
+		return nil
+	}
+
+	status := "will be installed"
+	if isInstalled(d.fs, venvPath) {
+		status = "installed"
+	}
+
+	log(ctx, fmt.Sprintf("virtualenv: %s (%s)", venvPath, status))
+	return nil
+}
+
+// NewDryRunOperator returns an operator that resolves the module and the virtualenv of the Python assets the same way
+// the local operator does, but only prints them.
+func NewDryRunOperator() *LocalOperator {
+	fs := afero.NewOsFs()
+
+	return &LocalOperator{
+		repoFinder: &git.RepoFinder{},
+		module:     &ModulePathFinder{},
+		runner: &dryRunner{
+			fs: fs,
+			venv: &installReqsToHomeDir{
+				fs:     fs,
+				config: user.NewConfigManager(fs),
+			},
+		},
+	}
+}
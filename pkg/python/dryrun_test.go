@@ -0,0 +1,90 @@
+package python
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/datablast-analytics/blast/pkg/executor"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_dryRunner_Run(t *testing.T) {
+	t.Parallel()
+
+	requirementsTxt := "/path/to/requirements.txt"
+	// this is the hash for the content 'req1\nreq2' below
+	fileHash := "147ed061038b695a06605f70c4e966c602effd55ab6cc50315787fe7f5633d81"
+
+	tests := []struct {
+		name         string
+		requirements string
+		reqsContent  string
+		venvExists   bool
+		want         string
+		wantErr      bool
+	}{
+		{
+			name: "no requirements",
+			want: "module: path.to.module\nvirtualenv: none, there is no requirements.txt\n",
+		},
+		{
+			name:         "empty requirements",
+			requirements: requirementsTxt,
+			reqsContent:  "  \n",
+			want:         "module: path.to.module\nrequirements: /path/to/requirements.txt\nvirtualenv: none, requirements.txt is empty\n",
+		},
+		{
+			name:         "missing requirements file",
+			requirements: "/some/other/requirements.txt",
+			want:         "module: path.to.module\nrequirements: /some/other/requirements.txt\n",
+			wantErr:      true,
+		},
+		{
+			name:         "virtualenv to be created",
+			requirements: requirementsTxt,
+			reqsContent:  "req1\nreq2",
+			want:         "module: path.to.module\nrequirements: /path/to/requirements.txt\nvirtualenv: /venvs/" + fileHash + " (will be installed)\n",
+		},
+		{
+			name:         "existing virtualenv",
+			requirements: requirementsTxt,
+			reqsContent:  "req1\nreq2",
+			venvExists:   true,
+			want:         "module: path.to.module\nrequirements: /path/to/requirements.txt\nvirtualenv: /venvs/" + fileHash + " (installed)\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			require.NoError(t, afero.WriteFile(fs, requirementsTxt, []byte(tt.reqsContent), 0o644))
+			if tt.venvExists {
+				require.NoError(t, afero.WriteFile(fs, "/venvs/"+fileHash+"/"+installedMarkerFile, []byte{}, 0o644))
+			}
+
+			config := new(mockConfigManager)
+			config.On("MakeVirtualenvPath", fileHash).Return("/venvs/" + fileHash)
+
+			r := &dryRunner{
+				fs:   fs,
+				venv: &installReqsToHomeDir{fs: fs, config: config},
+			}
+
+			var output bytes.Buffer
+			ctx := context.WithValue(context.Background(), executor.KeyPrinter, &output)
+			err := r.Run(ctx, &executionContext{module: "path.to.module", requirementsTxt: tt.requirements})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.want, output.String())
+		})
+	}
+}
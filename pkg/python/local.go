@@ -7,6 +7,7 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
 	"github.com/datablast-analytics/blast/pkg/executor"
@@ -20,7 +21,7 @@ type cmd interface {
 }
 
 type requirementsInstaller interface {
-	EnsureVirtualEnvExists(ctx context.Context, repo *git.Repo, requirementsTxt string) (string, error)
+	EnsureVirtualEnvExists(ctx context.Context, repo *git.Repo, requirementsTxt string) (string, func(), error)
 }
 
 type localPythonRunner struct {
@@ -51,8 +52,7 @@ func (l *localPythonRunner) Run(ctx context.Context, execCtx *executionContext)
 		return l.cmd.Run(ctx, execCtx.repo, noDependencyCommand)
 	}
 
-	log(ctx, "requirements.txt found, installing the packages to an isolated environment...")
-	depsPath, err := l.requirementsInstaller.EnsureVirtualEnvExists(ctx, execCtx.repo, execCtx.requirementsTxt)
+	depsPath, release, err := l.requirementsInstaller.EnsureVirtualEnvExists(ctx, execCtx.repo, execCtx.requirementsTxt)
 	if err != nil {
 		return err
 	}
@@ -62,15 +62,38 @@ func (l *localPythonRunner) Run(ctx context.Context, execCtx *executionContext)
 		return l.cmd.Run(ctx, execCtx.repo, noDependencyCommand)
 	}
 
-	log(ctx, "asset dependencies are successfully installed, starting execCtx...")
-	fullCommand := fmt.Sprintf("source %s/bin/activate && echo 'activated virtualenv' && pip3 install -r %s --quiet --quiet && echo 'installed all the dependencies' && python3 -u -m %s", depsPath, execCtx.requirementsTxt, execCtx.module)
+	// the virtualenv cannot be rebuilt or pruned while the script is running in it
+	defer release()
+
 	return l.cmd.Run(ctx, execCtx.repo, &command{
-		Name:    "/bin/sh",
-		Args:    []string{"-c", fullCommand},
-		EnvVars: execCtx.envVariables,
+		Name:    filepath.Join(depsPath, "bin", "python3"),
+		Args:    noDependencyCommand.Args,
+		EnvVars: activatedEnv(depsPath, execCtx.envVariables),
 	})
 }
 
+// activatedEnv returns the variables of the asset with the virtualenv activated, the same way the activate script does,
+// so that the executables installed in the virtualenv are found by the script and its subprocesses.
+func activatedEnv(venvPath string, envVariables map[string]string) map[string]string {
+	env := make(map[string]string, len(envVariables)+2)
+	for k, v := range envVariables {
+		env[k] = v
+	}
+
+	path, ok := env["PATH"]
+	if !ok {
+		path = os.Getenv("PATH")
+	}
+
+	env["PATH"] = filepath.Join(venvPath, "bin")
+	if path != "" {
+		env["PATH"] += string(os.PathListSeparator) + path
+	}
+	env["VIRTUAL_ENV"] = venvPath
+
+	return env
+}
+
 type commandRunner struct{}
 
 type command struct {
@@ -80,7 +103,8 @@ type command struct {
 }
 
 func (l *commandRunner) Run(ctx context.Context, repo *git.Repo, command *command) error {
-	cmd := exec.Command(command.Name, command.Args...) //nolint:gosec
+	cmd := exec.CommandContext(ctx, command.Name, command.Args...) //nolint:gosec
+	killProcessGroupOnCancel(cmd)
 	cmd.Dir = repo.Path
 	cmd.Env = make([]string, len(command.EnvVars))
 	for k, v := range command.EnvVars {
@@ -113,6 +137,10 @@ func (l *commandRunner) Run(ctx context.Context, repo *git.Repo, command *comman
 
 	res := cmd.Wait()
 	if res != nil {
+		if ctx.Err() != nil {
+			return errors.Wrap(ctx.Err(), "the process is killed")
+		}
+
 		return res
 	}
 
@@ -2,7 +2,10 @@ package python
 
 import (
 	"context"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/datablast-analytics/blast/pkg/git"
 	"github.com/stretchr/testify/assert"
@@ -13,9 +16,13 @@ type mockReqInstaller struct {
 	mock.Mock
 }
 
-func (m *mockReqInstaller) EnsureVirtualEnvExists(ctx context.Context, repo *git.Repo, requirementsTxt string) (string, error) {
+func (m *mockReqInstaller) EnsureVirtualEnvExists(ctx context.Context, repo *git.Repo, requirementsTxt string) (string, func(), error) {
 	called := m.Called(ctx, repo, requirementsTxt)
-	return called.String(0), called.Error(1)
+	if called.String(0) == "" {
+		return "", nil, called.Error(1)
+	}
+
+	return called.String(0), func() { m.MethodCalled("release") }, called.Error(1)
 }
 
 func Test_localPythonRunner_Run(t *testing.T) {
@@ -121,18 +128,18 @@ func Test_localPythonRunner_Run(t *testing.T) {
 			wantErr: assert.NoError,
 		},
 		{
-			name: "if venv path is found then it should be sourced, error is propagated",
+			name: "if venv path is found then its interpreter should be used, error is propagated",
 			fields: func() *fields {
 				reqs := new(mockReqInstaller)
 				reqs.On("EnsureVirtualEnvExists", mock.Anything, repo, requirementsTxt).
 					Return(venvPath, nil)
-
-				expectedCommand := "source /path/to/venv/bin/activate && echo 'activated virtualenv' && pip3 install -r /path/to/requirements.txt --quiet --quiet && echo 'installed all the dependencies' && python3 -u -m path.to.module"
+				reqs.On("release").Return().Once()
 
 				cmd := new(mockCmd)
 				cmd.On("Run", mock.Anything, repo, &command{
-					Name: "/bin/sh",
-					Args: []string{"-c", expectedCommand},
+					Name:    "/path/to/venv/bin/python3",
+					Args:    []string{"-u", "-m", module},
+					EnvVars: activatedEnv(venvPath, nil),
 				}).Return(assert.AnError)
 
 				return &fields{
@@ -144,18 +151,18 @@ func Test_localPythonRunner_Run(t *testing.T) {
 			wantErr: assert.Error,
 		},
 		{
-			name: "if venv path is found then it should be sourced, no error",
+			name: "if venv path is found then its interpreter should be used, no error",
 			fields: func() *fields {
 				reqs := new(mockReqInstaller)
 				reqs.On("EnsureVirtualEnvExists", mock.Anything, repo, requirementsTxt).
 					Return(venvPath, nil)
-
-				expectedCommand := "source /path/to/venv/bin/activate && echo 'activated virtualenv' && pip3 install -r /path/to/requirements.txt --quiet --quiet && echo 'installed all the dependencies' && python3 -u -m path.to.module"
+				reqs.On("release").Return().Once()
 
 				cmd := new(mockCmd)
 				cmd.On("Run", mock.Anything, repo, &command{
-					Name: "/bin/sh",
-					Args: []string{"-c", expectedCommand},
+					Name:    "/path/to/venv/bin/python3",
+					Args:    []string{"-u", "-m", module},
+					EnvVars: activatedEnv(venvPath, nil),
 				}).Return(nil)
 
 				return &fields{
@@ -178,6 +185,40 @@ func Test_localPythonRunner_Run(t *testing.T) {
 				requirementsInstaller: f.requirementsInstaller,
 			}
 			tt.wantErr(t, l.Run(context.Background(), tt.execCtx))
+			if reqs, ok := f.requirementsInstaller.(*mockReqInstaller); ok {
+				reqs.AssertExpectations(t)
+			}
 		})
 	}
 }
+
+func Test_activatedEnv(t *testing.T) {
+	t.Parallel()
+
+	env := activatedEnv("/path/to/venv", map[string]string{"PATH": "/usr/bin", "KEY": "value"})
+	assert.Equal(t, map[string]string{
+		"PATH":        "/path/to/venv/bin" + string(os.PathListSeparator) + "/usr/bin",
+		"VIRTUAL_ENV": "/path/to/venv",
+		"KEY":         "value",
+	}, env)
+
+	env = activatedEnv("/path/to/venv", nil)
+	assert.Equal(t, "/path/to/venv", env["VIRTUAL_ENV"])
+	assert.True(t, strings.HasPrefix(env["PATH"], "/path/to/venv/bin"))
+}
+
+func Test_commandRunner_Run_KillsProcessesWhenContextIsCancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := (&commandRunner{}).Run(ctx, &git.Repo{Path: t.TempDir()}, &command{
+		Name: "/bin/sh",
+		Args: []string{"-c", "sleep 30 && echo 'done'"},
+	})
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 10*time.Second)
+}
@@ -0,0 +1,167 @@
+package python
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+const (
+	// lockFileSuffix is appended to the path of the virtualenv for its lock file, the lock file stays next to the
+	// virtualenv so that it can be locked before the virtualenv exists.
+	lockFileSuffix = ".lock"
+
+	lockRetryInterval = 100 * time.Millisecond
+)
+
+var errVirtualEnvLocked = errors.New("the virtualenv is locked")
+
+// processLocks guard the virtualenvs within the process, every run creates its own installer while the file locks are
+// shared by all the processes. The installers lock a virtualenv exclusively, while the assets that run in it share it.
+var processLocks = struct {
+	sync.Mutex
+	locks map[string]*sync.RWMutex
+}{locks: make(map[string]*sync.RWMutex)}
+
+func processLock(venvPath string) *sync.RWMutex {
+	processLocks.Lock()
+	defer processLocks.Unlock()
+
+	if _, ok := processLocks.locks[venvPath]; !ok {
+		processLocks.locks[venvPath] = &sync.RWMutex{}
+	}
+
+	return processLocks.locks[venvPath]
+}
+
+// lockVirtualEnv waits until the virtualenv is not used by anyone else, in this process or another one, and returns the
+// function that releases it.
+func lockVirtualEnv(ctx context.Context, fs afero.Fs, venvPath string) (func(), error) {
+	return acquireVirtualEnvLock(ctx, fs, venvPath, false, true)
+}
+
+// rLockVirtualEnv waits until the virtualenv is not locked exclusively, the virtualenv can be shared with the others
+// that only use it until the returned function is called.
+func rLockVirtualEnv(ctx context.Context, fs afero.Fs, venvPath string) (func(), error) {
+	return acquireVirtualEnvLock(ctx, fs, venvPath, true, true)
+}
+
+// tryLockVirtualEnv locks the virtualenv exclusively only if it is not used already, ok is false if it is in use.
+func tryLockVirtualEnv(fs afero.Fs, venvPath string) (unlock func(), ok bool, err error) {
+	unlock, err = acquireVirtualEnvLock(context.Background(), fs, venvPath, false, false)
+	if errors.Is(err, errVirtualEnvLocked) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return unlock, true, nil
+}
+
+func acquireVirtualEnvLock(ctx context.Context, fs afero.Fs, venvPath string, shared, wait bool) (func(), error) {
+	release, err := acquireProcessLock(ctx, venvPath, shared, wait)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		file, err := fs.OpenFile(venvPath+lockFileSuffix, os.O_CREATE|os.O_RDWR, 0o644)
+		if err != nil {
+			release()
+			return nil, errors.Wrap(err, "failed to open the lock file of the virtualenv")
+		}
+
+		// the other filesystems, e.g. the in-memory one, cannot be shared with other processes
+		osFile, ok := file.(*os.File)
+		if !ok {
+			_ = file.Close()
+			return release, nil
+		}
+
+		err = lockFileOrWait(ctx, osFile, shared, wait)
+		if err != nil {
+			_ = osFile.Close()
+			release()
+			return nil, err
+		}
+
+		// the lock file is removed together with the virtualenv, the lock is taken again if the file was removed while
+		// waiting for it
+		if !isCurrentFile(fs, osFile, venvPath+lockFileSuffix) {
+			_ = unlockFile(osFile)
+			_ = osFile.Close()
+			continue
+		}
+
+		return func() {
+			_ = unlockFile(osFile)
+			_ = osFile.Close()
+			release()
+		}, nil
+	}
+}
+
+func acquireProcessLock(ctx context.Context, venvPath string, shared, wait bool) (func(), error) {
+	lock := processLock(venvPath)
+	tryLock, unlock := lock.TryLock, lock.Unlock
+	if shared {
+		tryLock, unlock = lock.TryRLock, lock.RUnlock
+	}
+
+	for !tryLock() {
+		if !wait {
+			return nil, errVirtualEnvLocked
+		}
+
+		select {
+		case <-time.After(lockRetryInterval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return unlock, nil
+}
+
+func lockFileOrWait(ctx context.Context, file *os.File, shared, wait bool) error {
+	for {
+		locked, err := tryLockFile(file, shared)
+		if err != nil {
+			return errors.Wrap(err, "failed to lock the virtualenv")
+		}
+
+		if locked {
+			return nil
+		}
+
+		if !wait {
+			return errVirtualEnvLocked
+		}
+
+		select {
+		case <-time.After(lockRetryInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// isCurrentFile tells if the open file is still the one at the given path.
+func isCurrentFile(fs afero.Fs, file *os.File, path string) bool {
+	openInfo, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	currentInfo, err := fs.Stat(path)
+	if err != nil {
+		return false
+	}
+
+	return os.SameFile(openInfo, currentInfo)
+}
@@ -0,0 +1,28 @@
+//go:build !windows
+
+package python
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// tryLockFile locks the file without waiting, it reports false if another process holds a conflicting lock.
+func tryLockFile(file *os.File, shared bool) (bool, error) {
+	how := syscall.LOCK_EX
+	if shared {
+		how = syscall.LOCK_SH
+	}
+
+	err := syscall.Flock(int(file.Fd()), how|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+func unlockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,8 @@
+//go:build windows
+
+package python
+
+import "os/exec"
+
+// killProcessGroupOnCancel relies on the default behavior on Windows, which kills only the command itself.
+func killProcessGroupOnCancel(_ *exec.Cmd) {}
@@ -5,14 +5,26 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
-	"sync"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
 
 	"github.com/datablast-analytics/blast/pkg/git"
 	"github.com/datablast-analytics/blast/pkg/path"
+	"github.com/datablast-analytics/blast/pkg/user"
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
 )
 
+const (
+	// installedMarkerFile is written once the packages are installed, its modification time is the last use of the
+	// virtualenv.
+	installedMarkerFile = ".blast-installed"
+	// requirementsFile is the copy of the requirements the virtualenv is built from, so that it can be rebuilt later.
+	requirementsFile = "requirements.txt"
+)
+
 type configManager interface {
 	EnsureVirtualenvDirExists() error
 	MakeVirtualenvPath(dir string) string
@@ -22,49 +34,279 @@ type installReqsToHomeDir struct {
 	fs     afero.Fs
 	config configManager
 	cmd    cmd
-
-	lock sync.Mutex
 }
 
-func (i *installReqsToHomeDir) EnsureVirtualEnvExists(ctx context.Context, repo *git.Repo, requirementsTxt string) (string, error) {
+// EnsureVirtualEnvExists returns the virtualenv with the given requirements installed, the packages are installed only
+// once per virtualenv. The workers that need the same virtualenv wait for the one that installs it, even if they belong
+// to other runs or other processes. The virtualenv is not rebuilt or pruned until the returned function is called.
+func (i *installReqsToHomeDir) EnsureVirtualEnvExists(ctx context.Context, repo *git.Repo, requirementsTxt string) (string, func(), error) {
 	err := i.config.EnsureVirtualenvDirExists()
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
-	reqContent, err := afero.ReadFile(i.fs, requirementsTxt)
+	venvPath, requirements, err := i.virtualEnvPath(requirementsTxt)
+	if err != nil || venvPath == "" {
+		return "", nil, err
+	}
+
+	for {
+		release, err := rLockVirtualEnv(ctx, i.fs, venvPath)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if isInstalled(i.fs, venvPath) {
+			now := time.Now()
+			err = i.fs.Chtimes(filepath.Join(venvPath, installedMarkerFile), now, now)
+			if err != nil {
+				release()
+				return "", nil, errors.Wrap(err, "failed to update the last use of the virtualenv")
+			}
+
+			return venvPath, release, nil
+		}
+
+		// the packages are installed with the virtualenv locked exclusively, it is used once they are installed
+		release()
+		err = i.installIfMissing(ctx, repo, venvPath, requirements)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+}
+
+func (i *installReqsToHomeDir) installIfMissing(ctx context.Context, repo *git.Repo, venvPath string, requirements []byte) error {
+	unlock, err := lockVirtualEnv(ctx, i.fs, venvPath)
 	if err != nil {
-		return "", errors.Wrap(err, "failed to read requirements.txt")
+		return err
 	}
+	defer unlock()
 
-	if len(reqContent) == 0 {
-		return "", nil
+	if isInstalled(i.fs, venvPath) {
+		return nil
+	}
+
+	log(ctx, "installing the packages in requirements.txt to an isolated environment...")
+	return i.install(ctx, repo, venvPath, requirements)
+}
+
+// install builds the virtualenv from scratch, the leftovers of an interrupted installation are removed beforehand.
+func (i *installReqsToHomeDir) install(ctx context.Context, repo *git.Repo, venvPath string, requirements []byte) error {
+	err := i.fs.RemoveAll(venvPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to remove the incomplete virtualenv")
+	}
+
+	err = i.cmd.Run(ctx, repo, &command{
+		Name: "python3",
+		Args: []string{"-m", "venv", venvPath},
+	})
+	if err != nil {
+		return err
+	}
+
+	requirementsPath := filepath.Join(venvPath, requirementsFile)
+	err = afero.WriteFile(i.fs, requirementsPath, requirements, 0o644)
+	if err != nil {
+		return errors.Wrap(err, "failed to copy the requirements to the virtualenv")
+	}
+
+	err = i.cmd.Run(ctx, repo, &command{
+		Name: filepath.Join(venvPath, "bin", "pip3"),
+		Args: []string{"install", "-r", requirementsPath, "--quiet", "--quiet"},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to install the requirements")
+	}
+
+	err = afero.WriteFile(i.fs, filepath.Join(venvPath, installedMarkerFile), []byte{}, 0o644)
+	if err != nil {
+		return errors.Wrap(err, "failed to mark the virtualenv as installed")
+	}
+
+	return nil
+}
+
+// virtualEnvPath returns the path of the virtualenv for the given requirements without creating it, the virtualenvs are
+// shared between the assets with the same requirements. An empty path means there is nothing to install.
+func (i *installReqsToHomeDir) virtualEnvPath(requirementsTxt string) (string, []byte, error) {
+	reqContent, err := afero.ReadFile(i.fs, requirementsTxt)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to read requirements.txt")
 	}
 
 	cleanContent := bytes.TrimSpace(reqContent)
 	if len(cleanContent) == 0 {
-		return "", nil
+		return "", nil, nil
 	}
 
 	sum := sha256.Sum256(cleanContent)
-	venvPath := i.config.MakeVirtualenvPath(hex.EncodeToString(sum[:]))
+	return i.config.MakeVirtualenvPath(hex.EncodeToString(sum[:])), cleanContent, nil
+}
+
+func isInstalled(fs afero.Fs, venvPath string) bool {
+	exists, err := afero.Exists(fs, filepath.Join(venvPath, installedMarkerFile))
+	return err == nil && exists
+}
+
+type VirtualEnv struct {
+	Name      string
+	Path      string
+	Installed bool
+	LastUsed  time.Time
+}
 
-	i.lock.Lock()
-	defer i.lock.Unlock()
+type virtualEnvDirManager interface {
+	configManager
+	VirtualenvDir() string
+}
+
+// VirtualEnvManager manages the virtualenvs of the Python assets under the blast home directory.
+type VirtualEnvManager struct {
+	fs        afero.Fs
+	config    virtualEnvDirManager
+	installer *installReqsToHomeDir
+}
 
-	reqsPathExists := path.DirExists(i.fs, venvPath)
-	if reqsPathExists {
-		return venvPath, nil
+func NewVirtualEnvManager(fs afero.Fs) *VirtualEnvManager {
+	config := user.NewConfigManager(fs)
+
+	return &VirtualEnvManager{
+		fs:     fs,
+		config: config,
+		installer: &installReqsToHomeDir{
+			fs:     fs,
+			config: config,
+			cmd:    &commandRunner{},
+		},
 	}
+}
 
-	err = i.cmd.Run(ctx, repo, &command{
-		Name: "python3",
-		Args: []string{"-m", "venv", venvPath},
+// List returns the virtualenvs starting from the most recently used one, the incomplete installations are listed with
+// the time they were started at.
+func (m *VirtualEnvManager) List() ([]*VirtualEnv, error) {
+	err := m.config.EnsureVirtualenvDirExists()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := afero.ReadDir(m.fs, m.config.VirtualenvDir())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the virtualenvs directory")
+	}
+
+	venvs := make([]*VirtualEnv, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		venv := &VirtualEnv{
+			Name:     entry.Name(),
+			Path:     m.config.MakeVirtualenvPath(entry.Name()),
+			LastUsed: entry.ModTime(),
+		}
+
+		marker, err := m.fs.Stat(filepath.Join(venv.Path, installedMarkerFile))
+		if err == nil {
+			venv.Installed = true
+			venv.LastUsed = marker.ModTime()
+		}
+
+		venvs = append(venvs, venv)
+	}
+
+	sort.SliceStable(venvs, func(i, j int) bool {
+		return venvs[i].LastUsed.After(venvs[j].LastUsed)
 	})
 
+	return venvs, nil
+}
+
+// Rebuild removes the virtualenv and installs the requirements it was built from again, the virtualenvs that are in use
+// by an installer or a running asset are not rebuilt.
+func (m *VirtualEnvManager) Rebuild(ctx context.Context, name string) error {
+	err := m.config.EnsureVirtualenvDirExists()
+	if err != nil {
+		return err
+	}
+
+	venvPath := m.config.MakeVirtualenvPath(name)
+	if name == "" || filepath.Base(venvPath) != name || !path.DirExists(m.fs, venvPath) {
+		return errors.Errorf("there is no virtualenv with the name '%s'", name)
+	}
+
+	requirements, err := afero.ReadFile(m.fs, filepath.Join(venvPath, requirementsFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return errors.Errorf("the requirements of the virtualenv '%s' are not known, prune it instead and it will be rebuilt on its next use", name)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to read the requirements of the virtualenv")
+	}
+
+	unlock, ok, err := tryLockVirtualEnv(m.fs, venvPath)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.Errorf("the virtualenv '%s' is in use, please try again once it is not", name)
+	}
+	defer unlock()
+
+	return m.installer.install(ctx, &git.Repo{Path: m.config.VirtualenvDir()}, venvPath, requirements)
+}
+
+// Prune removes the virtualenvs that are not used since the given time together with their lock files and returns
+// them, the virtualenvs that are in use by an installer or a running asset are skipped.
+func (m *VirtualEnvManager) Prune(unusedSince time.Time) ([]*VirtualEnv, error) {
+	venvs, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+
+	pruned := make([]*VirtualEnv, 0)
+	for _, venv := range venvs {
+		if !venv.LastUsed.Before(unusedSince) {
+			continue
+		}
+
+		removed, err := m.removeIfUnused(venv, unusedSince)
+		if err != nil {
+			return pruned, err
+		}
+
+		if removed {
+			pruned = append(pruned, venv)
+		}
+	}
+
+	return pruned, nil
+}
+
+// removeIfUnused removes the virtualenv unless it is locked or it has been used after it was listed.
+func (m *VirtualEnvManager) removeIfUnused(venv *VirtualEnv, unusedSince time.Time) (bool, error) {
+	unlock, ok, err := tryLockVirtualEnv(m.fs, venv.Path)
+	if err != nil || !ok {
+		return false, err
+	}
+	defer unlock()
+
+	marker, err := m.fs.Stat(filepath.Join(venv.Path, installedMarkerFile))
+	if err == nil && !marker.ModTime().Before(unusedSince) {
+		return false, nil
+	}
+
+	err = m.fs.RemoveAll(venv.Path)
 	if err != nil {
-		return "", err
+		return false, errors.Wrapf(err, "failed to remove the virtualenv '%s'", venv.Name)
+	}
+
+	// the ones waiting for the removed lock file lock the new one instead
+	err = m.fs.Remove(venv.Path + lockFileSuffix)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, errors.Wrapf(err, "failed to remove the lock file of the virtualenv '%s'", venv.Name)
 	}
 
-	return venvPath, nil
+	return true, nil
 }
@@ -2,7 +2,12 @@ package python
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/datablast-analytics/blast/pkg/git"
 	"github.com/spf13/afero"
@@ -23,6 +28,22 @@ func (m *mockConfigManager) MakeVirtualenvPath(dir string) string {
 	return m.Called(dir).String(0)
 }
 
+type fakeVirtualenvDir struct {
+	dir string
+}
+
+func (f fakeVirtualenvDir) EnsureVirtualenvDirExists() error {
+	return nil
+}
+
+func (f fakeVirtualenvDir) MakeVirtualenvPath(dir string) string {
+	return filepath.Join(f.dir, dir)
+}
+
+func (f fakeVirtualenvDir) VirtualenvDir() string {
+	return f.dir
+}
+
 type mockCmd struct {
 	mock.Mock
 }
@@ -128,7 +149,7 @@ func Test_installReqsToHomeDir_EnsureVirtualEnvExists(t *testing.T) {
 			wantErr: assert.NoError,
 		},
 		{
-			name: "should skip creating a venv if the venv already exists",
+			name: "should skip the installation if the venv is already installed",
 			fields: func() *fields {
 				fs := afero.NewMemMapFs()
 
@@ -140,7 +161,7 @@ func Test_installReqsToHomeDir_EnsureVirtualEnvExists(t *testing.T) {
 
 				createRequirementsFile(fs, "req1\nreq2")
 
-				require.NoError(t, fs.MkdirAll("/path/to/venv", 0o755))
+				require.NoError(t, afero.WriteFile(fs, "/path/to/venv/"+installedMarkerFile, []byte{}, 0o644))
 				return &fields{
 					fs:     fs,
 					config: config,
@@ -180,15 +201,12 @@ func Test_installReqsToHomeDir_EnsureVirtualEnvExists(t *testing.T) {
 			wantErr: assert.Error,
 		},
 		{
-			name: "the venv is created successfully",
+			name: "should return error if the requirements cannot be installed",
 			fields: func() *fields {
 				fs := afero.NewMemMapFs()
 
 				config := new(mockConfigManager)
 				config.On("EnsureVirtualenvDirExists").Return(nil)
-
-				// this returns a path that doesn't exist in the filesystem,
-				// that's how this test is validating that the venv is created
 				config.On("MakeVirtualenvPath", fileHash).
 					Return("/path/to/venv")
 
@@ -198,6 +216,10 @@ func Test_installReqsToHomeDir_EnsureVirtualEnvExists(t *testing.T) {
 				fakeCmd.On("Run", mock.Anything, repo, &command{
 					Name: "python3",
 					Args: []string{"-m", "venv", "/path/to/venv"},
+				}).Return(nil)
+				fakeCmd.On("Run", mock.Anything, repo, &command{
+					Name: "/path/to/venv/bin/pip3",
+					Args: []string{"install", "-r", "/path/to/venv/requirements.txt", "--quiet", "--quiet"},
 				}).Return(assert.AnError)
 
 				return &fields{
@@ -208,6 +230,40 @@ func Test_installReqsToHomeDir_EnsureVirtualEnvExists(t *testing.T) {
 			},
 			wantErr: assert.Error,
 		},
+		{
+			name: "the venv is created and the requirements are installed",
+			fields: func() *fields {
+				fs := afero.NewMemMapFs()
+
+				config := new(mockConfigManager)
+				config.On("EnsureVirtualenvDirExists").Return(nil)
+				config.On("MakeVirtualenvPath", fileHash).
+					Return("/path/to/venv")
+
+				createRequirementsFile(fs, validReqsContent)
+
+				// the leftovers of an interrupted installation are removed before the venv is created again
+				require.NoError(t, afero.WriteFile(fs, "/path/to/venv/leftover", []byte{}, 0o644))
+
+				fakeCmd := new(mockCmd)
+				fakeCmd.On("Run", mock.Anything, repo, &command{
+					Name: "python3",
+					Args: []string{"-m", "venv", "/path/to/venv"},
+				}).Return(nil).Once()
+				fakeCmd.On("Run", mock.Anything, repo, &command{
+					Name: "/path/to/venv/bin/pip3",
+					Args: []string{"install", "-r", "/path/to/venv/requirements.txt", "--quiet", "--quiet"},
+				}).Return(nil).Once()
+
+				return &fields{
+					fs:     fs,
+					config: config,
+					cmd:    fakeCmd,
+				}
+			},
+			want:    "/path/to/venv",
+			wantErr: assert.NoError,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -221,9 +277,277 @@ func Test_installReqsToHomeDir_EnsureVirtualEnvExists(t *testing.T) {
 				cmd:    f.cmd,
 			}
 
-			got, err := i.EnsureVirtualEnvExists(context.Background(), repo, requirementsTxt)
+			got, release, err := i.EnsureVirtualEnvExists(context.Background(), repo, requirementsTxt)
 			tt.wantErr(t, err)
+			if release != nil {
+				release()
+			}
 			assert.Equal(t, tt.want, got)
+
+			if got != "" {
+				assert.True(t, isInstalled(f.fs, got))
+
+				leftoverExists, err := afero.Exists(f.fs, "/path/to/venv/leftover")
+				require.NoError(t, err)
+				assert.False(t, leftoverExists)
+			}
 		})
 	}
 }
+
+func Test_installReqsToHomeDir_InstallsOncePerVenv(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/path1/requirements.txt", []byte("req1\nreq2"), 0o644))
+
+	config := new(mockConfigManager)
+	config.On("EnsureVirtualenvDirExists").Return(nil)
+	config.On("MakeVirtualenvPath", mock.Anything).Return("/path/to/venv")
+
+	fakeCmd := new(mockCmd)
+	fakeCmd.On("Run", mock.Anything, mock.Anything, &command{
+		Name: "python3",
+		Args: []string{"-m", "venv", "/path/to/venv"},
+	}).Return(nil).Once()
+	fakeCmd.On("Run", mock.Anything, mock.Anything, &command{
+		Name: "/path/to/venv/bin/pip3",
+		Args: []string{"install", "-r", "/path/to/venv/requirements.txt", "--quiet", "--quiet"},
+	}).Return(nil).Once()
+
+	i := &installReqsToHomeDir{fs: fs, config: config, cmd: fakeCmd}
+
+	var wg sync.WaitGroup
+	for w := 0; w < 5; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			got, release, err := i.EnsureVirtualEnvExists(context.Background(), &git.Repo{}, "/path1/requirements.txt")
+			assert.NoError(t, err)
+			assert.Equal(t, "/path/to/venv", got)
+			release()
+		}()
+	}
+	wg.Wait()
+
+	fakeCmd.AssertExpectations(t)
+}
+
+func newTestVirtualEnvManager(t *testing.T, cmd cmd) (*VirtualEnvManager, afero.Fs) {
+	fs := afero.NewMemMapFs()
+	config := fakeVirtualenvDir{dir: "/venvs"}
+
+	// the used venv has its requirements, the unused one is from before the requirements were copied
+	require.NoError(t, afero.WriteFile(fs, "/venvs/used/requirements.txt", []byte("req1"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/venvs/used/"+installedMarkerFile, []byte{}, 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/venvs/unused/"+installedMarkerFile, []byte{}, 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/venvs/incomplete/requirements.txt", []byte("req2"), 0o644))
+
+	now := time.Now()
+	require.NoError(t, fs.Chtimes("/venvs/used/"+installedMarkerFile, now, now))
+	require.NoError(t, fs.Chtimes("/venvs/unused/"+installedMarkerFile, now.Add(-60*24*time.Hour), now.Add(-60*24*time.Hour)))
+	require.NoError(t, fs.Chtimes("/venvs/incomplete", now.Add(-time.Hour), now.Add(-time.Hour)))
+
+	return &VirtualEnvManager{
+		fs:        fs,
+		config:    config,
+		installer: &installReqsToHomeDir{fs: fs, config: config, cmd: cmd},
+	}, fs
+}
+
+func TestVirtualEnvManager_ListAndPrune(t *testing.T) {
+	t.Parallel()
+
+	m, fs := newTestVirtualEnvManager(t, new(mockCmd))
+
+	venvs, err := m.List()
+	require.NoError(t, err)
+	require.Len(t, venvs, 3)
+	assert.Equal(t, "used", venvs[0].Name)
+	assert.True(t, venvs[0].Installed)
+	assert.Equal(t, "incomplete", venvs[1].Name)
+	assert.False(t, venvs[1].Installed)
+	assert.Equal(t, "unused", venvs[2].Name)
+	assert.Equal(t, "/venvs/unused", venvs[2].Path)
+
+	pruned, err := m.Prune(time.Now().Add(-30 * 24 * time.Hour))
+	require.NoError(t, err)
+	require.Len(t, pruned, 1)
+	assert.Equal(t, "unused", pruned[0].Name)
+
+	exists, err := afero.DirExists(fs, "/venvs/unused")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	venvs, err = m.List()
+	require.NoError(t, err)
+	assert.Len(t, venvs, 2)
+}
+
+func TestVirtualEnvManager_Rebuild(t *testing.T) {
+	t.Parallel()
+
+	fakeCmd := new(mockCmd)
+	fakeCmd.On("Run", mock.Anything, &git.Repo{Path: "/venvs"}, &command{
+		Name: "python3",
+		Args: []string{"-m", "venv", "/venvs/incomplete"},
+	}).Return(nil).Once()
+	fakeCmd.On("Run", mock.Anything, &git.Repo{Path: "/venvs"}, &command{
+		Name: "/venvs/incomplete/bin/pip3",
+		Args: []string{"install", "-r", "/venvs/incomplete/requirements.txt", "--quiet", "--quiet"},
+	}).Return(nil).Once()
+
+	m, fs := newTestVirtualEnvManager(t, fakeCmd)
+
+	require.NoError(t, m.Rebuild(context.Background(), "incomplete"))
+	fakeCmd.AssertExpectations(t)
+	assert.True(t, isInstalled(fs, "/venvs/incomplete"))
+
+	requirements, err := afero.ReadFile(fs, "/venvs/incomplete/requirements.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "req2", string(requirements))
+
+	assert.EqualError(t, m.Rebuild(context.Background(), "missing"), "there is no virtualenv with the name 'missing'")
+	assert.EqualError(t, m.Rebuild(context.Background(), "../venvs"), "there is no virtualenv with the name '../venvs'")
+	assert.EqualError(t, m.Rebuild(context.Background(), "unused"), "the requirements of the virtualenv 'unused' are not known, prune it instead and it will be rebuilt on its next use")
+}
+
+func Test_installReqsToHomeDir_InstallersShareTheLock(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewOsFs()
+	config := fakeVirtualenvDir{dir: t.TempDir()}
+	requirementsTxt := filepath.Join(t.TempDir(), "requirements.txt")
+	require.NoError(t, afero.WriteFile(fs, requirementsTxt, []byte("req1\nreq2"), 0o644))
+
+	venvPath, _, err := (&installReqsToHomeDir{fs: fs, config: config}).virtualEnvPath(requirementsTxt)
+	require.NoError(t, err)
+
+	fakeCmd := new(mockCmd)
+	fakeCmd.On("Run", mock.Anything, mock.Anything, &command{
+		Name: "python3",
+		Args: []string{"-m", "venv", venvPath},
+	}).Run(func(args mock.Arguments) {
+		// give the other installers the time to find the venv incomplete if they are not waiting for the lock
+		time.Sleep(50 * time.Millisecond)
+		assert.NoError(t, fs.MkdirAll(venvPath, 0o755))
+	}).Return(nil).Once()
+	fakeCmd.On("Run", mock.Anything, mock.Anything, &command{
+		Name: filepath.Join(venvPath, "bin", "pip3"),
+		Args: []string{"install", "-r", filepath.Join(venvPath, requirementsFile), "--quiet", "--quiet"},
+	}).Return(nil).Once()
+
+	// every run creates its own installer, they must not install the same venv at the same time
+	installers := []*installReqsToHomeDir{
+		{fs: fs, config: config, cmd: fakeCmd},
+		{fs: fs, config: config, cmd: fakeCmd},
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < 6; w++ {
+		wg.Add(1)
+		go func(installer *installReqsToHomeDir) {
+			defer wg.Done()
+
+			got, release, err := installer.EnsureVirtualEnvExists(context.Background(), &git.Repo{}, requirementsTxt)
+			assert.NoError(t, err)
+			assert.Equal(t, venvPath, got)
+			release()
+		}(installers[w%len(installers)])
+	}
+	wg.Wait()
+
+	fakeCmd.AssertExpectations(t)
+	assert.True(t, isInstalled(fs, venvPath))
+}
+
+func TestVirtualEnvManager_SkipsLockedVirtualEnvs(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewOsFs()
+	config := fakeVirtualenvDir{dir: t.TempDir()}
+	m := &VirtualEnvManager{fs: fs, config: config, installer: &installReqsToHomeDir{fs: fs, config: config, cmd: new(mockCmd)}}
+
+	unusedAt := time.Now().Add(-60 * 24 * time.Hour)
+	for _, name := range []string{"locked", "unused"} {
+		venvPath := config.MakeVirtualenvPath(name)
+		require.NoError(t, fs.MkdirAll(venvPath, 0o755))
+		require.NoError(t, afero.WriteFile(fs, filepath.Join(venvPath, requirementsFile), []byte(name), 0o644))
+		require.NoError(t, afero.WriteFile(fs, filepath.Join(venvPath, installedMarkerFile), []byte{}, 0o644))
+		require.NoError(t, fs.Chtimes(filepath.Join(venvPath, installedMarkerFile), unusedAt, unusedAt))
+	}
+
+	// the lock file is shared through another file descriptor, the same way an asset running in another process would
+	// hold it
+	lockFile, err := os.OpenFile(config.MakeVirtualenvPath("locked")+lockFileSuffix, os.O_CREATE|os.O_RDWR, 0o644)
+	require.NoError(t, err)
+	defer lockFile.Close()
+
+	locked, err := tryLockFile(lockFile, true)
+	require.NoError(t, err)
+	require.True(t, locked)
+
+	assert.EqualError(t, m.Rebuild(context.Background(), "locked"), "the virtualenv 'locked' is in use, please try again once it is not")
+
+	pruned, err := m.Prune(time.Now().Add(-30 * 24 * time.Hour))
+	require.NoError(t, err)
+	require.Len(t, pruned, 1)
+	assert.Equal(t, "unused", pruned[0].Name)
+	assert.True(t, isInstalled(fs, config.MakeVirtualenvPath("locked")))
+
+	lockFileExists, err := afero.Exists(fs, config.MakeVirtualenvPath("unused")+lockFileSuffix)
+	require.NoError(t, err)
+	assert.False(t, lockFileExists)
+
+	venvs, err := m.List()
+	require.NoError(t, err)
+	require.Len(t, venvs, 1)
+	assert.Equal(t, "locked", venvs[0].Name)
+
+	// once the lock is released the virtualenv can be pruned
+	require.NoError(t, unlockFile(lockFile))
+	pruned, err = m.Prune(time.Now().Add(-30 * 24 * time.Hour))
+	require.NoError(t, err)
+	require.Len(t, pruned, 1)
+	assert.Equal(t, "locked", pruned[0].Name)
+}
+
+func TestVirtualEnvManager_SkipsVirtualEnvsOfRunningAssets(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewOsFs()
+	config := fakeVirtualenvDir{dir: t.TempDir()}
+	installer := &installReqsToHomeDir{fs: fs, config: config, cmd: new(mockCmd)}
+	m := &VirtualEnvManager{fs: fs, config: config, installer: installer}
+
+	requirementsTxt := filepath.Join(t.TempDir(), "requirements.txt")
+	require.NoError(t, afero.WriteFile(fs, requirementsTxt, []byte("req1"), 0o644))
+
+	venvPath, requirements, err := installer.virtualEnvPath(requirementsTxt)
+	require.NoError(t, err)
+	require.NoError(t, fs.MkdirAll(venvPath, 0o755))
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(venvPath, requirementsFile), requirements, 0o644))
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(venvPath, installedMarkerFile), []byte{}, 0o644))
+
+	// the asset keeps the virtualenv until it finishes running
+	got, release, err := installer.EnsureVirtualEnvExists(context.Background(), &git.Repo{}, requirementsTxt)
+	require.NoError(t, err)
+	require.Equal(t, venvPath, got)
+
+	assert.EqualError(t, m.Rebuild(context.Background(), filepath.Base(venvPath)), fmt.Sprintf("the virtualenv '%s' is in use, please try again once it is not", filepath.Base(venvPath)))
+
+	pruned, err := m.Prune(time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, pruned)
+	assert.True(t, isInstalled(fs, venvPath))
+
+	release()
+	pruned, err = m.Prune(time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, pruned, 1)
+
+	exists, err := afero.Exists(fs, venvPath+lockFileSuffix)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
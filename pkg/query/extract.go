@@ -75,12 +75,7 @@ func splitQueries(fileContent string) []*Query {
 	queries := make([]*Query, 0)
 	var sqlVariablesSeenSoFar []string
 
-	for _, query := range strings.Split(fileContent, ";") {
-		query = strings.TrimSpace(query)
-		if len(query) == 0 {
-			continue
-		}
-
+	for _, query := range SplitStatements(fileContent) {
 		queryLines := strings.Split(query, "\n")
 		cleanQueryRows := make([]string, 0, len(queryLines))
 		for _, line := range queryLines {
@@ -112,6 +107,86 @@ func splitQueries(fileContent string) []*Query {
 	return queries
 }
 
+// SplitStatements splits the given SQL into its statements. The semicolons in string literals, quoted identifiers,
+// dollar-quoted blocks and comments do not end a statement, and the comments are left out of the statements.
+func SplitStatements(content string) []string {
+	statements := make([]string, 0)
+	var current strings.Builder
+
+	addStatement := func() {
+		statement := strings.TrimSpace(current.String())
+		if statement != "" {
+			statements = append(statements, statement)
+		}
+		current.Reset()
+	}
+
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case c == ';':
+			addStatement()
+		case c == '-' && strings.HasPrefix(content[i:], "--"):
+			end := strings.IndexByte(content[i:], '\n')
+			if end == -1 {
+				i = len(content)
+				continue
+			}
+			i += end
+			current.WriteByte('\n')
+		case c == '/' && strings.HasPrefix(content[i:], "/*"):
+			end := strings.Index(content[i+2:], "*/")
+			if end == -1 {
+				i = len(content)
+				continue
+			}
+			i += end + 3
+			current.WriteByte(' ')
+		case c == '$' && strings.HasPrefix(content[i:], "$$"):
+			end := strings.Index(content[i+2:], "$$")
+			if end == -1 {
+				current.WriteString(content[i:])
+				i = len(content)
+				continue
+			}
+			current.WriteString(content[i : i+end+4])
+			i += end + 3
+		case c == '\'' || c == '"':
+			end := closingQuote(content, i)
+			current.WriteString(content[i:end])
+			i = end - 1
+		default:
+			current.WriteByte(c)
+		}
+	}
+	addStatement()
+
+	return statements
+}
+
+// closingQuote returns the index right after the quote that closes the one at start, the quotes are escaped either by
+// doubling them or, in string literals, with a backslash.
+func closingQuote(content string, start int) int {
+	quote := content[start]
+	for i := start + 1; i < len(content); i++ {
+		switch content[i] {
+		case '\\':
+			if quote == '\'' {
+				i++
+			}
+		case quote:
+			if i+1 < len(content) && content[i+1] == quote {
+				i++
+				continue
+			}
+
+			return i + 1
+		}
+	}
+
+	return len(content)
+}
+
 // WholeFileExtractor is a regular file extractor that returns the whole file content as the query string. It is useful
 // for cases where the whole file content can be treated as a single query, such as validating GoogleCloudPlatform queries via dry-run.
 type WholeFileExtractor struct {
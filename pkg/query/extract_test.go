@@ -194,6 +194,23 @@ set min_level_req = 22;
 				},
 			},
 		},
+		{
+			name: "semicolons in string literals do not split the queries",
+			path: "somefile.txt",
+			setupFilesystem: func(t *testing.T, fs afero.Fs) {
+				err := afero.WriteFile(fs, "somefile.txt", []byte("select 'a;b' as x from users; select name from countries;"), 0o644)
+				require.NoError(t, err)
+			},
+			setupRenderer: noOpRenderer,
+			want: []*Query{
+				{
+					Query: "select 'a;b' as x from users",
+				},
+				{
+					Query: "select name from countries",
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -228,6 +245,50 @@ set min_level_req = 22;
 	}
 }
 
+func TestSplitStatements(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "empty content",
+			content: " \n ;; \n",
+			want:    []string{},
+		},
+		{
+			name:    "simple statements",
+			content: "use database analytics;\nset x = 1;\nselect $x",
+			want:    []string{"use database analytics", "set x = 1", "select $x"},
+		},
+		{
+			name:    "semicolons in quotes",
+			content: `select 'a;b', 'it''s;', 'c\';d' as "col;1" from t; select 2;`,
+			want:    []string{`select 'a;b', 'it''s;', 'c\';d' as "col;1" from t`, "select 2"},
+		},
+		{
+			name:    "semicolons in dollar-quoted blocks",
+			content: "create function f() returns int as $$ select 1; $$; select 2",
+			want:    []string{"create function f() returns int as $$ select 1; $$", "select 2"},
+		},
+		{
+			name:    "comments are left out",
+			content: "-- first; comment\nselect 1; /* block; comment */ select 2;\n-- trailing comment",
+			want:    []string{"select 1", "select 2"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, SplitStatements(tt.content))
+		})
+	}
+}
+
 func TestWholeFileExtractor_ExtractQueriesFromFile(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,290 @@
+package report
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/datablast-analytics/blast/pkg/scheduler"
+	"github.com/pkg/errors"
+)
+
+const (
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusCancelled = "cancelled"
+)
+
+// Report is the machine-readable outcome of the runs of a single command, a backfill contains one run per interval.
+type Report struct {
+	Runs []*Run `json:"runs"`
+}
+
+type Run struct {
+	ID              string      `json:"id"`
+	Pipeline        string      `json:"pipeline"`
+	StartDate       time.Time   `json:"start_date"`
+	EndDate         time.Time   `json:"end_date"`
+	StartedAt       time.Time   `json:"started_at"`
+	DurationSeconds float64     `json:"duration_seconds"`
+	Status          string      `json:"status"`
+	Instances       []*Instance `json:"instances"`
+	Intervals       []*Run      `json:"intervals,omitempty"`
+}
+
+type Instance struct {
+	ID              string                       `json:"id"`
+	Asset           string                       `json:"asset"`
+	Type            string                       `json:"type"`
+	Status          scheduler.TaskInstanceStatus `json:"status"`
+	StartedAt       *time.Time                   `json:"started_at,omitempty"`
+	DurationSeconds float64                      `json:"duration_seconds"`
+	Attempts        int                          `json:"attempts,omitempty"`
+	Error           string                       `json:"error,omitempty"`
+	Warning         string                       `json:"warning,omitempty"`
+	FailedUpstreams []string                     `json:"failed_upstreams,omitempty"`
+}
+
+// NewRun builds the report of a single run from the final state of the scheduler. The instances that were not part of
+// the run, e.g. the ones that succeeded in a resumed run or the ones that were not selected, are left out.
+func NewRun(id, pipeline string, startDate, endDate, startedAt time.Time, duration time.Duration, instances []scheduler.TaskInstance, results []*scheduler.TaskExecutionResult) *Run {
+	resultsByID := make(map[string]*scheduler.TaskExecutionResult, len(results))
+	for _, result := range results {
+		resultsByID[result.Instance.GetHumanID()] = result
+	}
+
+	run := &Run{
+		ID:              id,
+		Pipeline:        pipeline,
+		StartDate:       startDate,
+		EndDate:         endDate,
+		StartedAt:       startedAt,
+		DurationSeconds: duration.Seconds(),
+		Status:          StatusSucceeded,
+		Instances:       make([]*Instance, 0),
+	}
+
+	for _, instance := range instances {
+		result, executed := resultsByID[instance.GetHumanID()]
+		status := instance.GetStatus()
+		if !executed && status != scheduler.UpstreamFailed && status != scheduler.Cancelled && status != scheduler.Skipped {
+			continue
+		}
+
+		record := &Instance{
+			ID:     instance.GetHumanID(),
+			Asset:  instance.GetAsset().Name,
+			Type:   instance.GetType().String(),
+			Status: status,
+		}
+
+		if executed && !result.StartedAt.IsZero() {
+			startedAt := result.StartedAt
+			record.StartedAt = &startedAt
+			record.DurationSeconds = result.FinishedAt.Sub(result.StartedAt).Seconds()
+			record.Attempts = result.Attempts
+		}
+
+		if executed && result.IsWarning() {
+			record.Warning = result.Error.Error()
+		} else if executed && result.Error != nil {
+			record.Error = result.Error.Error()
+		}
+
+		switch status { //nolint:exhaustive
+		case scheduler.Failed, scheduler.UpstreamFailed:
+			run.Status = StatusFailed
+		case scheduler.Cancelled:
+			if run.Status != StatusFailed {
+				run.Status = StatusCancelled
+			}
+		}
+
+		if status == scheduler.UpstreamFailed {
+			record.FailedUpstreams = failedUpstreams(instance)
+		}
+
+		run.Instances = append(run.Instances, record)
+	}
+
+	return run
+}
+
+// NewBackfill aggregates the runs of the backfilled intervals into a single run that covers all of them, the outcome of
+// every interval is kept in its intervals. The backfill fails if any of the intervals fails.
+func NewBackfill(pipeline string, intervals []*Run) *Run {
+	run := &Run{
+		Pipeline:  pipeline,
+		Status:    StatusSucceeded,
+		Instances: make([]*Instance, 0),
+		Intervals: intervals,
+	}
+
+	var finishedAt time.Time
+	for _, interval := range intervals {
+		if run.StartDate.IsZero() || interval.StartDate.Before(run.StartDate) {
+			run.StartDate = interval.StartDate
+		}
+		if interval.EndDate.After(run.EndDate) {
+			run.EndDate = interval.EndDate
+		}
+
+		if !interval.StartedAt.IsZero() {
+			if run.StartedAt.IsZero() || interval.StartedAt.Before(run.StartedAt) {
+				run.StartedAt = interval.StartedAt
+			}
+
+			intervalFinishedAt := interval.StartedAt.Add(time.Duration(interval.DurationSeconds * float64(time.Second)))
+			if intervalFinishedAt.After(finishedAt) {
+				finishedAt = intervalFinishedAt
+			}
+		}
+
+		switch interval.Status {
+		case StatusFailed:
+			run.Status = StatusFailed
+		case StatusCancelled:
+			if run.Status != StatusFailed {
+				run.Status = StatusCancelled
+			}
+		}
+	}
+
+	if !run.StartedAt.IsZero() {
+		run.DurationSeconds = finishedAt.Sub(run.StartedAt).Seconds()
+	}
+
+	return run
+}
+
+// failedUpstreams returns the failed instances that caused the given instance to be skipped.
+func failedUpstreams(instance scheduler.TaskInstance) []string {
+	seen := make(map[string]bool)
+	failed := make([]string, 0)
+
+	var walk func(instance scheduler.TaskInstance)
+	walk = func(instance scheduler.TaskInstance) {
+		for _, upstream := range instance.GetUpstream() {
+			if seen[upstream.GetHumanID()] {
+				continue
+			}
+			seen[upstream.GetHumanID()] = true
+
+			switch upstream.GetStatus() { //nolint:exhaustive
+			case scheduler.Failed:
+				failed = append(failed, upstream.GetHumanID())
+			case scheduler.UpstreamFailed:
+				walk(upstream)
+			}
+		}
+	}
+	walk(instance)
+
+	sort.Strings(failed)
+	return failed
+}
+
+func WriteJSON(w io.Writer, r *Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return errors.Wrap(encoder.Encode(r), "failed to write the JSON report")
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
+
+// WriteJUnit writes every run as a test suite and every instance as a test case, the instances that did not run due
+// to their upstreams failing, the run being cancelled or their schedules are reported as skipped.
+func WriteJUnit(w io.Writer, r *Report) error {
+	suites := junitTestSuites{Suites: make([]junitTestSuite, 0, len(r.Runs))}
+	for _, run := range r.Runs {
+		suite := junitTestSuite{
+			Name:      fmt.Sprintf("%s (%s - %s)", run.Pipeline, run.StartDate.Format(time.RFC3339), run.EndDate.Format(time.RFC3339)),
+			Tests:     len(run.Instances),
+			Time:      formatSeconds(run.DurationSeconds),
+			Timestamp: run.StartedAt.Format(time.RFC3339),
+			Cases:     make([]junitTestCase, 0, len(run.Instances)),
+		}
+
+		for _, instance := range run.Instances {
+			testCase := junitTestCase{
+				Name:      instance.ID,
+				ClassName: fmt.Sprintf("%s.%s", run.Pipeline, instance.Type),
+				Time:      formatSeconds(instance.DurationSeconds),
+			}
+
+			// JUnit has no notion of warnings, they are kept in the output of the passing test cases
+			if instance.Warning != "" {
+				testCase.SystemOut = "warning: " + instance.Warning
+			}
+
+			switch instance.Status { //nolint:exhaustive
+			case scheduler.Failed:
+				suite.Failures++
+				testCase.Failure = &junitMessage{Message: "the instance has failed", Content: instance.Error}
+			case scheduler.UpstreamFailed:
+				suite.Skipped++
+				testCase.Skipped = &junitMessage{Message: fmt.Sprintf("the upstream instances have failed: %v", instance.FailedUpstreams)}
+			case scheduler.Cancelled:
+				suite.Skipped++
+				testCase.Skipped = &junitMessage{Message: "the run is cancelled", Content: instance.Error}
+			case scheduler.Skipped:
+				suite.Skipped++
+				testCase.Skipped = &junitMessage{Message: "the asset is not scheduled for the day"}
+			}
+
+			suite.Cases = append(suite.Cases, testCase)
+		}
+
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return errors.Wrap(err, "failed to write the JUnit report")
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(suites)
+	if err != nil {
+		return errors.Wrap(err, "failed to write the JUnit report")
+	}
+
+	_, err = io.WriteString(w, "\n")
+	return errors.Wrap(err, "failed to write the JUnit report")
+}
+
+func formatSeconds(seconds float64) string {
+	return fmt.Sprintf("%.3f", seconds)
+}
@@ -0,0 +1,129 @@
+package report_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/datablast-analytics/blast/pkg/pipeline"
+	"github.com/datablast-analytics/blast/pkg/report"
+	"github.com/datablast-analytics/blast/pkg/scheduler"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newInstance(name string, status scheduler.TaskInstanceStatus, upstreams ...scheduler.TaskInstance) *scheduler.AssetInstance {
+	instance := &scheduler.AssetInstance{
+		HumanID: name,
+		Asset:   &pipeline.Asset{Name: name},
+	}
+	instance.MarkAs(status)
+	for _, upstream := range upstreams {
+		instance.AddUpstream(upstream)
+	}
+
+	return instance
+}
+
+func newRun() *report.Run {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	succeeded := newInstance("succeeded", scheduler.Succeeded)
+	skipped := newInstance("not-selected", scheduler.Succeeded)
+	failed := newInstance("failed", scheduler.Failed, succeeded)
+	upstreamFailed := newInstance("upstream-failed", scheduler.UpstreamFailed, failed)
+	transitive := newInstance("transitive", scheduler.UpstreamFailed, upstreamFailed, succeeded)
+	weekly := newInstance("weekly", scheduler.Skipped)
+
+	instances := []scheduler.TaskInstance{succeeded, skipped, failed, upstreamFailed, transitive, weekly}
+	results := []*scheduler.TaskExecutionResult{
+		{Instance: succeeded, Attempts: 1, StartedAt: start, FinishedAt: start.Add(2 * time.Second)},
+		{Instance: failed, Attempts: 2, Error: errors.New("query failed"), StartedAt: start.Add(2 * time.Second), FinishedAt: start.Add(3 * time.Second)},
+	}
+
+	return report.NewRun("run-1", "pipeline-1", start, start.AddDate(0, 0, 1), start, 3*time.Second, instances, results)
+}
+
+func TestNewRun(t *testing.T) {
+	t.Parallel()
+
+	run := newRun()
+
+	assert.Equal(t, report.StatusFailed, run.Status)
+	require.Len(t, run.Instances, 5)
+
+	assert.Equal(t, "succeeded", run.Instances[0].ID)
+	assert.Equal(t, "main", run.Instances[0].Type)
+	assert.InDelta(t, 2.0, run.Instances[0].DurationSeconds, 0.001)
+	assert.Empty(t, run.Instances[0].Error)
+
+	assert.Equal(t, "failed", run.Instances[1].ID)
+	assert.Equal(t, scheduler.Failed, run.Instances[1].Status)
+	assert.Equal(t, 2, run.Instances[1].Attempts)
+	assert.Equal(t, "query failed", run.Instances[1].Error)
+
+	assert.Equal(t, []string{"failed"}, run.Instances[2].FailedUpstreams)
+	assert.Nil(t, run.Instances[2].StartedAt)
+	assert.Equal(t, []string{"failed"}, run.Instances[3].FailedUpstreams)
+
+	assert.Equal(t, "weekly", run.Instances[4].ID)
+	assert.Equal(t, scheduler.Skipped, run.Instances[4].Status)
+}
+
+func TestNewBackfill(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	first := &report.Run{ID: "run-1", StartDate: start, EndDate: start.AddDate(0, 0, 1), StartedAt: start, DurationSeconds: 10, Status: report.StatusSucceeded}
+	second := &report.Run{ID: "run-2", StartDate: start.AddDate(0, 0, 1), EndDate: start.AddDate(0, 0, 2), StartedAt: start.Add(5 * time.Second), DurationSeconds: 20, Status: report.StatusCancelled}
+	third := &report.Run{StartDate: start.AddDate(0, 0, 2), EndDate: start.AddDate(0, 0, 3), Status: report.StatusFailed}
+
+	run := report.NewBackfill("pipeline-1", []*report.Run{first, second})
+	assert.Equal(t, "pipeline-1", run.Pipeline)
+	assert.Equal(t, report.StatusCancelled, run.Status)
+	assert.Equal(t, start, run.StartDate)
+	assert.Equal(t, start.AddDate(0, 0, 2), run.EndDate)
+	assert.Equal(t, start, run.StartedAt)
+	assert.InDelta(t, 25.0, run.DurationSeconds, 0.001)
+	assert.Empty(t, run.Instances)
+	assert.Equal(t, []*report.Run{first, second}, run.Intervals)
+
+	run = report.NewBackfill("pipeline-1", []*report.Run{first, second, third})
+	assert.Equal(t, report.StatusFailed, run.Status)
+	assert.Equal(t, start.AddDate(0, 0, 3), run.EndDate)
+}
+
+func TestWriteJSON(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	err := report.WriteJSON(&buf, &report.Report{Runs: []*report.Run{newRun()}})
+	require.NoError(t, err)
+
+	var decoded map[string][]map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Len(t, decoded["runs"], 1)
+
+	instances := decoded["runs"][0]["instances"].([]any)
+	require.Len(t, instances, 5)
+	assert.Equal(t, "failed", instances[1].(map[string]any)["status"])
+	assert.Equal(t, "upstream_failed", instances[2].(map[string]any)["status"])
+	assert.Equal(t, "skipped", instances[4].(map[string]any)["status"])
+}
+
+func TestWriteJUnit(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	err := report.WriteJUnit(&buf, &report.Report{Runs: []*report.Run{newRun()}})
+	require.NoError(t, err)
+
+	out := buf.String()
+	assert.Contains(t, out, `<testsuite name="pipeline-1 (2023-01-01T00:00:00Z - 2023-01-02T00:00:00Z)" tests="5" failures="1" skipped="3" time="3.000"`)
+	assert.Contains(t, out, `<testcase name="succeeded" classname="pipeline-1.main" time="2.000"></testcase>`)
+	assert.Contains(t, out, `<failure message="the instance has failed">query failed</failure>`)
+	assert.Contains(t, out, `<skipped message="the upstream instances have failed: [failed]"></skipped>`)
+	assert.Contains(t, out, `<skipped message="the asset is not scheduled for the day"></skipped>`)
+}
@@ -3,10 +3,15 @@ package scheduler
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/datablast-analytics/blast/pkg/events"
 	"github.com/datablast-analytics/blast/pkg/pipeline"
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
 )
 
@@ -26,10 +31,30 @@ func (s TaskInstanceStatus) String() string {
 		return "upstream_failed"
 	case Succeeded:
 		return "succeeded"
+	case Cancelled:
+		return "cancelled"
+	case Skipped:
+		return "skipped"
 	}
 	return "unknown"
 }
 
+func (s TaskInstanceStatus) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *TaskInstanceStatus) UnmarshalText(text []byte) error {
+	// the statuses are sequential, the first one without a name marks the end of the list
+	for status := Pending; status.String() != "unknown"; status++ {
+		if status.String() == string(text) {
+			*s = status
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown task instance status '%s'", text)
+}
+
 type TaskInstanceType int
 
 func (s TaskInstanceType) String() string {
@@ -51,6 +76,9 @@ const (
 	Failed
 	UpstreamFailed
 	Succeeded
+	Cancelled
+	// Skipped instances are not run since their assets are not scheduled for the day, they satisfy their downstreams.
+	Skipped
 )
 
 const (
@@ -95,7 +123,7 @@ func (t *AssetInstance) GetStatus() TaskInstanceStatus {
 }
 
 func (t *AssetInstance) Completed() bool {
-	return t.status == Failed || t.status == Succeeded || t.status == UpstreamFailed
+	return t.status == Failed || t.status == Succeeded || t.status == UpstreamFailed || t.status == Cancelled || t.status == Skipped
 }
 
 func (t *AssetInstance) MarkAs(status TaskInstanceStatus) {
@@ -142,9 +170,56 @@ func (t *ColumnCheckInstance) GetType() TaskInstanceType {
 	return TaskInstanceTypeColumnCheck
 }
 
+func (t *ColumnCheckInstance) GetSeverity() pipeline.CheckSeverity {
+	return t.Check.Severity
+}
+
+type CustomCheckInstance struct {
+	*AssetInstance
+
+	parentID string
+	Check    *pipeline.CustomCheck
+}
+
+func (t *CustomCheckInstance) GetType() TaskInstanceType {
+	return TaskInstanceTypeCustomTest
+}
+
+func (t *CustomCheckInstance) GetSeverity() pipeline.CheckSeverity {
+	return t.Check.Severity
+}
+
+type severityAware interface {
+	GetSeverity() pipeline.CheckSeverity
+}
+
 type TaskExecutionResult struct {
-	Instance TaskInstance
-	Error    error
+	Instance   TaskInstance
+	Error      error
+	Attempts   int
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// IsWarning reports whether the result is a failure of a check with the warn severity, such failures are reported but
+// they do not block the downstream instances.
+func (r *TaskExecutionResult) IsWarning() bool {
+	if r.Error == nil {
+		return false
+	}
+
+	instance, ok := r.Instance.(severityAware)
+	return ok && instance.GetSeverity() == pipeline.CheckSeverityWarn
+}
+
+// NewInstanceEvent creates an event about the given instance, the rest of the fields are filled by the publisher.
+func NewInstanceEvent(eventType events.Type, instance TaskInstance) *events.Event {
+	return &events.Event{
+		Type:         eventType,
+		InstanceID:   instance.GetHumanID(),
+		InstanceType: instance.GetType().String(),
+		Asset:        instance.GetAsset().Name,
+	}
 }
 
 type InstancesByType map[TaskInstanceType][]TaskInstance
@@ -152,14 +227,32 @@ type InstancesByType map[TaskInstanceType][]TaskInstance
 type Scheduler struct {
 	logger           *zap.SugaredLogger
 	taskScheduleLock sync.Mutex
+	cancelled        bool
+	workQueueClosed  sync.Once
 
 	taskInstances []TaskInstance
 	taskNameMap   map[string]InstancesByType
 
+	// the slots are taken when an instance is queued and released when its result is received
+	maxActiveTasks int
+	activeTasks    int
+	poolUsage      map[string]int
+	acquiredPools  map[TaskInstance][]pipeline.ConcurrencyPool
+
+	estimatedDurations map[string]time.Duration
+	criticalPaths      map[TaskInstance]time.Duration
+
+	events        events.Publisher
+	stateListener func(statuses map[string]TaskInstanceStatus)
+
 	WorkQueue chan TaskInstance
 	Results   chan *TaskExecutionResult
 }
 
+func (s *Scheduler) GetTaskInstances() []TaskInstance {
+	return s.taskInstances
+}
+
 func (s *Scheduler) InstanceCount() int {
 	return len(s.taskInstances)
 }
@@ -206,6 +299,56 @@ func (s *Scheduler) MarkTaskInstance(instance TaskInstance, status TaskInstanceS
 	}
 }
 
+// GetInstanceStatuses returns the current status of every instance keyed by their human-readable IDs, which stay
+// the same across runs of the same pipeline.
+func (s *Scheduler) GetInstanceStatuses() map[string]TaskInstanceStatus {
+	statuses := make(map[string]TaskInstanceStatus, len(s.taskInstances))
+	for _, instance := range s.taskInstances {
+		statuses[instance.GetHumanID()] = instance.GetStatus()
+	}
+
+	return statuses
+}
+
+// RestoreState marks the instances that have succeeded in a previous run as succeeded, and every other instance as
+// pending, so that only the failed and the unfinished instances are scheduled again.
+func (s *Scheduler) RestoreState(statuses map[string]TaskInstanceStatus) {
+	for _, instance := range s.taskInstances {
+		if statuses[instance.GetHumanID()] == Succeeded {
+			instance.MarkAs(Succeeded)
+			continue
+		}
+
+		instance.MarkAs(Pending)
+	}
+}
+
+// SkipAssetsNotScheduledOn marks the pending instances of the assets whose schedule does not include the given weekday
+// as skipped, the assets without any schedule days run every day.
+func (s *Scheduler) SkipAssetsNotScheduledOn(day time.Weekday) {
+	for _, instance := range s.taskInstances {
+		if instance.GetStatus() != Pending || isScheduledOn(instance.GetAsset(), day) {
+			continue
+		}
+
+		instance.MarkAs(Skipped)
+	}
+}
+
+func isScheduledOn(asset *pipeline.Asset, day time.Weekday) bool {
+	if len(asset.Schedule.Days) == 0 {
+		return true
+	}
+
+	for _, scheduledDay := range asset.Schedule.Days {
+		if strings.EqualFold(scheduledDay, day.String()) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (s *Scheduler) markTaskInstanceFailedWithDownstream(instance TaskInstance) {
 	s.MarkTaskInstance(instance, UpstreamFailed, true)
 	s.MarkTaskInstance(instance, Failed, false)
@@ -272,12 +415,32 @@ func NewScheduler(logger *zap.SugaredLogger, p *pipeline.Pipeline) *Scheduler {
 				instances = append(instances, testInstance)
 			}
 		}
+
+		for _, check := range task.CustomChecks {
+			c := check
+			instances = append(instances, &CustomCheckInstance{
+				AssetInstance: &AssetInstance{
+					ID:         uuid.New().String(),
+					HumanID:    fmt.Sprintf("%s:custom:%s", task.Name, c.Name),
+					Pipeline:   p,
+					Asset:      task,
+					status:     Pending,
+					upstream:   make([]TaskInstance, 0),
+					downstream: make([]TaskInstance, 0),
+				},
+				parentID: parentID,
+				Check:    &c,
+			})
+		}
 	}
 
 	s := &Scheduler{
 		logger:           logger,
 		taskInstances:    instances,
 		taskScheduleLock: sync.Mutex{},
+		maxActiveTasks:   p.MaxActiveTasks,
+		poolUsage:        make(map[string]int),
+		acquiredPools:    make(map[TaskInstance][]pipeline.ConcurrencyPool),
 		WorkQueue:        make(chan TaskInstance, 100),
 		Results:          make(chan *TaskExecutionResult),
 	}
@@ -336,10 +499,11 @@ func (s *Scheduler) constructInstanceRelationships() {
 		}
 
 		assetName := ti.GetAsset().Name
-		columnChecks := s.taskNameMap[assetName][TaskInstanceTypeColumnCheck]
-		for _, instance := range columnChecks {
-			instance.AddUpstream(ti)
-			ti.AddDownstream(instance)
+		for _, checkType := range []TaskInstanceType{TaskInstanceTypeColumnCheck, TaskInstanceTypeCustomTest} {
+			for _, instance := range s.taskNameMap[assetName][checkType] {
+				instance.AddUpstream(ti)
+				ti.AddDownstream(instance)
+			}
 		}
 
 		for _, dep := range ti.GetAsset().DependsOn {
@@ -364,15 +528,22 @@ func (s *Scheduler) Run(ctx context.Context) []*TaskExecutionResult {
 	results := make([]*TaskExecutionResult, 0)
 
 	s.logger.Debug("started the scheduler loop")
+	done := ctx.Done()
 	for {
 		select {
-		case <-ctx.Done():
-			close(s.WorkQueue)
-			return results
+		case <-done:
+			s.logger.Debug("the context is cancelled, waiting for the running instances to finish")
+			done = nil
+			finished := s.cancel()
+			s.notifyStateListener()
+			if finished {
+				return results
+			}
 		case result := <-s.Results:
 			s.logger.Debug("received task result: ", result.Instance.GetAsset().Name)
 			results = append(results, result)
 			finished := s.Tick(result)
+			s.notifyStateListener()
 			if finished {
 				s.logger.Debug("pipeline has completed, finishing the scheduler loop")
 				return results
@@ -385,19 +556,39 @@ func (s *Scheduler) Run(ctx context.Context) []*TaskExecutionResult {
 // The results are mainly fed from a channel, but Tick allows implementing additional methods of passing
 // Asset results and simulating scheduler loops, e.g. time travel. It is also useful for testing purposes.
 func (s *Scheduler) Tick(result *TaskExecutionResult) bool {
+	return s.tick(result, true)
+}
+
+func (s *Scheduler) tick(result *TaskExecutionResult, publish bool) bool {
 	s.taskScheduleLock.Lock()
 	defer s.taskScheduleLock.Unlock()
 
+	var pendingDownstreams []TaskInstance
+	s.releaseSlots(result.Instance)
 	s.MarkTaskInstance(result.Instance, Succeeded, false)
 	if result.Error != nil {
-		s.markTaskInstanceFailedWithDownstream(result.Instance)
+		if s.cancelled {
+			s.MarkTaskInstance(result.Instance, Cancelled, false)
+		} else if !result.IsWarning() {
+			pendingDownstreams = s.pendingDownstreams(result.Instance)
+			s.markTaskInstanceFailedWithDownstream(result.Instance)
+		}
+	}
+
+	if publish {
+		s.publishResult(result)
+		s.publishSkipped(pendingDownstreams, UpstreamFailed)
 	}
 
 	if s.hasPipelineFinished() {
-		close(s.WorkQueue)
+		s.closeWorkQueue()
 		return true
 	}
 
+	if s.cancelled {
+		return false
+	}
+
 	tasks := s.getScheduleableTasks()
 	if len(tasks) == 0 {
 		return false
@@ -405,22 +596,148 @@ func (s *Scheduler) Tick(result *TaskExecutionResult) bool {
 
 	for _, task := range tasks {
 		task.MarkAs(Queued)
+		s.publish(NewInstanceEvent(events.InstanceQueued, task))
 		s.WorkQueue <- task
 	}
 
 	return false
 }
 
+// SetEventPublisher sets where the scheduler publishes the queued and the finished instances.
+func (s *Scheduler) SetEventPublisher(publisher events.Publisher) {
+	s.taskScheduleLock.Lock()
+	defer s.taskScheduleLock.Unlock()
+
+	s.events = publisher
+}
+
+// SetStateListener sets a function that receives the statuses of the instances every time a result is processed, e.g.
+// to persist the progress of the run.
+func (s *Scheduler) SetStateListener(listener func(statuses map[string]TaskInstanceStatus)) {
+	s.taskScheduleLock.Lock()
+	defer s.taskScheduleLock.Unlock()
+
+	s.stateListener = listener
+}
+
+func (s *Scheduler) notifyStateListener() {
+	s.taskScheduleLock.Lock()
+	listener := s.stateListener
+	var statuses map[string]TaskInstanceStatus
+	if listener != nil {
+		statuses = s.GetInstanceStatuses()
+	}
+	s.taskScheduleLock.Unlock()
+
+	if listener != nil {
+		listener(statuses)
+	}
+}
+
+func (s *Scheduler) publish(e *events.Event) {
+	if s.events == nil {
+		return
+	}
+
+	s.events.Publish(e)
+}
+
+func (s *Scheduler) publishResult(result *TaskExecutionResult) {
+	e := NewInstanceEvent(events.InstanceFinished, result.Instance)
+	e.Status = result.Instance.GetStatus().String()
+	e.Attempts = result.Attempts
+	if !result.StartedAt.IsZero() {
+		e.DurationSeconds = result.FinishedAt.Sub(result.StartedAt).Seconds()
+	}
+	if result.IsWarning() {
+		e.Warning = result.Error.Error()
+	} else if result.Error != nil {
+		e.Error = result.Error.Error()
+	}
+
+	s.publish(e)
+}
+
+// publishSkipped publishes the instances that will never run as finished if they are marked with the given status.
+func (s *Scheduler) publishSkipped(instances []TaskInstance, status TaskInstanceStatus) {
+	for _, instance := range instances {
+		if instance.GetStatus() != status {
+			continue
+		}
+
+		e := NewInstanceEvent(events.InstanceFinished, instance)
+		e.Status = status.String()
+		s.publish(e)
+	}
+}
+
+// pendingDownstreams returns the pending instances that depend on the given instance directly or indirectly.
+func (s *Scheduler) pendingDownstreams(instance TaskInstance) []TaskInstance {
+	seen := make(map[TaskInstance]bool)
+	pending := make([]TaskInstance, 0)
+
+	var walk func(instance TaskInstance)
+	walk = func(instance TaskInstance) {
+		for _, downstream := range instance.GetDownstream() {
+			if seen[downstream] {
+				continue
+			}
+			seen[downstream] = true
+
+			if downstream.GetStatus() == Pending {
+				pending = append(pending, downstream)
+			}
+			walk(downstream)
+		}
+	}
+	walk(instance)
+
+	return pending
+}
+
+// cancel stops scheduling new instances and marks the ones that are not started yet as cancelled, the instances that
+// are already running are marked as cancelled if they fail after the cancellation. It returns true if there are no
+// running instances left.
+func (s *Scheduler) cancel() bool {
+	s.taskScheduleLock.Lock()
+	defer s.taskScheduleLock.Unlock()
+
+	s.cancelled = true
+	pending := s.GetTaskInstancesByStatus(Pending)
+	for _, instance := range pending {
+		instance.MarkAs(Cancelled)
+	}
+	s.publishSkipped(pending, Cancelled)
+
+	if s.hasPipelineFinished() {
+		s.closeWorkQueue()
+		return true
+	}
+
+	return false
+}
+
+// closeWorkQueue closes the queue only once, since a cancelled run may finish before the kickstart tick is processed.
+func (s *Scheduler) closeWorkQueue() {
+	s.workQueueClosed.Do(func() {
+		close(s.WorkQueue)
+	})
+}
+
 // Kickstart initiates the scheduler process by sending a "start" task for the processing.
 func (s *Scheduler) Kickstart() {
-	s.Tick(&TaskExecutionResult{
+	s.taskScheduleLock.Lock()
+	s.publishSkipped(s.GetTaskInstancesByStatus(Skipped), Skipped)
+	s.taskScheduleLock.Unlock()
+
+	s.tick(&TaskExecutionResult{
 		Instance: &AssetInstance{
 			Asset: &pipeline.Asset{
 				Name: "start",
 			},
 			status: Succeeded,
 		},
-	})
+	}, false)
 }
 
 func (s *Scheduler) getScheduleableTasks() []TaskInstance {
@@ -429,7 +746,16 @@ func (s *Scheduler) getScheduleableTasks() []TaskInstance {
 		s.constructInstanceRelationships()
 	}
 
-	tasks := make([]TaskInstance, 0)
+	if s.acquiredPools == nil {
+		s.poolUsage = make(map[string]int)
+		s.acquiredPools = make(map[TaskInstance][]pipeline.ConcurrencyPool)
+	}
+
+	if s.criticalPaths == nil {
+		s.computeCriticalPaths()
+	}
+
+	ready := make([]TaskInstance, 0)
 	for _, task := range s.taskInstances {
 		if task.GetStatus() != Pending {
 			continue
@@ -439,12 +765,140 @@ func (s *Scheduler) getScheduleableTasks() []TaskInstance {
 			continue
 		}
 
+		ready = append(ready, task)
+	}
+
+	// the explicit priorities come first, the rest is ordered by the longest remaining path to the end of the pipeline
+	sort.SliceStable(ready, func(i, j int) bool {
+		priorityI, priorityJ := ready[i].GetAsset().Priority, ready[j].GetAsset().Priority
+		if priorityI != priorityJ {
+			return priorityI > priorityJ
+		}
+
+		return s.criticalPaths[ready[i]] > s.criticalPaths[ready[j]]
+	})
+
+	tasks := make([]TaskInstance, 0, len(ready))
+	for _, task := range ready {
+		if !s.acquireSlots(task) {
+			continue
+		}
+
 		tasks = append(tasks, task)
 	}
 
 	return tasks
 }
 
+// SetEstimatedDurations sets the expected durations of the instances keyed by their human IDs, e.g. from the previous
+// runs. The instances without an estimate are assumed to take the average of the known ones.
+func (s *Scheduler) SetEstimatedDurations(durations map[string]time.Duration) {
+	s.taskScheduleLock.Lock()
+	defer s.taskScheduleLock.Unlock()
+
+	s.estimatedDurations = durations
+	s.criticalPaths = nil
+}
+
+// computeCriticalPaths calculates the estimated duration of the longest path from every instance to the end of the
+// pipeline, including the instance itself.
+func (s *Scheduler) computeCriticalPaths() {
+	defaultDuration := time.Second
+	if len(s.estimatedDurations) > 0 {
+		var total time.Duration
+		for _, duration := range s.estimatedDurations {
+			total += duration
+		}
+		defaultDuration = total / time.Duration(len(s.estimatedDurations))
+	}
+
+	s.criticalPaths = make(map[TaskInstance]time.Duration, len(s.taskInstances))
+	visiting := make(map[TaskInstance]bool)
+
+	var walk func(instance TaskInstance) time.Duration
+	walk = func(instance TaskInstance) time.Duration {
+		if path, ok := s.criticalPaths[instance]; ok {
+			return path
+		}
+
+		// the cycles are reported by the linter, they are only guarded against here
+		if visiting[instance] {
+			return 0
+		}
+		visiting[instance] = true
+
+		var longest time.Duration
+		for _, downstream := range instance.GetDownstream() {
+			if path := walk(downstream); path > longest {
+				longest = path
+			}
+		}
+
+		duration, ok := s.estimatedDurations[instance.GetHumanID()]
+		if !ok {
+			duration = defaultDuration
+		}
+
+		s.criticalPaths[instance] = duration + longest
+		return s.criticalPaths[instance]
+	}
+
+	for _, instance := range s.taskInstances {
+		walk(instance)
+	}
+}
+
+// ValidateConcurrencyLimits rejects the pools without any slots, the instances that need a slot from them would never
+// run and the run would wait for them forever.
+func ValidateConcurrencyLimits(p *pipeline.Pipeline) error {
+	invalidPools := p.Pools.Invalid()
+	if len(invalidPools) > 0 {
+		return errors.Errorf("the concurrency pools must have a positive number of slots: %s", strings.Join(invalidPools, ", "))
+	}
+
+	return nil
+}
+
+// acquireSlots takes a slot from every pool of the instance if all of them have free slots, the instances that cannot
+// get their slots stay pending until another instance finishes.
+func (s *Scheduler) acquireSlots(instance TaskInstance) bool {
+	if s.maxActiveTasks > 0 && s.activeTasks >= s.maxActiveTasks {
+		return false
+	}
+
+	pools := make([]pipeline.ConcurrencyPool, 0)
+	if instance.GetPipeline() != nil {
+		pools = instance.GetPipeline().GetConcurrencyPoolsForAsset(instance.GetAsset())
+	}
+
+	for _, pool := range pools {
+		if s.poolUsage[pool.Name] >= pool.Slots {
+			return false
+		}
+	}
+
+	s.activeTasks++
+	for _, pool := range pools {
+		s.poolUsage[pool.Name]++
+	}
+	s.acquiredPools[instance] = pools
+
+	return true
+}
+
+func (s *Scheduler) releaseSlots(instance TaskInstance) {
+	pools, ok := s.acquiredPools[instance]
+	if !ok {
+		return
+	}
+
+	delete(s.acquiredPools, instance)
+	s.activeTasks--
+	for _, pool := range pools {
+		s.poolUsage[pool.Name]--
+	}
+}
+
 func (s *Scheduler) allDependenciesSucceededForTask(t TaskInstance) bool {
 	if len(t.GetUpstream()) == 0 {
 		return true
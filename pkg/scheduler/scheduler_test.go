@@ -1,9 +1,14 @@
 package scheduler
 
 import (
+	"context"
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/datablast-analytics/blast/pkg/events"
 	"github.com/datablast-analytics/blast/pkg/pipeline"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 )
@@ -403,3 +408,478 @@ func TestScheduler_WillRunTaskOfType(t *testing.T) {
 	assert.True(t, s.WillRunTaskOfType("python"))
 	assert.True(t, s.WillRunTaskOfType("empty"))
 }
+
+func TestScheduler_RestoreState(t *testing.T) {
+	t.Parallel()
+
+	p := &pipeline.Pipeline{
+		Tasks: []*pipeline.Asset{
+			{
+				Name: "task11",
+				Columns: map[string]pipeline.Column{
+					"col1": {
+						Name: "col1",
+						Checks: []pipeline.ColumnCheck{
+							{
+								Name: "not_null",
+							},
+						},
+					},
+				},
+			},
+			{
+				Name:      "task12",
+				DependsOn: []string{"task11"},
+			},
+			{
+				Name:      "task13",
+				DependsOn: []string{"task12"},
+			},
+			{
+				Name: "task21",
+			},
+		},
+	}
+
+	previous := NewScheduler(zap.NewNop().Sugar(), p)
+	previous.MarkAll(Succeeded)
+	for _, ti := range previous.GetTaskInstancesByStatus(Succeeded) {
+		switch ti.GetAsset().Name {
+		case "task12":
+			previous.MarkTaskInstance(ti, Failed, false)
+		case "task13":
+			previous.MarkTaskInstance(ti, UpstreamFailed, false)
+		}
+	}
+
+	statuses := previous.GetInstanceStatuses()
+	assert.Equal(t, map[string]TaskInstanceStatus{
+		"task11":               Succeeded,
+		"task11:col1:not_null": Succeeded,
+		"task12":               Failed,
+		"task13":               UpstreamFailed,
+		"task21":               Succeeded,
+	}, statuses)
+
+	s := NewScheduler(zap.NewNop().Sugar(), p)
+	s.RestoreState(statuses)
+
+	assert.Equal(t, 3, s.InstanceCountByStatus(Succeeded))
+	assert.Equal(t, 2, s.InstanceCountByStatus(Pending))
+
+	s.Kickstart()
+
+	ti12 := <-s.WorkQueue
+	assert.Equal(t, "task12", ti12.GetAsset().Name)
+	s.Tick(&TaskExecutionResult{
+		Instance: ti12,
+	})
+
+	ti13 := <-s.WorkQueue
+	assert.Equal(t, "task13", ti13.GetAsset().Name)
+	finished := s.Tick(&TaskExecutionResult{
+		Instance: ti13,
+	})
+	assert.True(t, finished)
+}
+
+func TestTaskInstanceStatus_TextRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, status := range []TaskInstanceStatus{Pending, Queued, Running, Failed, UpstreamFailed, Succeeded, Cancelled} {
+		text, err := status.MarshalText()
+		assert.NoError(t, err)
+
+		var parsed TaskInstanceStatus
+		err = parsed.UnmarshalText(text)
+		assert.NoError(t, err)
+		assert.Equal(t, status, parsed)
+	}
+
+	var parsed TaskInstanceStatus
+	assert.Error(t, parsed.UnmarshalText([]byte("some-random-status")))
+}
+
+func TestScheduler_RunCancelled(t *testing.T) {
+	t.Parallel()
+
+	p := &pipeline.Pipeline{
+		Tasks: []*pipeline.Asset{
+			{
+				Name: "task11",
+			},
+			{
+				Name:      "task12",
+				DependsOn: []string{"task11"},
+			},
+			{
+				Name: "task21",
+			},
+		},
+	}
+
+	s := NewScheduler(zap.NewNop().Sugar(), p)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	resultsChan := make(chan []*TaskExecutionResult)
+	go func() {
+		resultsChan <- s.Run(ctx)
+	}()
+
+	first := <-s.WorkQueue
+	second := <-s.WorkQueue
+	cancel()
+
+	for _, ti := range []TaskInstance{first, second} {
+		if ti.GetAsset().Name == "task11" {
+			s.Results <- &TaskExecutionResult{Instance: ti, Error: context.Canceled}
+		} else {
+			s.Results <- &TaskExecutionResult{Instance: ti}
+		}
+	}
+
+	results := <-resultsChan
+	assert.Len(t, results, 2)
+
+	_, ok := <-s.WorkQueue
+	assert.False(t, ok, "the work queue must be closed")
+
+	assert.Equal(t, map[string]TaskInstanceStatus{
+		"task11": Cancelled,
+		"task12": Cancelled,
+		"task21": Succeeded,
+	}, s.GetInstanceStatuses())
+}
+
+func TestScheduler_ConcurrencyPools(t *testing.T) {
+	t.Parallel()
+
+	p := &pipeline.Pipeline{
+		MaxActiveTasks: 2,
+		Pools: pipeline.Pools{
+			AssetTypes: map[string]int{"python": 1},
+			Custom:     map[string]int{"heavy": 1},
+		},
+		Tasks: []*pipeline.Asset{
+			{Name: "python1", Type: "python"},
+			{Name: "python2", Type: "python"},
+			{Name: "heavy", Type: "bq.sql", Pool: "heavy"},
+			{Name: "light", Type: "bq.sql"},
+		},
+	}
+
+	s := NewScheduler(zap.NewNop().Sugar(), p)
+	s.Kickstart()
+
+	// python2 waits for the python pool, light waits for the pipeline limit
+	python1 := <-s.WorkQueue
+	assert.Equal(t, "python1", python1.GetHumanID())
+	heavy := <-s.WorkQueue
+	assert.Equal(t, "heavy", heavy.GetHumanID())
+	assert.Empty(t, s.WorkQueue)
+	assert.Equal(t, 2, s.InstanceCountByStatus(Pending))
+
+	s.Tick(&TaskExecutionResult{Instance: python1})
+	python2 := <-s.WorkQueue
+	assert.Equal(t, "python2", python2.GetHumanID())
+	assert.Empty(t, s.WorkQueue)
+
+	// a failure releases the slots as well
+	s.Tick(&TaskExecutionResult{Instance: heavy, Error: assert.AnError})
+	light := <-s.WorkQueue
+	assert.Equal(t, "light", light.GetHumanID())
+
+	s.Tick(&TaskExecutionResult{Instance: python2})
+	finished := s.Tick(&TaskExecutionResult{Instance: light})
+	assert.True(t, finished)
+	assert.Equal(t, 0, s.activeTasks)
+}
+
+func TestScheduler_Priorities(t *testing.T) {
+	t.Parallel()
+
+	newPipeline := func() *pipeline.Pipeline {
+		// leaf1, leaf2 and the chain head1 -> head2 -> head3 are all ready at the beginning
+		return &pipeline.Pipeline{
+			Tasks: []*pipeline.Asset{
+				{Name: "leaf1"},
+				{Name: "leaf2"},
+				{Name: "head1"},
+				{Name: "head2", DependsOn: []string{"head1"}},
+				{Name: "head3", DependsOn: []string{"head2"}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		modify    func(p *pipeline.Pipeline)
+		durations map[string]time.Duration
+		want      []string
+	}{
+		{
+			name: "the longest chain goes first by default",
+			want: []string{"head1", "leaf1", "leaf2"},
+		},
+		{
+			name: "the explicit priorities take precedence",
+			modify: func(p *pipeline.Pipeline) {
+				p.Tasks[1].Priority = 10
+				p.Tasks[0].Priority = -1
+			},
+			want: []string{"leaf2", "head1", "leaf1"},
+		},
+		{
+			name: "the historical durations weigh the paths",
+			durations: map[string]time.Duration{
+				"leaf1": time.Hour,
+				"leaf2": 30 * time.Second,
+				"head1": time.Minute,
+				"head2": time.Minute,
+				"head3": time.Minute,
+			},
+			want: []string{"leaf1", "head1", "leaf2"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := newPipeline()
+			if tt.modify != nil {
+				tt.modify(p)
+			}
+
+			s := NewScheduler(zap.NewNop().Sugar(), p)
+			s.SetEstimatedDurations(tt.durations)
+
+			got := make([]string, 0)
+			for _, instance := range s.getScheduleableTasks() {
+				got = append(got, instance.GetHumanID())
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestScheduler_PublishesEvents(t *testing.T) {
+	t.Parallel()
+
+	p := &pipeline.Pipeline{
+		Tasks: []*pipeline.Asset{
+			{Name: "task1"},
+			{Name: "task2", DependsOn: []string{"task1"}},
+			{Name: "task3", DependsOn: []string{"task2"}},
+			{Name: "task4"},
+		},
+	}
+
+	published := make([]string, 0)
+	bus := events.NewBus()
+	bus.Subscribe(events.ListenerFunc(func(e *events.Event) {
+		published = append(published, fmt.Sprintf("%s %s %s %s", e.RunID, e.Type, e.InstanceID, e.Status))
+	}))
+
+	s := NewScheduler(zap.NewNop().Sugar(), p)
+	s.SetEventPublisher(bus.ForRun("run-1"))
+	s.Kickstart()
+
+	task1 := <-s.WorkQueue
+	task4 := <-s.WorkQueue
+	s.Tick(&TaskExecutionResult{Instance: task1, Error: errors.New("failed")})
+	finished := s.Tick(&TaskExecutionResult{Instance: task4})
+	assert.True(t, finished)
+
+	assert.Equal(t, []string{
+		"run-1 instance_queued task1 ",
+		"run-1 instance_queued task4 ",
+		"run-1 instance_finished task1 failed",
+		"run-1 instance_finished task2 upstream_failed",
+		"run-1 instance_finished task3 upstream_failed",
+		"run-1 instance_finished task4 succeeded",
+	}, published)
+}
+
+func TestScheduler_CustomChecks(t *testing.T) {
+	t.Parallel()
+
+	p := &pipeline.Pipeline{
+		Tasks: []*pipeline.Asset{
+			{
+				Name: "task1",
+				CustomChecks: []pipeline.CustomCheck{
+					{Name: "has_rows", Query: "SELECT count(*) > 0 FROM task1", Value: 1},
+				},
+			},
+			{
+				Name:      "task2",
+				DependsOn: []string{"task1"},
+			},
+		},
+	}
+
+	s := NewScheduler(zap.NewNop().Sugar(), p)
+	assert.Equal(t, 3, s.InstanceCount())
+
+	s.Kickstart()
+	task1 := <-s.WorkQueue
+	assert.Equal(t, "task1", task1.GetHumanID())
+
+	s.Tick(&TaskExecutionResult{Instance: task1})
+	check := <-s.WorkQueue
+	assert.Equal(t, "task1:custom:has_rows", check.GetHumanID())
+	assert.Equal(t, TaskInstanceTypeCustomTest, check.GetType())
+	assert.Equal(t, "has_rows", check.(*CustomCheckInstance).Check.Name)
+
+	// the downstream asset waits for the checks of its upstream
+	s.Tick(&TaskExecutionResult{Instance: check, Error: errors.New("custom check 'has_rows' has returned 0 instead of 1")})
+	assert.Equal(t, UpstreamFailed, s.taskNameMap["task2"][TaskInstanceTypeMain][0].GetStatus())
+}
+
+func TestScheduler_WarningChecksDoNotBlockDownstream(t *testing.T) {
+	t.Parallel()
+
+	p := &pipeline.Pipeline{
+		Tasks: []*pipeline.Asset{
+			{
+				Name: "task1",
+				CustomChecks: []pipeline.CustomCheck{
+					{Name: "has_rows", Query: "SELECT count(*) > 0 FROM task1", Value: 1, Severity: pipeline.CheckSeverityWarn},
+				},
+			},
+			{
+				Name:      "task2",
+				DependsOn: []string{"task1"},
+			},
+		},
+	}
+
+	s := NewScheduler(zap.NewNop().Sugar(), p)
+
+	s.Kickstart()
+	task1 := <-s.WorkQueue
+	s.Tick(&TaskExecutionResult{Instance: task1})
+
+	check := <-s.WorkQueue
+	result := &TaskExecutionResult{Instance: check, Error: errors.New("custom check 'has_rows' has returned 0 instead of 1")}
+	assert.True(t, result.IsWarning())
+
+	s.Tick(result)
+	assert.Equal(t, Succeeded, check.GetStatus())
+
+	task2 := <-s.WorkQueue
+	assert.Equal(t, "task2", task2.GetHumanID())
+}
+
+func TestScheduler_SkipAssetsNotScheduledOn(t *testing.T) {
+	t.Parallel()
+
+	p := &pipeline.Pipeline{
+		Tasks: []*pipeline.Asset{
+			{
+				Name:     "weekly",
+				Schedule: pipeline.TaskSchedule{Days: []string{"monday"}},
+				Columns: map[string]pipeline.Column{
+					"id": {Name: "id", Checks: []pipeline.ColumnCheck{{Name: "not_null"}}},
+				},
+			},
+			{
+				Name:      "daily",
+				DependsOn: []string{"weekly"},
+			},
+		},
+	}
+
+	s := NewScheduler(zap.NewNop().Sugar(), p)
+	s.SkipAssetsNotScheduledOn(time.Tuesday)
+
+	assert.Equal(t, 2, s.InstanceCountByStatus(Skipped))
+	assert.Equal(t, 1, s.InstanceCountByStatus(Pending))
+
+	s.Kickstart()
+	daily := <-s.WorkQueue
+	assert.Equal(t, "daily", daily.GetHumanID())
+
+	s.Tick(&TaskExecutionResult{Instance: daily})
+	assert.Equal(t, 0, s.InstanceCountByStatus(Pending))
+	assert.Equal(t, 2, s.InstanceCountByStatus(Skipped))
+}
+
+func TestScheduler_StateListenerReceivesEveryResult(t *testing.T) {
+	t.Parallel()
+
+	p := &pipeline.Pipeline{
+		Tasks: []*pipeline.Asset{
+			{Name: "task1"},
+			{Name: "task2", DependsOn: []string{"task1"}},
+		},
+	}
+
+	s := NewScheduler(zap.NewNop().Sugar(), p)
+	states := make([]map[string]TaskInstanceStatus, 0)
+	s.SetStateListener(func(statuses map[string]TaskInstanceStatus) {
+		states = append(states, statuses)
+	})
+
+	go func() {
+		for instance := range s.WorkQueue {
+			s.Results <- &TaskExecutionResult{Instance: instance}
+		}
+	}()
+
+	s.Run(context.Background())
+
+	assert.Equal(t, []map[string]TaskInstanceStatus{
+		{"task1": Succeeded, "task2": Queued},
+		{"task1": Succeeded, "task2": Succeeded},
+	}, states)
+}
+
+func TestValidateConcurrencyLimits(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		pools   pipeline.Pools
+		wantErr string
+	}{
+		{
+			name: "no pools",
+		},
+		{
+			name: "positive slots are accepted",
+			pools: pipeline.Pools{
+				Connections: map[string]int{"gcp": 4},
+				AssetTypes:  map[string]int{"python": 1},
+				Custom:      map[string]int{"heavy": 2},
+			},
+		},
+		{
+			name: "pools without slots are rejected",
+			pools: pipeline.Pools{
+				Connections: map[string]int{"gcp": 4},
+				AssetTypes:  map[string]int{"python": 0},
+				Custom:      map[string]int{"heavy": -1},
+			},
+			wantErr: "the concurrency pools must have a positive number of slots: asset_types.python = 0, custom.heavy = -1",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateConcurrencyLimits(&pipeline.Pipeline{Pools: tt.pools})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package selector
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/datablast-analytics/blast/pkg/pipeline"
+	"github.com/pkg/errors"
+)
+
+const (
+	methodName   = "name"
+	methodTag    = "tag"
+	methodPath   = "path"
+	methodOwner  = "owner"
+	methodDomain = "domain"
+	methodMeta   = "meta"
+)
+
+// Selector picks a set of assets from a pipeline, the syntax follows the dbt selectors:
+//   - `asset_a` selects the asset by its name
+//   - `tag:finance` selects the assets with the given tag
+//   - `path:assets/marts` selects the assets under the given directory, glob patterns are supported
+//   - `owner:someone@example.com` and `domain:finance` select the assets with the given owner or domain
+//   - `meta.sla:6h` selects the assets with the given value for the given meta key
+//   - a `+` prefix adds the upstream assets, a `+` suffix adds the downstream assets
+type Selector struct {
+	method     string
+	metaKey    string
+	value      string
+	upstream   bool
+	downstream bool
+}
+
+func Parse(expression string) (*Selector, error) {
+	s := &Selector{method: methodName}
+
+	value := strings.TrimSpace(expression)
+	if strings.HasPrefix(value, "+") {
+		s.upstream = true
+		value = strings.TrimPrefix(value, "+")
+	}
+
+	if strings.HasSuffix(value, "+") {
+		s.downstream = true
+		value = strings.TrimSuffix(value, "+")
+	}
+
+	if method, v, found := strings.Cut(value, ":"); found {
+		switch {
+		case method == methodName, method == methodTag, method == methodPath, method == methodOwner, method == methodDomain:
+			s.method = method
+		case strings.HasPrefix(method, methodMeta+".") && len(method) > len(methodMeta)+1:
+			s.method = methodMeta
+			s.metaKey = strings.TrimPrefix(method, methodMeta+".")
+		default:
+			return nil, errors.Errorf("unknown selector method '%s' in '%s', the supported methods are name, tag, path, owner, domain and meta.<key>", method, expression)
+		}
+		value = v
+	}
+
+	if value == "" {
+		return nil, errors.Errorf("the selector '%s' is empty", expression)
+	}
+
+	s.value = value
+	return s, nil
+}
+
+// Resolve returns the assets that match the selector along with their upstreams and downstreams if requested.
+func (s *Selector) Resolve(p *pipeline.Pipeline) ([]*pipeline.Asset, error) {
+	matched := make([]*pipeline.Asset, 0)
+	for _, asset := range p.Tasks {
+		if s.matches(p, asset) {
+			matched = append(matched, asset)
+		}
+	}
+
+	if s.method == methodName && len(matched) == 0 {
+		return nil, errors.Errorf("there is no asset named '%s' in the pipeline '%s'", s.value, p.Name)
+	}
+
+	resolved := make([]*pipeline.Asset, 0, len(matched))
+	for _, asset := range matched {
+		resolved = append(resolved, asset)
+		if s.upstream {
+			resolved = append(resolved, asset.GetFullUpstream()...)
+		}
+
+		if s.downstream {
+			resolved = append(resolved, asset.GetFullDownstream()...)
+		}
+	}
+
+	return resolved, nil
+}
+
+func (s *Selector) matches(p *pipeline.Pipeline, asset *pipeline.Asset) bool {
+	switch s.method {
+	case methodTag:
+		for _, tag := range asset.Tags {
+			if tag == s.value {
+				return true
+			}
+		}
+
+		return false
+	case methodOwner:
+		return asset.Owner == s.value
+	case methodDomain:
+		return asset.Domain == s.value
+	case methodMeta:
+		metaValue, ok := asset.Meta[s.metaKey]
+		return ok && metaValue == s.value
+	case methodPath:
+		assetPath := filepath.ToSlash(p.RelativeAssetPath(asset))
+		pattern := strings.TrimSuffix(filepath.ToSlash(s.value), "/")
+
+		if matched, err := filepath.Match(pattern, assetPath); err == nil && matched {
+			return true
+		}
+
+		return strings.HasPrefix(assetPath, pattern+"/")
+	default:
+		return asset.Name == s.value
+	}
+}
+
+// Select returns the assets of the pipeline that match any of the selections and none of the exclusions, in the order
+// they are defined in the pipeline. Every selection can contain multiple selectors separated by spaces, and all the
+// assets are selected if there are no selections.
+func Select(p *pipeline.Pipeline, selections, exclusions []string) ([]*pipeline.Asset, error) {
+	selected := make(map[string]bool, len(p.Tasks))
+	if len(selections) == 0 {
+		for _, asset := range p.Tasks {
+			selected[asset.Name] = true
+		}
+	}
+
+	err := resolveAll(p, selections, selected)
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := make(map[string]bool)
+	err = resolveAll(p, exclusions, excluded)
+	if err != nil {
+		return nil, err
+	}
+
+	assets := make([]*pipeline.Asset, 0, len(selected))
+	for _, asset := range p.Tasks {
+		if selected[asset.Name] && !excluded[asset.Name] {
+			assets = append(assets, asset)
+		}
+	}
+
+	return assets, nil
+}
+
+func resolveAll(p *pipeline.Pipeline, expressions []string, into map[string]bool) error {
+	for _, expression := range expressions {
+		for _, field := range strings.Fields(expression) {
+			s, err := Parse(field)
+			if err != nil {
+				return err
+			}
+
+			assets, err := s.Resolve(p)
+			if err != nil {
+				return err
+			}
+
+			for _, asset := range assets {
+				into[asset.Name] = true
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,189 @@
+package selector
+
+import (
+	"testing"
+
+	"github.com/datablast-analytics/blast/pkg/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		expression string
+		want       *Selector
+		wantErr    bool
+	}{
+		{expression: "asset_a", want: &Selector{method: methodName, value: "asset_a"}},
+		{expression: "+asset_a", want: &Selector{method: methodName, value: "asset_a", upstream: true}},
+		{expression: "asset_a+", want: &Selector{method: methodName, value: "asset_a", downstream: true}},
+		{expression: "+name:asset_a+", want: &Selector{method: methodName, value: "asset_a", upstream: true, downstream: true}},
+		{expression: "tag:finance", want: &Selector{method: methodTag, value: "finance"}},
+		{expression: "path:assets/marts/*+", want: &Selector{method: methodPath, value: "assets/marts/*", downstream: true}},
+		{expression: "owner:someone@example.com", want: &Selector{method: methodOwner, value: "someone@example.com"}},
+		{expression: "+domain:finance", want: &Selector{method: methodDomain, value: "finance", upstream: true}},
+		{expression: "meta.sla:6h", want: &Selector{method: methodMeta, metaKey: "sla", value: "6h"}},
+		{expression: "meta.:6h", wantErr: true},
+		{expression: "team:someone", wantErr: true},
+		{expression: "tag:", wantErr: true},
+		{expression: "+", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.expression, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Parse(tt.expression)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSelect(t *testing.T) {
+	t.Parallel()
+
+	// raw -> staging -> mart_a
+	//            └----> mart_b -> report
+	raw := &pipeline.Asset{Name: "raw", Owner: "ingestion@example.com", Meta: map[string]string{"sla": "1h"}, DefinitionFile: pipeline.TaskDefinitionFile{Path: "/pipeline/assets/raw/raw.sql"}}
+	staging := &pipeline.Asset{Name: "staging", Tags: []string{"finance"}, DefinitionFile: pipeline.TaskDefinitionFile{Path: "/pipeline/assets/staging/staging.sql"}}
+	martA := &pipeline.Asset{Name: "mart_a", Tags: []string{"finance", "daily"}, Domain: "finance", Meta: map[string]string{"sla": "6h"}, DefinitionFile: pipeline.TaskDefinitionFile{Path: "/pipeline/assets/marts/mart_a.sql"}}
+	martB := &pipeline.Asset{Name: "mart_b", Domain: "finance", DefinitionFile: pipeline.TaskDefinitionFile{Path: "/pipeline/assets/marts/mart_b.sql"}}
+	report := &pipeline.Asset{Name: "report", Tags: []string{"daily"}, DefinitionFile: pipeline.TaskDefinitionFile{Path: "/pipeline/reports/report.py"}}
+
+	link := func(upstream, downstream *pipeline.Asset) {
+		downstream.AddUpstream(upstream)
+		upstream.AddDownstream(downstream)
+	}
+	link(raw, staging)
+	link(staging, martA)
+	link(staging, martB)
+	link(martB, report)
+
+	p := &pipeline.Pipeline{
+		Name:           "test",
+		DefinitionFile: pipeline.DefinitionFile{Path: "/pipeline/pipeline.yml"},
+		Tasks:          []*pipeline.Asset{raw, staging, martA, martB, report},
+	}
+
+	tests := []struct {
+		name       string
+		selections []string
+		exclusions []string
+		want       []string
+		wantErr    bool
+	}{
+		{
+			name: "everything is selected by default",
+			want: []string{"raw", "staging", "mart_a", "mart_b", "report"},
+		},
+		{
+			name:       "single asset",
+			selections: []string{"mart_b"},
+			want:       []string{"mart_b"},
+		},
+		{
+			name:       "asset with its upstreams",
+			selections: []string{"+mart_b"},
+			want:       []string{"raw", "staging", "mart_b"},
+		},
+		{
+			name:       "asset with its downstreams",
+			selections: []string{"staging+"},
+			want:       []string{"staging", "mart_a", "mart_b", "report"},
+		},
+		{
+			name:       "asset with both directions",
+			selections: []string{"+mart_b+"},
+			want:       []string{"raw", "staging", "mart_b", "report"},
+		},
+		{
+			name:       "tags",
+			selections: []string{"tag:daily"},
+			want:       []string{"mart_a", "report"},
+		},
+		{
+			name:       "path with a glob",
+			selections: []string{"path:assets/marts/*"},
+			want:       []string{"mart_a", "mart_b"},
+		},
+		{
+			name:       "path with a directory",
+			selections: []string{"path:assets/"},
+			want:       []string{"raw", "staging", "mart_a", "mart_b"},
+		},
+		{
+			name:       "owner",
+			selections: []string{"owner:ingestion@example.com+"},
+			want:       []string{"raw", "staging", "mart_a", "mart_b", "report"},
+		},
+		{
+			name:       "domain",
+			selections: []string{"domain:finance"},
+			want:       []string{"mart_a", "mart_b"},
+		},
+		{
+			name:       "meta",
+			selections: []string{"meta.sla:6h"},
+			want:       []string{"mart_a"},
+		},
+		{
+			name:       "multiple selectors are combined",
+			selections: []string{"raw tag:daily", "mart_b"},
+			want:       []string{"raw", "mart_a", "mart_b", "report"},
+		},
+		{
+			name:       "exclusions without selections",
+			exclusions: []string{"mart_b+"},
+			want:       []string{"raw", "staging", "mart_a"},
+		},
+		{
+			name:       "exclusions with selections",
+			selections: []string{"tag:finance+"},
+			exclusions: []string{"path:reports/*"},
+			want:       []string{"staging", "mart_a", "mart_b"},
+		},
+		{
+			name:       "a tag that matches nothing",
+			selections: []string{"tag:unknown"},
+			want:       []string{},
+		},
+		{
+			name:       "unknown asset name",
+			selections: []string{"+unknown"},
+			wantErr:    true,
+		},
+		{
+			name:       "invalid exclusion",
+			exclusions: []string{"team:someone"},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Select(p, tt.selections, tt.exclusions)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			names := make([]string, len(got))
+			for i, asset := range got {
+				names[i] = asset.Name
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
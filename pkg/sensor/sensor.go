@@ -0,0 +1,140 @@
+package sensor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/datablast-analytics/blast/pkg/executor"
+	"github.com/datablast-analytics/blast/pkg/scheduler"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ModePoke keeps checking the condition every poke interval until it is met or the timeout is reached.
+	ModePoke = "poke"
+	// ModeOnce checks the condition a single time and fails right away if it is not met.
+	ModeOnce = "once"
+
+	DefaultPokeInterval = 30 * time.Second
+	DefaultTimeout      = 6 * time.Hour
+)
+
+// Poker checks once whether the condition the sensor waits for is met.
+type Poker interface {
+	Poke(ctx context.Context, ti scheduler.TaskInstance) (bool, error)
+}
+
+type Config struct {
+	PokeInterval time.Duration
+	Timeout      time.Duration
+	Mode         string
+}
+
+// ParseConfig reads the `poke_interval`, `timeout` and `mode` parameters of a sensor asset, the durations are given in
+// the Go format, e.g. `30s` or `1h`.
+func ParseConfig(params map[string]string) (*Config, error) {
+	config := &Config{
+		PokeInterval: DefaultPokeInterval,
+		Timeout:      DefaultTimeout,
+		Mode:         ModePoke,
+	}
+
+	if value, ok := params["poke_interval"]; ok {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			return nil, errors.Errorf("invalid `poke_interval` '%s', it must be a positive duration such as '30s'", value)
+		}
+		config.PokeInterval = interval
+	}
+
+	if value, ok := params["timeout"]; ok {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			return nil, errors.Errorf("invalid `timeout` '%s', it must be a positive duration such as '1h'", value)
+		}
+		config.Timeout = timeout
+	}
+
+	if value, ok := params["mode"]; ok {
+		if value != ModePoke && value != ModeOnce {
+			return nil, errors.Errorf("invalid `mode` '%s', it must be either '%s' or '%s'", value, ModePoke, ModeOnce)
+		}
+		config.Mode = value
+	}
+
+	return config, nil
+}
+
+// Operator runs a sensor, it succeeds as soon as the poker reports that the condition is met.
+type Operator struct {
+	poker Poker
+	mode  string
+}
+
+func NewOperator(poker Poker) *Operator {
+	return &Operator{poker: poker}
+}
+
+// WithMode returns a copy of the operator that ignores the mode of the assets, e.g. the dry runs poke only once.
+func (o Operator) WithMode(mode string) *Operator {
+	o.mode = mode
+	return &o
+}
+
+func (o Operator) Run(ctx context.Context, ti scheduler.TaskInstance) error {
+	config, err := ParseConfig(ti.GetAsset().Parameters)
+	if err != nil {
+		return err
+	}
+
+	if o.mode != "" {
+		config.Mode = o.mode
+	}
+
+	deadline := time.Now().Add(config.Timeout)
+	for {
+		met, err := o.poker.Poke(ctx, ti)
+		if err != nil {
+			return errors.Wrap(err, "failed to check the sensor condition")
+		}
+
+		if met {
+			return nil
+		}
+
+		if config.Mode == ModeOnce {
+			return errors.New("the sensor condition is not met")
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return errors.Errorf("the sensor condition is not met within %s", config.Timeout)
+		}
+
+		wait := config.PokeInterval
+		if remaining < wait {
+			wait = remaining
+		}
+
+		log(ctx, fmt.Sprintf("The sensor condition is not met yet, checking again in %s.", wait))
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func log(ctx context.Context, message string) {
+	writer, ok := ctx.Value(executor.KeyPrinter).(io.Writer)
+	if !ok {
+		return
+	}
+
+	_, _ = fmt.Fprintln(writer, message)
+}
@@ -0,0 +1,180 @@
+package sensor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/datablast-analytics/blast/pkg/pipeline"
+	"github.com/datablast-analytics/blast/pkg/scheduler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePoker struct {
+	results []bool
+	err     error
+	pokes   int
+}
+
+func (f *fakePoker) Poke(ctx context.Context, ti scheduler.TaskInstance) (bool, error) {
+	f.pokes++
+	if f.err != nil {
+		return false, f.err
+	}
+
+	if f.pokes > len(f.results) {
+		return false, nil
+	}
+
+	return f.results[f.pokes-1], nil
+}
+
+func TestParseConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		params  map[string]string
+		want    *Config
+		wantErr string
+	}{
+		{
+			name:   "defaults are used",
+			params: map[string]string{"table": "dataset.events"},
+			want:   &Config{PokeInterval: DefaultPokeInterval, Timeout: DefaultTimeout, Mode: ModePoke},
+		},
+		{
+			name:   "all the parameters are given",
+			params: map[string]string{"poke_interval": "1m", "timeout": "2h", "mode": "once"},
+			want:   &Config{PokeInterval: time.Minute, Timeout: 2 * time.Hour, Mode: ModeOnce},
+		},
+		{
+			name:    "poke interval must be a duration",
+			params:  map[string]string{"poke_interval": "30"},
+			wantErr: "invalid `poke_interval` '30', it must be a positive duration such as '30s'",
+		},
+		{
+			name:    "timeout must be positive",
+			params:  map[string]string{"timeout": "-1h"},
+			wantErr: "invalid `timeout` '-1h', it must be a positive duration such as '1h'",
+		},
+		{
+			name:    "mode must be known",
+			params:  map[string]string{"mode": "reschedule"},
+			wantErr: "invalid `mode` 'reschedule', it must be either 'poke' or 'once'",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseConfig(tt.params)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestOperator_Run(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		params    map[string]string
+		poker     *fakePoker
+		mode      string
+		wantPokes int
+		wantErr   string
+	}{
+		{
+			name:      "condition is met right away",
+			poker:     &fakePoker{results: []bool{true}},
+			wantPokes: 1,
+		},
+		{
+			name:      "condition is met after a few pokes",
+			params:    map[string]string{"poke_interval": "1ms"},
+			poker:     &fakePoker{results: []bool{false, false, true}},
+			wantPokes: 3,
+		},
+		{
+			name:      "condition is checked one last time at the timeout",
+			params:    map[string]string{"poke_interval": "1h", "timeout": "50ms"},
+			poker:     &fakePoker{},
+			wantPokes: 2,
+			wantErr:   "the sensor condition is not met within 50ms",
+		},
+		{
+			name:      "once mode pokes a single time",
+			params:    map[string]string{"mode": "once"},
+			poker:     &fakePoker{results: []bool{false, true}},
+			wantPokes: 1,
+			wantErr:   "the sensor condition is not met",
+		},
+		{
+			name:      "operator mode overrides the asset",
+			params:    map[string]string{"poke_interval": "1ms"},
+			poker:     &fakePoker{results: []bool{false, true}},
+			mode:      ModeOnce,
+			wantPokes: 1,
+			wantErr:   "the sensor condition is not met",
+		},
+		{
+			name:      "poke errors fail the sensor",
+			poker:     &fakePoker{err: assert.AnError},
+			wantPokes: 1,
+			wantErr:   "failed to check the sensor condition: " + assert.AnError.Error(),
+		},
+		{
+			name:    "invalid parameters fail the sensor",
+			params:  map[string]string{"mode": "sometimes"},
+			poker:   &fakePoker{},
+			wantErr: "invalid `mode` 'sometimes', it must be either 'poke' or 'once'",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			op := NewOperator(tt.poker)
+			if tt.mode != "" {
+				op = op.WithMode(tt.mode)
+			}
+
+			err := op.Run(context.Background(), &scheduler.AssetInstance{
+				Asset: &pipeline.Asset{Name: "wait", Parameters: tt.params},
+			})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.wantPokes, tt.poker.pokes)
+		})
+	}
+}
+
+func TestOperator_RunIsCancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	poker := &fakePoker{}
+	done := make(chan error)
+	go func() {
+		done <- NewOperator(poker).Run(ctx, &scheduler.AssetInstance{
+			Asset: &pipeline.Asset{Name: "wait", Parameters: map[string]string{"poke_interval": "1h"}},
+		})
+	}()
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
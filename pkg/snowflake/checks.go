@@ -0,0 +1,50 @@
+package snowflake
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var unquotedIdentifierRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*$`)
+
+// quoteIdentifier prepares a possibly-qualified identifier, e.g. `schema.table`, to be used in a Snowflake query.
+// Snowflake resolves unquoted identifiers case-insensitively while quoted ones become case-sensitive, therefore
+// only the parts that cannot be used as they are get wrapped in double quotes.
+func quoteIdentifier(identifier string) string {
+	parts := strings.Split(identifier, ".")
+	for i, part := range parts {
+		if unquotedIdentifierRegex.MatchString(part) {
+			continue
+		}
+
+		parts[i] = fmt.Sprintf(`"%s"`, strings.ReplaceAll(part, `"`, `""`))
+	}
+
+	return strings.Join(parts, ".")
+}
+
+func quoteLiteral(value string) string {
+	return fmt.Sprintf("'%s'", strings.ReplaceAll(value, "'", "''"))
+}
+
+// checkDialect writes the column checks in the Snowflake dialect.
+type checkDialect struct{}
+
+func (checkDialect) QuoteIdentifier(identifier string) string {
+	return quoteIdentifier(identifier)
+}
+
+func (checkDialect) QuoteString(value string) string {
+	return quoteLiteral(value)
+}
+
+func (checkDialect) StringType() string {
+	return "VARCHAR"
+}
+
+// NotMatching relies on REGEXP_LIKE, which matches the patterns against the whole value.
+func (checkDialect) NotMatching(expression string, pattern string) string {
+	// the backslashes are escape characters in the string literals, they need to be escaped to reach the pattern
+	return fmt.Sprintf("NOT REGEXP_LIKE(%s, %s)", expression, quoteLiteral(strings.ReplaceAll(pattern, `\`, `\\`)))
+}
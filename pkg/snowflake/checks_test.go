@@ -0,0 +1,333 @@
+package snowflake
+
+import (
+	"context"
+	"testing"
+
+	"github.com/datablast-analytics/blast/pkg/pipeline"
+	"github.com/datablast-analytics/blast/pkg/query"
+	"github.com/datablast-analytics/blast/pkg/scheduler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNotNullCheck_Check(t *testing.T) {
+	t.Parallel()
+
+	runTestsFoCountZeroCheck(
+		t,
+		func(q *mockQuerierWithResult) *ColumnCheckOperator {
+			conn := new(mockConnectionFetcher)
+			conn.On("GetSfConnection", "test").Return(q, nil)
+			return &ColumnCheckOperator{conn: conn}
+		},
+		"SELECT count(*) FROM analytics.test_asset WHERE test_column IS NULL",
+		"column `test_column` has 5 null values",
+		&pipeline.ColumnCheck{
+			Name: "not_null",
+		},
+	)
+}
+
+func TestPositiveCheck_Check(t *testing.T) {
+	t.Parallel()
+
+	runTestsFoCountZeroCheck(
+		t,
+		func(q *mockQuerierWithResult) *ColumnCheckOperator {
+			conn := new(mockConnectionFetcher)
+			conn.On("GetSfConnection", "test").Return(q, nil)
+			return &ColumnCheckOperator{conn: conn}
+		},
+		"SELECT count(*) FROM analytics.test_asset WHERE test_column <= 0",
+		"column `test_column` has 5 non-positive values",
+		&pipeline.ColumnCheck{
+			Name: "positive",
+		},
+	)
+}
+
+func TestUniqueCheck_Check(t *testing.T) {
+	t.Parallel()
+
+	runTestsFoCountZeroCheck(
+		t,
+		func(q *mockQuerierWithResult) *ColumnCheckOperator {
+			conn := new(mockConnectionFetcher)
+			conn.On("GetSfConnection", "test").Return(q, nil)
+			return &ColumnCheckOperator{conn: conn}
+		},
+		"SELECT COUNT(test_column) - COUNT(DISTINCT test_column) FROM analytics.test_asset",
+		"column `test_column` has 5 non-unique values",
+		&pipeline.ColumnCheck{
+			Name: "unique",
+		},
+	)
+}
+
+func TestAcceptedValuesCheck_Check(t *testing.T) {
+	t.Parallel()
+
+	runTestsFoCountZeroCheck(
+		t,
+		func(q *mockQuerierWithResult) *ColumnCheckOperator {
+			conn := new(mockConnectionFetcher)
+			conn.On("GetSfConnection", "test").Return(q, nil)
+			return &ColumnCheckOperator{conn: conn}
+		},
+		"SELECT COUNT(*) FROM analytics.test_asset WHERE CAST(test_column as VARCHAR) NOT IN ('test','it''s')",
+		"column `test_column` has 5 rows that are not in the accepted values",
+		&pipeline.ColumnCheck{
+			Name: "accepted_values",
+			Value: pipeline.ColumnCheckValue{
+				StringArray: &[]string{"test", "it's"},
+			},
+		},
+	)
+
+	runTestsFoCountZeroCheck(
+		t,
+		func(q *mockQuerierWithResult) *ColumnCheckOperator {
+			conn := new(mockConnectionFetcher)
+			conn.On("GetSfConnection", "test").Return(q, nil)
+			return &ColumnCheckOperator{conn: conn}
+		},
+		"SELECT COUNT(*) FROM analytics.test_asset WHERE CAST(test_column as VARCHAR) NOT IN ('1','2')",
+		"column `test_column` has 5 rows that are not in the accepted values",
+		&pipeline.ColumnCheck{
+			Name: "accepted_values",
+			Value: pipeline.ColumnCheckValue{
+				IntArray: &[]int{1, 2},
+			},
+		},
+	)
+}
+
+func Test_quoteIdentifier(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		identifier string
+		want       string
+	}{
+		{
+			name:       "simple identifiers are left untouched",
+			identifier: "my_table",
+			want:       "my_table",
+		},
+		{
+			name:       "qualified identifiers are handled part by part",
+			identifier: "my_db.my_schema.my_table",
+			want:       "my_db.my_schema.my_table",
+		},
+		{
+			name:       "identifiers with special characters are quoted",
+			identifier: "my_schema.my-table",
+			want:       `my_schema."my-table"`,
+		},
+		{
+			name:       "quotes are escaped",
+			identifier: `some"column`,
+			want:       `"some""column"`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, quoteIdentifier(tt.identifier))
+		})
+	}
+}
+
+func TestNumericChecks_Check(t *testing.T) {
+	t.Parallel()
+
+	minValue := 3
+	runTestsFoCountZeroCheck(
+		t,
+		func(q *mockQuerierWithResult) *ColumnCheckOperator {
+			conn := new(mockConnectionFetcher)
+			conn.On("GetSfConnection", "test").Return(q, nil)
+			return &ColumnCheckOperator{conn: conn}
+		},
+		"SELECT count(*) FROM analytics.test_asset WHERE test_column < 3",
+		"column `test_column` has 5 values below 3",
+		&pipeline.ColumnCheck{
+			Name:  "min",
+			Value: pipeline.ColumnCheckValue{Int: &minValue},
+		},
+	)
+
+	runTestsFoCountZeroCheck(
+		t,
+		func(q *mockQuerierWithResult) *ColumnCheckOperator {
+			conn := new(mockConnectionFetcher)
+			conn.On("GetSfConnection", "test").Return(q, nil)
+			return &ColumnCheckOperator{conn: conn}
+		},
+		"SELECT count(*) FROM analytics.test_asset WHERE test_column NOT BETWEEN 0.5 AND 1.5",
+		"column `test_column` has 5 values outside of the range [0.5, 1.5]",
+		&pipeline.ColumnCheck{
+			Name:  "between",
+			Value: pipeline.ColumnCheckValue{StringArray: &[]string{"0.5", "1.5"}},
+		},
+	)
+
+	runTestsFoCountZeroCheck(
+		t,
+		func(q *mockQuerierWithResult) *ColumnCheckOperator {
+			conn := new(mockConnectionFetcher)
+			conn.On("GetSfConnection", "test").Return(q, nil)
+			return &ColumnCheckOperator{conn: conn}
+		},
+		"SELECT count(*) FROM analytics.test_asset WHERE test_column < 0",
+		"column `test_column` has 5 negative values",
+		&pipeline.ColumnCheck{Name: "non_negative"},
+	)
+}
+
+func TestStringChecks_Check(t *testing.T) {
+	t.Parallel()
+
+	pattern := `it's \d+`
+	runTestsFoCountZeroCheck(
+		t,
+		func(q *mockQuerierWithResult) *ColumnCheckOperator {
+			conn := new(mockConnectionFetcher)
+			conn.On("GetSfConnection", "test").Return(q, nil)
+			return &ColumnCheckOperator{conn: conn}
+		},
+		`SELECT count(*) FROM analytics.test_asset WHERE NOT REGEXP_LIKE(CAST(test_column as VARCHAR), 'it''s \\d+')`,
+		`column `+"`test_column`"+` has 5 values that do not match the pattern 'it's \d+'`,
+		&pipeline.ColumnCheck{
+			Name:  "regex",
+			Value: pipeline.ColumnCheckValue{String: &pattern},
+		},
+	)
+
+	maxLength := 12
+	runTestsFoCountZeroCheck(
+		t,
+		func(q *mockQuerierWithResult) *ColumnCheckOperator {
+			conn := new(mockConnectionFetcher)
+			conn.On("GetSfConnection", "test").Return(q, nil)
+			return &ColumnCheckOperator{conn: conn}
+		},
+		"SELECT count(*) FROM analytics.test_asset WHERE LENGTH(test_column) > 12",
+		"column `test_column` has 5 values longer than 12 characters",
+		&pipeline.ColumnCheck{
+			Name:  "max_length",
+			Value: pipeline.ColumnCheckValue{Int: &maxLength},
+		},
+	)
+}
+
+func TestRelationshipCheck_Check(t *testing.T) {
+	t.Parallel()
+
+	reference := "analytics.users.user id"
+	runTestsFoCountZeroCheck(
+		t,
+		func(q *mockQuerierWithResult) *ColumnCheckOperator {
+			conn := new(mockConnectionFetcher)
+			conn.On("GetSfConnection", "test").Return(q, nil)
+			return &ColumnCheckOperator{conn: conn}
+		},
+		`SELECT count(*) FROM analytics.test_asset AS child WHERE child.test_column IS NOT NULL AND NOT EXISTS (SELECT 1 FROM analytics.users AS parent WHERE parent."user id" = child.test_column)`,
+		"column `test_column` has 5 values that do not exist in `analytics.users`.`user id`",
+		&pipeline.ColumnCheck{
+			Name:  "relationship",
+			Value: pipeline.ColumnCheckValue{String: &reference},
+		},
+	)
+}
+
+func runTestsFoCountZeroCheck(t *testing.T, instanceBuilder func(q *mockQuerierWithResult) *ColumnCheckOperator, expectedQueryString string, expectedErrorMessage string, checkInstance *pipeline.ColumnCheck) {
+	expectedQuery := &query.Query{Query: expectedQueryString}
+	setupFunc := func(val [][]interface{}, err error) func(n *mockQuerierWithResult) {
+		return func(q *mockQuerierWithResult) {
+			q.On("Select", mock.Anything, expectedQuery).
+				Return(val, err).
+				Once()
+		}
+	}
+
+	checkError := func(message string) assert.ErrorAssertionFunc {
+		return func(t assert.TestingT, err error, i ...interface{}) bool {
+			return assert.EqualError(t, err, message)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(n *mockQuerierWithResult)
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "failed to run query",
+			setup:   setupFunc(nil, assert.AnError),
+			wantErr: assert.Error,
+		},
+		{
+			name:    "multiple results are returned",
+			setup:   setupFunc([][]interface{}{{1}, {2}}, nil),
+			wantErr: assert.Error,
+		},
+		{
+			name:    "non-numeric results are rejected",
+			setup:   setupFunc([][]interface{}{{"abc"}}, nil),
+			wantErr: assert.Error,
+		},
+		{
+			name:    "null values found",
+			setup:   setupFunc([][]interface{}{{5}}, nil),
+			wantErr: checkError(expectedErrorMessage),
+		},
+		{
+			name:    "null values found with string results",
+			setup:   setupFunc([][]interface{}{{"5"}}, nil),
+			wantErr: checkError(expectedErrorMessage),
+		},
+		{
+			name:    "no null values found, test passed",
+			setup:   setupFunc([][]interface{}{{"0"}}, nil),
+			wantErr: assert.NoError,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			q := new(mockQuerierWithResult)
+			tt.setup(q)
+
+			n := instanceBuilder(q)
+
+			testInstance := &scheduler.ColumnCheckInstance{
+				AssetInstance: &scheduler.AssetInstance{
+					Asset: &pipeline.Asset{
+						Name: "analytics.test_asset",
+						Type: "sf.sql",
+					},
+					Pipeline: &pipeline.Pipeline{
+						Name: "test",
+						DefaultConnections: map[string]string{
+							"snowflake": "test",
+						},
+					},
+				},
+				Column: &pipeline.Column{
+					Name: "test_column",
+				},
+				Check: checkInstance,
+			}
+
+			tt.wantErr(t, n.Run(context.Background(), testInstance))
+			defer q.AssertExpectations(t)
+		})
+	}
+}
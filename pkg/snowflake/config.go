@@ -6,24 +6,26 @@ import (
 )
 
 type Config struct {
-	Account  string `envconfig:"SNOWFLAKE_ACCOUNT"`
-	Username string `envconfig:"SNOWFLAKE_USERNAME"`
-	Password string `envconfig:"SNOWFLAKE_PASSWORD"`
-	Region   string `envconfig:"SNOWFLAKE_REGION"`
-	Role     string `envconfig:"SNOWFLAKE_ROLE"`
-	Database string `envconfig:"SNOWFLAKE_DATABASE"`
-	Schema   string `envconfig:"SNOWFLAKE_SCHEMA"`
+	Account   string `envconfig:"SNOWFLAKE_ACCOUNT"`
+	Username  string `envconfig:"SNOWFLAKE_USERNAME"`
+	Password  string `envconfig:"SNOWFLAKE_PASSWORD"`
+	Region    string `envconfig:"SNOWFLAKE_REGION"`
+	Role      string `envconfig:"SNOWFLAKE_ROLE"`
+	Database  string `envconfig:"SNOWFLAKE_DATABASE"`
+	Schema    string `envconfig:"SNOWFLAKE_SCHEMA"`
+	Warehouse string `envconfig:"SNOWFLAKE_WAREHOUSE"`
 }
 
 func (c Config) DSN() (string, error) {
 	snowflakeConfig := gosnowflake.Config{
-		Account:  c.Account,
-		User:     c.Username,
-		Password: c.Password,
-		Region:   c.Region,
-		Role:     c.Role,
-		Database: c.Database,
-		Schema:   c.Schema,
+		Account:   c.Account,
+		User:      c.Username,
+		Password:  c.Password,
+		Region:    c.Region,
+		Role:      c.Role,
+		Database:  c.Database,
+		Schema:    c.Schema,
+		Warehouse: c.Warehouse,
 	}
 
 	return gosnowflake.DSN(&snowflakeConfig)
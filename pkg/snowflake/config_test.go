@@ -11,10 +11,11 @@ func TestConfig_DSN(t *testing.T) {
 	t.Parallel()
 
 	type fields struct {
-		Account  string
-		Username string
-		Password string
-		Region   string
+		Account   string
+		Username  string
+		Password  string
+		Region    string
+		Warehouse string
 	}
 	tests := []struct {
 		name    string
@@ -37,6 +38,23 @@ func TestConfig_DSN(t *testing.T) {
 				Region:   "us-east-1",
 			},
 		},
+		{
+			name: "warehouse is passed to the connection",
+			fields: fields{
+				Account:   "my-account",
+				Username:  "datablast",
+				Password:  "qwerty123",
+				Region:    "us-east-1",
+				Warehouse: "my-warehouse",
+			},
+			want: &gosnowflake.Config{
+				Account:   "my-account",
+				User:      "datablast",
+				Password:  "qwerty123",
+				Region:    "us-east-1",
+				Warehouse: "my-warehouse",
+			},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -44,10 +62,11 @@ func TestConfig_DSN(t *testing.T) {
 			t.Parallel()
 
 			c := Config{
-				Account:  tt.fields.Account,
-				Username: tt.fields.Username,
-				Password: tt.fields.Password,
-				Region:   tt.fields.Region,
+				Account:   tt.fields.Account,
+				Username:  tt.fields.Username,
+				Password:  tt.fields.Password,
+				Region:    tt.fields.Region,
+				Warehouse: tt.fields.Warehouse,
 			}
 			got, err := c.DSN()
 			if tt.wantErr {
@@ -0,0 +1,45 @@
+package snowflake
+
+import (
+	"context"
+
+	"github.com/datablast-analytics/blast/pkg/checks"
+	"github.com/datablast-analytics/blast/pkg/query"
+	"github.com/datablast-analytics/blast/pkg/scheduler"
+	"github.com/pkg/errors"
+)
+
+type renderer interface {
+	Render(query string) string
+}
+
+type CustomCheckOperator struct {
+	conn     connectionFetcher
+	renderer renderer
+}
+
+func NewCustomCheckOperator(manager connectionFetcher, renderer renderer) *CustomCheckOperator {
+	return &CustomCheckOperator{
+		conn:     manager,
+		renderer: renderer,
+	}
+}
+
+func (o CustomCheckOperator) Run(ctx context.Context, ti scheduler.TaskInstance) error {
+	instance, ok := ti.(*scheduler.CustomCheckInstance)
+	if !ok {
+		return errors.New("cannot run a non-custom check instance")
+	}
+
+	q, err := o.conn.GetSfConnection(instance.Pipeline.GetConnectionNameForAsset(instance.GetAsset()))
+	if err != nil {
+		return errors.Wrapf(err, "failed to get connection for custom check '%s'", instance.Check.Name)
+	}
+
+	res, err := q.Select(ctx, &query.Query{Query: o.renderer.Render(instance.Check.Query)})
+	if err != nil {
+		return errors.Wrapf(err, "failed custom check '%s'", instance.Check.Name)
+	}
+
+	return checks.CompareCustomCheckResult(instance.Check, res)
+}
@@ -0,0 +1,99 @@
+package snowflake
+
+import (
+	"context"
+	"testing"
+
+	"github.com/datablast-analytics/blast/pkg/jinja"
+	"github.com/datablast-analytics/blast/pkg/pipeline"
+	"github.com/datablast-analytics/blast/pkg/query"
+	"github.com/datablast-analytics/blast/pkg/scheduler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCustomCheckOperator_Run(t *testing.T) {
+	t.Parallel()
+
+	expectedQuery := &query.Query{Query: "SELECT count(*) > 0 FROM analytics.test_asset WHERE dt = '2023-01-01'"}
+
+	tests := []struct {
+		name     string
+		expected int64
+		result   [][]interface{}
+		err      error
+		wantErr  string
+	}{
+		{
+			name:    "failed to run query",
+			err:     assert.AnError,
+			wantErr: "failed custom check 'has_rows': " + assert.AnError.Error(),
+		},
+		{
+			name:    "multiple results are returned",
+			result:  [][]interface{}{{"1"}, {"2"}},
+			wantErr: "unexpected result from query during custom check 'has_rows', the query must return a single value",
+		},
+		{
+			name:     "numeric string matches the expected value",
+			expected: 5,
+			result:   [][]interface{}{{"5"}},
+		},
+		{
+			name:     "boolean string is compared as an integer",
+			expected: 1,
+			result:   [][]interface{}{{"true"}},
+		},
+		{
+			name:    "result does not match the expected value",
+			result:  [][]interface{}{{"true"}},
+			wantErr: "custom check 'has_rows' has returned 1 instead of 0",
+		},
+		{
+			name:    "unparseable result",
+			result:  [][]interface{}{{"some value"}},
+			wantErr: "unexpected result from query during custom check 'has_rows', cannot cast result to integer or boolean",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			q := new(mockQuerierWithResult)
+			q.On("Select", mock.Anything, expectedQuery).Return(tt.result, tt.err).Once()
+			defer q.AssertExpectations(t)
+
+			conn := new(mockConnectionFetcher)
+			conn.On("GetSfConnection", "test").Return(q, nil)
+
+			instance := &scheduler.CustomCheckInstance{
+				AssetInstance: &scheduler.AssetInstance{
+					Asset: &pipeline.Asset{
+						Name: "analytics.test_asset",
+						Type: "sf.sql",
+					},
+					Pipeline: &pipeline.Pipeline{
+						Name: "test",
+						DefaultConnections: map[string]string{
+							"snowflake": "test",
+						},
+					},
+				},
+				Check: &pipeline.CustomCheck{
+					Name:  "has_rows",
+					Query: "SELECT count(*) > 0 FROM analytics.test_asset WHERE dt = '{{ dt }}'",
+					Value: tt.expected,
+				},
+			}
+
+			operator := NewCustomCheckOperator(conn, jinja.NewRenderer(jinja.Context{"dt": "2023-01-01"}))
+			err := operator.Run(context.Background(), instance)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -9,19 +9,30 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
 	"github.com/snowflakedb/gosnowflake"
-	"go.uber.org/zap"
 )
 
 const (
 	invalidQueryError = "SQL compilation error"
 )
 
+type Querier interface {
+	RunQueryWithoutResult(ctx context.Context, query *query.Query) error
+}
+
+type Selector interface {
+	Select(ctx context.Context, query *query.Query) ([][]interface{}, error)
+}
+
+type SfClient interface {
+	Querier
+	Selector
+}
+
 type DB struct {
-	conn   *sqlx.DB
-	logger *zap.SugaredLogger
+	conn *sqlx.DB
 }
 
-func NewDB(c *Config, logger *zap.SugaredLogger) (*DB, error) {
+func NewDB(c *Config) (*DB, error) {
 	dsn, err := c.DSN()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create DSN")
@@ -29,12 +40,13 @@ func NewDB(c *Config, logger *zap.SugaredLogger) (*DB, error) {
 
 	gosnowflake.GetLogger().SetOutput(io.Discard)
 
-	db, err := sqlx.Connect("snowflake", dsn)
+	// the connection is opened lazily so that registering a connection does not require the account to be reachable
+	db, err := sqlx.Open("snowflake", dsn)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to connect to snowflake")
 	}
 
-	return &DB{conn: db, logger: logger}, nil
+	return &DB{conn: db}, nil
 }
 
 func (db DB) IsValid(ctx context.Context, query *query.Query) (bool, error) {
@@ -48,15 +60,7 @@ func (db DB) IsValid(ctx context.Context, query *query.Query) (bool, error) {
 		err = rows.Err()
 	}
 
-	if err != nil {
-		errorMessage := err.Error()
-		if strings.Contains(errorMessage, invalidQueryError) {
-			errorSegments := strings.Split(errorMessage, "\n")
-			if len(errorSegments) > 1 {
-				err = errors.New(errorSegments[1])
-			}
-		}
-	}
+	err = formatError(err)
 
 	if rows != nil {
 		defer rows.Close()
@@ -64,3 +68,64 @@ func (db DB) IsValid(ctx context.Context, query *query.Query) (bool, error) {
 
 	return err == nil, err
 }
+
+func (db DB) RunQueryWithoutResult(ctx context.Context, query *query.Query) error {
+	ctx, err := gosnowflake.WithMultiStatement(ctx, 0)
+	if err != nil {
+		return errors.Wrap(err, "failed to create snowflake context")
+	}
+
+	_, err = db.conn.ExecContext(ctx, query.ToDryRunQuery())
+	return formatError(err)
+}
+
+func (db DB) Select(ctx context.Context, query *query.Query) ([][]interface{}, error) {
+	rows, err := db.conn.QueryContext(ctx, query.String())
+	if err != nil {
+		return nil, formatError(err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the columns of the result")
+	}
+
+	result := make([][]interface{}, 0)
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+
+		err = rows.Scan(pointers...)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan the row")
+		}
+
+		result = append(result, values)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, formatError(err)
+	}
+
+	return result, nil
+}
+
+func formatError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	errorMessage := err.Error()
+	if strings.Contains(errorMessage, invalidQueryError) {
+		errorSegments := strings.Split(errorMessage, "\n")
+		if len(errorSegments) > 1 {
+			return errors.New(errorSegments[1])
+		}
+	}
+
+	return err
+}
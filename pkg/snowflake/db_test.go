@@ -89,3 +89,125 @@ func TestDB_IsValid(t *testing.T) {
 		})
 	}
 }
+
+func TestDB_RunQueryWithoutResult(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		mockConnection func(mock sqlmock.Sqlmock)
+		query          query.Query
+		wantErr        bool
+		errorMessage   string
+	}{
+		{
+			name: "simple query is executed with the variable definitions",
+			mockConnection: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("set variable1 = 1;\nCREATE TABLE x AS SELECT $variable1;").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			query: query.Query{
+				VariableDefinitions: []string{"set variable1 = 1"},
+				Query:               "CREATE TABLE x AS SELECT $variable1",
+			},
+		},
+		{
+			name: "compilation errors are cleaned up",
+			mockConnection: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`some broken query;`).
+					WillReturnError(fmt.Errorf("%s\nsome actual error", invalidQueryError))
+			},
+			query: query.Query{
+				Query: "some broken query",
+			},
+			wantErr:      true,
+			errorMessage: "some actual error",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer mockDB.Close()
+			sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+
+			tt.mockConnection(mock)
+			db := DB{conn: sqlxDB}
+
+			err = db.RunQueryWithoutResult(context.Background(), &tt.query)
+			if tt.wantErr {
+				require.Error(t, err)
+				require.Equal(t, tt.errorMessage, err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDB_Select(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		mockConnection func(mock sqlmock.Sqlmock)
+		query          query.Query
+		want           [][]interface{}
+		wantErr        bool
+	}{
+		{
+			name: "rows are returned as values",
+			mockConnection: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT id, name FROM users`).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "john").AddRow(2, "jane"))
+			},
+			query: query.Query{
+				Query: "SELECT id, name FROM users",
+			},
+			want: [][]interface{}{
+				{int64(1), "john"},
+				{int64(2), "jane"},
+			},
+		},
+		{
+			name: "errors are propagated",
+			mockConnection: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT id, name FROM users`).
+					WillReturnError(errors.New("something went wrong"))
+			},
+			query: query.Query{
+				Query: "SELECT id, name FROM users",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer mockDB.Close()
+			sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+
+			tt.mockConnection(mock)
+			db := DB{conn: sqlxDB}
+
+			got, err := db.Select(context.Background(), &tt.query)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, tt.want, got)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
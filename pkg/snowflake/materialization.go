@@ -0,0 +1,84 @@
+package snowflake
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/datablast-analytics/blast/pkg/pipeline"
+)
+
+type Materializer struct{}
+
+func (m Materializer) Render(task *pipeline.Asset, query string) (string, error) {
+	mat := task.Materialization
+	if mat.Type == pipeline.MaterializationTypeNone {
+		return query, nil
+	}
+
+	if mat.Type == pipeline.MaterializationTypeView {
+		return fmt.Sprintf("CREATE OR REPLACE VIEW %s AS\n%s", quoteIdentifier(task.Name), query), nil
+	}
+
+	if mat.Type == pipeline.MaterializationTypeTable {
+		strategy := mat.Strategy
+		if strategy == pipeline.MaterializationStrategyNone {
+			strategy = pipeline.MaterializationStrategyCreateReplace
+		}
+
+		if strategy == pipeline.MaterializationStrategyAppend {
+			return fmt.Sprintf("INSERT INTO %s %s", quoteIdentifier(task.Name), query), nil
+		}
+
+		if strategy == pipeline.MaterializationStrategyCreateReplace {
+			return buildCreateReplaceQuery(task, query, mat)
+		}
+
+		if strategy == pipeline.MaterializationStrategyDeleteInsert {
+			return buildIncrementalQuery(task, query, mat, strategy)
+		}
+	}
+
+	return "", fmt.Errorf("unsupported materialization type `%s`", mat.Type)
+}
+
+// buildIncrementalQuery creates the temporary table before opening the transaction since DDL statements commit
+// any open transaction implicitly in Snowflake.
+func buildIncrementalQuery(task *pipeline.Asset, query string, mat pipeline.Materialization, strategy pipeline.MaterializationStrategy) (string, error) {
+	if mat.IncrementalKey == "" {
+		return "", fmt.Errorf("materialization strategy %s requires the `incremental_key` field to be set", strategy)
+	}
+
+	tableName := quoteIdentifier(task.Name)
+	incrementalKey := quoteIdentifier(mat.IncrementalKey)
+	queries := []string{
+		fmt.Sprintf("CREATE OR REPLACE TEMPORARY TABLE __blast_tmp AS %s", query),
+		"BEGIN TRANSACTION",
+		fmt.Sprintf("DELETE FROM %s WHERE %s IN (SELECT DISTINCT %s FROM __blast_tmp)", tableName, incrementalKey, incrementalKey),
+		fmt.Sprintf("INSERT INTO %s SELECT * FROM __blast_tmp", tableName),
+		"COMMIT",
+	}
+
+	return strings.Join(queries, ";\n") + ";", nil
+}
+
+// buildCreateReplaceQuery creates a transient table, since the tables are fully rebuilt on every run there is no need
+// to pay for the fail-safe storage. Snowflake has no user-defined partitions, therefore the `partition_by` column
+// is used as the leading clustering key.
+func buildCreateReplaceQuery(task *pipeline.Asset, query string, mat pipeline.Materialization) (string, error) {
+	clusterKeys := make([]string, 0, len(mat.ClusterBy)+1)
+	if mat.PartitionBy != "" {
+		clusterKeys = append(clusterKeys, mat.PartitionBy)
+	}
+	clusterKeys = append(clusterKeys, mat.ClusterBy...)
+
+	clusterByClause := ""
+	if len(clusterKeys) > 0 {
+		for i, key := range clusterKeys {
+			clusterKeys[i] = quoteIdentifier(key)
+		}
+
+		clusterByClause = fmt.Sprintf(" CLUSTER BY (%s)", strings.Join(clusterKeys, ", "))
+	}
+
+	return fmt.Sprintf("CREATE OR REPLACE TRANSIENT TABLE %s%s AS\n%s", quoteIdentifier(task.Name), clusterByClause, query), nil
+}
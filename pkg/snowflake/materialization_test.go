@@ -0,0 +1,143 @@
+package snowflake
+
+import (
+	"testing"
+
+	"github.com/datablast-analytics/blast/pkg/pipeline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaterializer_Render(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		task    *pipeline.Asset
+		query   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "no materialization, return raw query",
+			task:  &pipeline.Asset{},
+			query: "SELECT 1",
+			want:  "SELECT 1",
+		},
+		{
+			name: "materialize to a view",
+			task: &pipeline.Asset{
+				Name: "my.asset",
+				Materialization: pipeline.Materialization{
+					Type: pipeline.MaterializationTypeView,
+				},
+			},
+			query: "SELECT 1",
+			want:  "CREATE OR REPLACE VIEW my.asset AS\nSELECT 1",
+		},
+		{
+			name: "materialize to a table, no partition or cluster, default to create+replace",
+			task: &pipeline.Asset{
+				Name: "my.asset",
+				Materialization: pipeline.Materialization{
+					Type: pipeline.MaterializationTypeTable,
+				},
+			},
+			query: "SELECT 1",
+			want:  "CREATE OR REPLACE TRANSIENT TABLE my.asset AS\nSELECT 1",
+		},
+		{
+			name: "materialize to a table with partition, used as the clustering key",
+			task: &pipeline.Asset{
+				Name: "my.asset",
+				Materialization: pipeline.Materialization{
+					Type:        pipeline.MaterializationTypeTable,
+					Strategy:    pipeline.MaterializationStrategyCreateReplace,
+					PartitionBy: "dt",
+				},
+			},
+			query: "SELECT 1",
+			want:  "CREATE OR REPLACE TRANSIENT TABLE my.asset CLUSTER BY (dt) AS\nSELECT 1",
+		},
+		{
+			name: "materialize to a table with partition and cluster",
+			task: &pipeline.Asset{
+				Name: "my.asset",
+				Materialization: pipeline.Materialization{
+					Type:        pipeline.MaterializationTypeTable,
+					Strategy:    pipeline.MaterializationStrategyCreateReplace,
+					PartitionBy: "dt",
+					ClusterBy:   []string{"event_name", "event-type"},
+				},
+			},
+			query: "SELECT 1",
+			want:  "CREATE OR REPLACE TRANSIENT TABLE my.asset CLUSTER BY (dt, event_name, \"event-type\") AS\nSELECT 1",
+		},
+		{
+			name: "materialize to a table with append",
+			task: &pipeline.Asset{
+				Name: "my.asset",
+				Materialization: pipeline.Materialization{
+					Type:     pipeline.MaterializationTypeTable,
+					Strategy: pipeline.MaterializationStrategyAppend,
+				},
+			},
+			query: "SELECT 1",
+			want:  "INSERT INTO my.asset SELECT 1",
+		},
+		{
+			name: "incremental strategies require the incremental_key to be set",
+			task: &pipeline.Asset{
+				Name: "my.asset",
+				Materialization: pipeline.Materialization{
+					Type:     pipeline.MaterializationTypeTable,
+					Strategy: pipeline.MaterializationStrategyDeleteInsert,
+				},
+			},
+			query:   "SELECT 1",
+			wantErr: true,
+		},
+		{
+			name: "delete+insert builds a proper transaction",
+			task: &pipeline.Asset{
+				Name: "my.asset",
+				Materialization: pipeline.Materialization{
+					Type:           pipeline.MaterializationTypeTable,
+					Strategy:       pipeline.MaterializationStrategyDeleteInsert,
+					IncrementalKey: "dt",
+				},
+			},
+			query: "SELECT 1",
+			want: "CREATE OR REPLACE TEMPORARY TABLE __blast_tmp AS SELECT 1;\n" +
+				"BEGIN TRANSACTION;\n" +
+				"DELETE FROM my.asset WHERE dt IN (SELECT DISTINCT dt FROM __blast_tmp);\n" +
+				"INSERT INTO my.asset SELECT * FROM __blast_tmp;\n" +
+				"COMMIT;",
+		},
+		{
+			name: "unsupported materialization types are rejected",
+			task: &pipeline.Asset{
+				Name: "my.asset",
+				Materialization: pipeline.Materialization{
+					Type: "some-other-type",
+				},
+			},
+			query:   "SELECT 1",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := Materializer{}
+			render, err := m.Render(tt.task, tt.query)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.want, render)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package snowflake
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/datablast-analytics/blast/pkg/checks"
+	"github.com/datablast-analytics/blast/pkg/pipeline"
+	"github.com/datablast-analytics/blast/pkg/query"
+	"github.com/datablast-analytics/blast/pkg/scheduler"
+	"github.com/pkg/errors"
+)
+
+type materializer interface {
+	Render(task *pipeline.Asset, query string) (string, error)
+}
+
+type queryExtractor interface {
+	ExtractQueriesFromFile(filepath string) ([]*query.Query, error)
+}
+
+type connectionFetcher interface {
+	GetSfConnection(name string) (SfClient, error)
+}
+
+type BasicOperator struct {
+	connection connectionFetcher
+	builder    *QueryBuilder
+}
+
+func NewBasicOperator(conn connectionFetcher, extractor queryExtractor, materializer materializer) *BasicOperator {
+	return &BasicOperator{
+		connection: conn,
+		builder:    NewQueryBuilder(extractor, materializer),
+	}
+}
+
+func (o BasicOperator) Run(ctx context.Context, ti scheduler.TaskInstance) error {
+	return o.RunTask(ctx, ti.GetPipeline(), ti.GetAsset())
+}
+
+func (o BasicOperator) RunTask(ctx context.Context, p *pipeline.Pipeline, t *pipeline.Asset) error {
+	q, err := o.builder.Build(t)
+	if err != nil {
+		return err
+	}
+
+	if q == nil {
+		return nil
+	}
+
+	conn, err := o.connection.GetSfConnection(p.GetConnectionNameForAsset(t))
+	if err != nil {
+		return err
+	}
+
+	return conn.RunQueryWithoutResult(ctx, q)
+}
+
+// QueryBuilder builds the query that is sent to Snowflake for an asset. The whole file is sent as a single request so
+// that the session statements and the transactions in it apply to the rest of the file, the run and render commands
+// share it so that the rendered query is the one that is executed.
+type QueryBuilder struct {
+	extractor    queryExtractor
+	materializer materializer
+}
+
+func NewQueryBuilder(extractor queryExtractor, materializer materializer) *QueryBuilder {
+	return &QueryBuilder{
+		extractor:    extractor,
+		materializer: materializer,
+	}
+}
+
+// Build returns nil if there is nothing to run in the asset file.
+func (b QueryBuilder) Build(t *pipeline.Asset) (*query.Query, error) {
+	queries, err := b.extractor.ExtractQueriesFromFile(t.ExecutableFile.Path)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot extract queries from the task file")
+	}
+
+	if len(queries) == 0 {
+		return nil, nil
+	}
+
+	statements := query.SplitStatements(queries[0].Query)
+	if len(statements) == 0 {
+		return nil, nil
+	}
+
+	if t.Materialization.Type == pipeline.MaterializationTypeNone {
+		return queries[0], nil
+	}
+
+	// the session statements are kept before the materialized query, any other statement would be materialized
+	// together with it.
+	last := len(statements) - 1
+	for _, statement := range statements[:last] {
+		if !isSessionStatement(statement) {
+			return nil, errors.New("cannot enable materialization for tasks with multiple queries")
+		}
+	}
+
+	if isSessionStatement(statements[last]) {
+		return nil, errors.New("cannot enable materialization for tasks without a query")
+	}
+
+	materialized, err := b.materializer.Render(t, statements[last])
+	if err != nil {
+		return nil, err
+	}
+
+	return &query.Query{
+		Query: strings.Join(append(statements[:last:last], materialized), ";\n"),
+	}, nil
+}
+
+// isSessionStatement tells if the statement only changes the session, e.g. the database in use or a variable.
+func isSessionStatement(statement string) bool {
+	fields := strings.FieldsFunc(strings.ToLower(statement), func(r rune) bool { return !unicode.IsLetter(r) })
+	if len(fields) == 0 {
+		return false
+	}
+
+	switch fields[0] {
+	case "use", "set", "unset", "declare":
+		return true
+	case "alter":
+		return len(fields) > 1 && fields[1] == "session"
+	}
+
+	return false
+}
+
+type ColumnCheckOperator struct {
+	conn connectionFetcher
+}
+
+func NewColumnCheckOperator(manager connectionFetcher) (*ColumnCheckOperator, error) {
+	return &ColumnCheckOperator{conn: manager}, nil
+}
+
+func (o ColumnCheckOperator) Run(ctx context.Context, ti scheduler.TaskInstance) error {
+	test, ok := ti.(*scheduler.ColumnCheckInstance)
+	if !ok {
+		return errors.New("cannot run a non-column test instance")
+	}
+
+	check, err := checks.NewColumnCheck(checkDialect{}, test)
+	if err != nil {
+		return err
+	}
+
+	q, err := o.conn.GetSfConnection(test.Pipeline.GetConnectionNameForAsset(test.GetAsset()))
+	if err != nil {
+		return errors.Wrapf(err, "failed to get connection for '%s' check", test.Check.Name)
+	}
+
+	return check.Run(ctx, q)
+}
@@ -0,0 +1,226 @@
+package snowflake
+
+import (
+	"context"
+	"testing"
+
+	"github.com/datablast-analytics/blast/pkg/pipeline"
+	"github.com/datablast-analytics/blast/pkg/query"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockQuerierWithResult struct {
+	mock.Mock
+}
+
+func (m *mockQuerierWithResult) Select(ctx context.Context, q *query.Query) ([][]interface{}, error) {
+	args := m.Called(ctx, q)
+	get := args.Get(0)
+	if get == nil {
+		return nil, args.Error(1)
+	}
+
+	return get.([][]interface{}), args.Error(1)
+}
+
+func (m *mockQuerierWithResult) RunQueryWithoutResult(ctx context.Context, query *query.Query) error {
+	args := m.Called(ctx, query)
+	return args.Error(0)
+}
+
+type mockConnectionFetcher struct {
+	mock.Mock
+}
+
+func (m *mockConnectionFetcher) GetSfConnection(name string) (SfClient, error) {
+	args := m.Called(name)
+	get := args.Get(0)
+	if get == nil {
+		return nil, args.Error(1)
+	}
+
+	return get.(SfClient), args.Error(1)
+}
+
+type mockExtractor struct {
+	mock.Mock
+}
+
+func (m *mockExtractor) ExtractQueriesFromFile(filepath string) ([]*query.Query, error) {
+	res := m.Called(filepath)
+	return res.Get(0).([]*query.Query), res.Error(1)
+}
+
+type mockMaterializer struct {
+	mock.Mock
+}
+
+func (m *mockMaterializer) Render(t *pipeline.Asset, query string) (string, error) {
+	res := m.Called(t, query)
+	return res.Get(0).(string), res.Error(1)
+}
+
+func TestBasicOperator_RunTask(t *testing.T) {
+	t.Parallel()
+
+	type fields struct {
+		q *mockQuerierWithResult
+		e *mockExtractor
+		m *mockMaterializer
+	}
+
+	// the file is extracted as a whole, the same way the run command does
+	wholeFile := func(f *fields, content string) {
+		f.e.On("ExtractQueriesFromFile", "test-file.sql").
+			Return([]*query.Query{{Query: content}}, nil)
+	}
+
+	tableAsset := &pipeline.Asset{
+		ExecutableFile: pipeline.ExecutableFile{
+			Path: "test-file.sql",
+		},
+		Materialization: pipeline.Materialization{
+			Type: pipeline.MaterializationTypeTable,
+		},
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(f *fields)
+		asset   *pipeline.Asset
+		wantErr bool
+	}{
+		{
+			name: "failed to extract queries",
+			setup: func(f *fields) {
+				f.e.On("ExtractQueriesFromFile", "test-file.sql").
+					Return([]*query.Query{}, errors.New("failed to extract queries"))
+			},
+			asset: &pipeline.Asset{
+				ExecutableFile: pipeline.ExecutableFile{
+					Path: "test-file.sql",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "no queries found in file",
+			setup: func(f *fields) {
+				wholeFile(f, "-- nothing to run here\n;")
+			},
+			asset: &pipeline.Asset{
+				ExecutableFile: pipeline.ExecutableFile{
+					Path: "test-file.sql",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "query returned an error",
+			setup: func(f *fields) {
+				wholeFile(f, "select * from users")
+
+				f.q.On("RunQueryWithoutResult", mock.Anything, &query.Query{Query: "select * from users"}).
+					Return(errors.New("failed to run query"))
+			},
+			asset: &pipeline.Asset{
+				ExecutableFile: pipeline.ExecutableFile{
+					Path: "test-file.sql",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "the whole file is executed as it is in a single request",
+			setup: func(f *fields) {
+				content := "USE DATABASE analytics;\nSET x = 1;\nBEGIN;\nINSERT INTO logs SELECT 'a;b', $x;\nCOMMIT;"
+				wholeFile(f, content)
+
+				f.q.On("RunQueryWithoutResult", mock.Anything, &query.Query{Query: content}).
+					Return(nil).
+					Once()
+			},
+			asset: &pipeline.Asset{
+				ExecutableFile: pipeline.ExecutableFile{
+					Path: "test-file.sql",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "multiple queries found but materialization is enabled, should fail",
+			setup: func(f *fields) {
+				wholeFile(f, "select 1; select 2")
+			},
+			asset:   tableAsset,
+			wantErr: true,
+		},
+		{
+			name: "only session statements found but materialization is enabled, should fail",
+			setup: func(f *fields) {
+				wholeFile(f, "use database analytics; set x = 1")
+			},
+			asset:   tableAsset,
+			wantErr: true,
+		},
+		{
+			name: "materialization failed",
+			setup: func(f *fields) {
+				wholeFile(f, "select * from users")
+
+				f.m.On("Render", mock.Anything, "select * from users").
+					Return("", errors.New("failed to materialize"))
+			},
+			asset:   tableAsset,
+			wantErr: true,
+		},
+		{
+			name: "query successfully executed with materialization, session statements are kept",
+			setup: func(f *fields) {
+				wholeFile(f, "USE DATABASE analytics;\n-- the separator; in a comment\nSET x = 'a;b';\nselect $x;\n")
+
+				f.m.On("Render", mock.Anything, "select $x").
+					Return("CREATE OR REPLACE TRANSIENT TABLE x AS select $x", nil)
+
+				f.q.On("RunQueryWithoutResult", mock.Anything, &query.Query{Query: "USE DATABASE analytics;\nSET x = 'a;b';\nCREATE OR REPLACE TRANSIENT TABLE x AS select $x"}).
+					Return(nil)
+			},
+			asset:   tableAsset,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := new(mockQuerierWithResult)
+			extractor := new(mockExtractor)
+			mat := new(mockMaterializer)
+			conn := new(mockConnectionFetcher)
+			conn.On("GetSfConnection", mock.Anything).Return(client, nil)
+
+			if tt.setup != nil {
+				tt.setup(&fields{
+					q: client,
+					e: extractor,
+					m: mat,
+				})
+			}
+
+			o := NewBasicOperator(conn, extractor, mat)
+
+			err := o.RunTask(context.Background(), &pipeline.Pipeline{}, tt.asset)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			client.AssertExpectations(t)
+			mat.AssertExpectations(t)
+		})
+	}
+}
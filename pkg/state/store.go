@@ -0,0 +1,176 @@
+package state
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/datablast-analytics/blast/pkg/scheduler"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+const stateFileExtension = ".json"
+
+// RunState is the snapshot of a single pipeline run that is persisted on the disk, it contains everything needed to
+// resume the run later on. The path is the one the run was started with, it can point to a pipeline or an asset.
+type RunState struct {
+	ID          string                                  `json:"id"`
+	Pipeline    string                                  `json:"pipeline"`
+	Path        string                                  `json:"path"`
+	Environment string                                  `json:"environment"`
+	Trigger     string                                  `json:"trigger,omitempty"`
+	StartDate   time.Time                               `json:"start_date"`
+	EndDate     time.Time                               `json:"end_date"`
+	UpdatedAt   time.Time                               `json:"updated_at"`
+	Instances   map[string]scheduler.TaskInstanceStatus `json:"instances"`
+}
+
+type configManager interface {
+	EnsureRunStateDirExists() error
+	RunStateDir() string
+}
+
+type Store struct {
+	fs     afero.Fs
+	config configManager
+}
+
+func NewStore(fs afero.Fs, config configManager) *Store {
+	return &Store{
+		fs:     fs,
+		config: config,
+	}
+}
+
+func (s *Store) Save(state *RunState) error {
+	err := s.config.EnsureRunStateDirExists()
+	if err != nil {
+		return err
+	}
+
+	statePath, err := s.statePath(state.ID)
+	if err != nil {
+		return err
+	}
+
+	state.UpdatedAt = time.Now()
+	content, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize the run state")
+	}
+
+	err = s.writeAtomically(statePath, content)
+	if err != nil {
+		return errors.Wrapf(err, "failed to write the state for run '%s'", state.ID)
+	}
+
+	return nil
+}
+
+// writeAtomically writes the content to a temporary file next to the given path and renames it, so that a crash in the
+// middle of the write leaves the previous state intact.
+func (s *Store) writeAtomically(path string, content []byte) error {
+	tmp, err := afero.TempFile(s.fs, filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	_, err = tmp.Write(content)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = s.fs.Remove(tmp.Name())
+		return err
+	}
+
+	err = s.fs.Rename(tmp.Name(), path)
+	if err != nil {
+		_ = s.fs.Remove(tmp.Name())
+		return err
+	}
+
+	return nil
+}
+
+func (s *Store) Load(runID string) (*RunState, error) {
+	err := s.config.EnsureRunStateDirExists()
+	if err != nil {
+		return nil, err
+	}
+
+	statePath, err := s.statePath(runID)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := afero.ReadFile(s.fs, statePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the state for run '%s'", runID)
+	}
+
+	var state RunState
+	err = json.Unmarshal(content, &state)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the state for run '%s'", runID)
+	}
+
+	return &state, nil
+}
+
+// Last returns the most recently updated run state.
+func (s *Store) Last() (*RunState, error) {
+	err := s.config.EnsureRunStateDirExists()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := afero.ReadDir(s.fs, s.config.RunStateDir())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the run states")
+	}
+
+	var last *RunState
+	for _, file := range files {
+		runID := strings.TrimSuffix(file.Name(), stateFileExtension)
+		if file.IsDir() || !strings.HasSuffix(file.Name(), stateFileExtension) || !isValidRunID(runID) {
+			continue
+		}
+
+		state, err := s.Load(runID)
+		if err != nil {
+			return nil, err
+		}
+
+		if last == nil || state.UpdatedAt.After(last.UpdatedAt) {
+			last = state
+		}
+	}
+
+	if last == nil {
+		return nil, errors.New("there are no previous runs to resume")
+	}
+
+	return last, nil
+}
+
+// statePath returns the path of the state file for the given run, the run IDs are UUIDs and anything else is rejected
+// so that the IDs given by the users cannot point outside the run states directory.
+func (s *Store) statePath(runID string) (string, error) {
+	if !isValidRunID(runID) {
+		return "", errors.Errorf("invalid run ID '%s', the run IDs are UUIDs", runID)
+	}
+
+	return filepath.Join(s.config.RunStateDir(), runID+stateFileExtension), nil
+}
+
+func isValidRunID(runID string) bool {
+	_, err := uuid.Parse(runID)
+	return err == nil && len(runID) == 36
+}
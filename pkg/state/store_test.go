@@ -0,0 +1,133 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/datablast-analytics/blast/pkg/scheduler"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockConfigManager struct {
+	fs  afero.Fs
+	dir string
+}
+
+func (m *mockConfigManager) EnsureRunStateDirExists() error {
+	return m.fs.MkdirAll(m.dir, 0o755)
+}
+
+func (m *mockConfigManager) RunStateDir() string {
+	return m.dir
+}
+
+func newTestStore() *Store {
+	fs := afero.NewMemMapFs()
+	return NewStore(fs, &mockConfigManager{fs: fs, dir: "/home/.blast/runs"})
+}
+
+func TestStore_SaveAndLoad(t *testing.T) {
+	t.Parallel()
+
+	s := newTestStore()
+	state := &RunState{
+		ID:          "0b6f7c52-6a43-4c3e-9c55-1d2f3a4b5c6d",
+		Pipeline:    "my-pipeline",
+		Path:        "/path/to/pipeline",
+		Environment: "dev",
+		StartDate:   time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:     time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		Instances: map[string]scheduler.TaskInstanceStatus{
+			"task1":                   scheduler.Succeeded,
+			"task2":                   scheduler.Failed,
+			"task3":                   scheduler.UpstreamFailed,
+			"task1:col1:not_null":     scheduler.Succeeded,
+			"task2:col2:accepted_val": scheduler.Pending,
+		},
+	}
+
+	err := s.Save(state)
+	require.NoError(t, err)
+	assert.False(t, state.UpdatedAt.IsZero())
+
+	loaded, err := s.Load("0b6f7c52-6a43-4c3e-9c55-1d2f3a4b5c6d")
+	require.NoError(t, err)
+	assert.Equal(t, state.ID, loaded.ID)
+	assert.Equal(t, state.Pipeline, loaded.Pipeline)
+	assert.Equal(t, state.Path, loaded.Path)
+	assert.Equal(t, state.Environment, loaded.Environment)
+	assert.True(t, state.StartDate.Equal(loaded.StartDate))
+	assert.True(t, state.EndDate.Equal(loaded.EndDate))
+	assert.Equal(t, state.Instances, loaded.Instances)
+}
+
+func TestStore_LoadMissingRun(t *testing.T) {
+	t.Parallel()
+
+	s := newTestStore()
+	_, err := s.Load("7d3c9a10-2b4e-4f6a-8c1d-9e0f1a2b3c4d")
+	assert.Error(t, err)
+}
+
+func TestStore_RejectsInvalidRunIDs(t *testing.T) {
+	t.Parallel()
+
+	s := newTestStore()
+	for _, id := range []string{"", "run-1", "../../x", "../runs/0b6f7c52-6a43-4c3e-9c55-1d2f3a4b5c6d"} {
+		_, err := s.Load(id)
+		assert.EqualError(t, err, "invalid run ID '"+id+"', the run IDs are UUIDs")
+
+		err = s.Save(&RunState{ID: id})
+		assert.Error(t, err)
+	}
+
+	files, err := afero.ReadDir(s.fs, "/home/.blast")
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "runs", files[0].Name())
+}
+
+func TestStore_SaveLeavesNoTemporaryFiles(t *testing.T) {
+	t.Parallel()
+
+	s := newTestStore()
+	id := "0b6f7c52-6a43-4c3e-9c55-1d2f3a4b5c6d"
+	for _, status := range []scheduler.TaskInstanceStatus{scheduler.Pending, scheduler.Succeeded} {
+		require.NoError(t, s.Save(&RunState{ID: id, Instances: map[string]scheduler.TaskInstanceStatus{"task1": status}}))
+	}
+
+	files, err := afero.ReadDir(s.fs, "/home/.blast/runs")
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, id+".json", files[0].Name())
+
+	loaded, err := s.Load(id)
+	require.NoError(t, err)
+	assert.Equal(t, scheduler.Succeeded, loaded.Instances["task1"])
+}
+
+func TestStore_Last(t *testing.T) {
+	t.Parallel()
+
+	s := newTestStore()
+	_, err := s.Last()
+	assert.Error(t, err)
+
+	run1 := "11111111-1111-4111-8111-111111111111"
+	run2 := "22222222-2222-4222-8222-222222222222"
+	run3 := "33333333-3333-4333-8333-333333333333"
+	for _, id := range []string{run1, run2, run3} {
+		err = s.Save(&RunState{ID: id})
+		require.NoError(t, err)
+		time.Sleep(time.Millisecond)
+	}
+
+	err = s.Save(&RunState{ID: run2})
+	require.NoError(t, err)
+
+	last, err := s.Last()
+	require.NoError(t, err)
+	assert.Equal(t, run2, last.ID)
+}
@@ -14,6 +14,8 @@ const (
 	blastHomeDir       = ".blast"
 	homeDirPermissions = 0o755
 	virtualEnvsPath    = "virtualenvs"
+	runStatesPath      = "runs"
+	historyDBFile      = "history.db"
 )
 
 type ConfigManager struct {
@@ -55,6 +57,10 @@ func (c *ConfigManager) makePathUnderConfig(dirName string) string {
 	return filepath.Join(c.blastHomeDir, dirName)
 }
 
+func (c *ConfigManager) VirtualenvDir() string {
+	return c.makePathUnderConfig(virtualEnvsPath)
+}
+
 func (c *ConfigManager) MakeVirtualenvPath(dirName string) string {
 	return filepath.Join(c.blastHomeDir, virtualEnvsPath, dirName)
 }
@@ -78,3 +84,32 @@ func (c *ConfigManager) EnsureVirtualenvDirExists() error {
 
 	return nil
 }
+
+// HistoryDBPath returns the path of the run history database, the home directory needs to exist beforehand.
+func (c *ConfigManager) HistoryDBPath() string {
+	return c.makePathUnderConfig(historyDBFile)
+}
+
+func (c *ConfigManager) RunStateDir() string {
+	return c.makePathUnderConfig(runStatesPath)
+}
+
+func (c *ConfigManager) EnsureRunStateDirExists() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	err := c.EnsureHomeDirExists()
+	if err != nil {
+		return err
+	}
+
+	runStatePath := c.makePathUnderConfig(runStatesPath)
+	if !path.DirExists(c.fs, runStatePath) {
+		err = c.fs.MkdirAll(runStatePath, homeDirPermissions)
+		if err != nil {
+			return errors.Wrap(err, "failed to create run states directory under blast home")
+		}
+	}
+
+	return nil
+}
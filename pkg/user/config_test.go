@@ -56,3 +56,26 @@ func TestConfigManager_EnsureVirtualenvDirExists(t *testing.T) {
 	err = c.EnsureVirtualenvDirExists()
 	assert.NoError(t, err)
 }
+
+func TestConfigManager_EnsureRunStateDirExists(t *testing.T) {
+	t.Parallel()
+
+	homeDir, err := os.UserHomeDir()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, homeDir)
+
+	fs := afero.NewMemMapFs()
+	c := &ConfigManager{fs: fs}
+
+	err = c.EnsureRunStateDirExists()
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(homeDir, blastHomeDir, runStatesPath), c.RunStateDir())
+
+	fileInfo, err := fs.Stat(c.RunStateDir())
+	assert.NoError(t, err)
+	assert.True(t, fileInfo.IsDir())
+
+	// ensure repetitive calls are safe
+	err = c.EnsureRunStateDirExists()
+	assert.NoError(t, err)
+}